@@ -20,6 +20,7 @@ type client struct {
 	logRequests  bool
 	logResponses bool
 	serverName   string
+	authToken    string
 }
 
 // New returns a new API client.
@@ -43,3 +44,7 @@ func (c *client) LogRequests(enabled bool) {
 func (c *client) LogResponses(enabled bool) {
 	c.logResponses = enabled
 }
+
+func (c *client) SetAuthToken(token string) {
+	c.authToken = token
+}
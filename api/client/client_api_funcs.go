@@ -4,9 +4,11 @@ import (
 	"encoding/base64"
 	"fmt"
 	"io"
+	"net/url"
 	"strconv"
 
 	"github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/api/utils"
 )
 
 func (c *client) Root(ctx types.Context) ([]string, error) {
@@ -66,6 +68,17 @@ func (c *client) Services(
 	return reply, nil
 }
 
+func (c *client) ServicesReload(
+	ctx types.Context) (map[string]*types.ServiceInfo, error) {
+
+	reply := map[string]*types.ServiceInfo{}
+	if _, err := c.httpPost(
+		ctx, "/services?reload", nil, &reply); err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
 func (c *client) ServiceInspect(
 	ctx types.Context, name string) (*types.ServiceInfo, error) {
 
@@ -87,8 +100,11 @@ func (c *client) Volumes(
 	attachments types.VolumeAttachmentsTypes) (types.ServiceVolumeMap, error) {
 
 	reply := types.ServiceVolumeMap{}
-	url := fmt.Sprintf("/volumes?attachments=%v", attachments)
-	if _, err := c.httpGet(ctx, url, &reply); err != nil {
+	url := fmt.Sprintf("/volumes?attachments=%v&serviceErrors", attachments)
+	if err := c.getServiceResult(ctx, url, &reply); err != nil {
+		if _, ok := err.(*types.ErrPartialResult); ok {
+			return reply, err
+		}
 		return nil, err
 	}
 	return reply, nil
@@ -173,6 +189,34 @@ func (c *client) VolumeRemove(
 	return nil
 }
 
+func (c *client) VolumeResize(
+	ctx types.Context,
+	service, volumeID string,
+	request *types.VolumeResizeRequest) (*types.Volume, error) {
+
+	reply := types.Volume{}
+	if _, err := c.httpPost(ctx,
+		fmt.Sprintf("/volumes/%s/%s?resize", service, volumeID),
+		request, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+func (c *client) VolumeUpdate(
+	ctx types.Context,
+	service, volumeID string,
+	request *types.VolumeUpdateRequest) (*types.Volume, error) {
+
+	reply := types.Volume{}
+	if _, err := c.httpPatch(ctx,
+		fmt.Sprintf("/volumes/%s/%s", service, volumeID),
+		request, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
 func (c *client) VolumeAttach(
 	ctx types.Context,
 	service string,
@@ -248,7 +292,11 @@ func (c *client) Snapshots(
 	ctx types.Context) (types.ServiceSnapshotMap, error) {
 
 	reply := types.ServiceSnapshotMap{}
-	if _, err := c.httpGet(ctx, "/snapshots", &reply); err != nil {
+	url := "/snapshots?serviceErrors"
+	if err := c.getServiceResult(ctx, url, &reply); err != nil {
+		if _, ok := err.(*types.ErrPartialResult); ok {
+			return reply, err
+		}
 		return nil, err
 	}
 	return reply, nil
@@ -303,6 +351,95 @@ func (c *client) SnapshotCopy(
 	return &reply, nil
 }
 
+func (c *client) Tasks(
+	ctx types.Context,
+	opts *types.TasksOpts) (map[string]*types.Task, error) {
+
+	q := url.Values{}
+	if opts != nil {
+		if opts.State != "" {
+			q.Set("state", string(opts.State))
+		}
+		if opts.User != "" {
+			q.Set("user", opts.User)
+		}
+		if opts.Service != "" {
+			q.Set("service", opts.Service)
+		}
+		if opts.Since > 0 {
+			q.Set("since", strconv.FormatInt(opts.Since, 10))
+		}
+		if opts.Until > 0 {
+			q.Set("until", strconv.FormatInt(opts.Until, 10))
+		}
+	}
+
+	path := "/tasks"
+	if len(q) > 0 {
+		path = fmt.Sprintf("%s?%s", path, q.Encode())
+	}
+
+	reply := map[string]*types.Task{}
+	if _, err := c.httpGet(ctx, path, &reply); err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+func (c *client) Audit(
+	ctx types.Context,
+	opts *types.AuditOpts) ([]*types.AuditRecord, error) {
+
+	q := url.Values{}
+	if opts != nil {
+		if opts.Service != "" {
+			q.Set("service", opts.Service)
+		}
+		if opts.Since > 0 {
+			q.Set("since", strconv.FormatInt(opts.Since, 10))
+		}
+		if opts.Until > 0 {
+			q.Set("until", strconv.FormatInt(opts.Until, 10))
+		}
+		if opts.Limit > 0 {
+			q.Set("limit", strconv.Itoa(opts.Limit))
+		}
+	}
+
+	path := "/audit"
+	if len(q) > 0 {
+		path = fmt.Sprintf("%s?%s", path, q.Encode())
+	}
+
+	reply := []*types.AuditRecord{}
+	if _, err := c.httpGet(ctx, path, &reply); err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+func (c *client) TaskInspect(
+	ctx types.Context, taskID int) (*types.Task, error) {
+
+	reply := types.Task{}
+	if _, err := c.httpGet(ctx,
+		fmt.Sprintf("/tasks/%d", taskID), &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+func (c *client) TaskCancel(
+	ctx types.Context, taskID int) (*types.Task, error) {
+
+	reply := types.Task{}
+	if _, err := c.httpDelete(ctx,
+		fmt.Sprintf("/tasks/%d", taskID), &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
 func (c *client) Executors(
 	ctx types.Context) (map[string]*types.ExecutorInfo, error) {
 
@@ -348,3 +485,21 @@ func (c *client) ExecutorGet(
 	}
 	return res.Body, nil
 }
+
+// getServiceResult gets the resources of multiple services and decodes them
+// into reply. The request's URL must include the serviceErrors query
+// parameter so that the errors of the services that failed to respond are
+// returned alongside the resources. An ErrPartialResult error is returned
+// with the errors when any of the services failed to respond.
+func (c *client) getServiceResult(
+	ctx types.Context, url string, reply interface{}) error {
+
+	res := &types.ServiceResult{Result: reply}
+	if _, err := c.httpGet(ctx, url, res); err != nil {
+		return err
+	}
+	if len(res.Errors) == 0 {
+		return nil
+	}
+	return utils.NewPartialResultError(res.Errors)
+}
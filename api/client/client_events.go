@@ -0,0 +1,109 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/akutz/goof"
+	"golang.org/x/net/context/ctxhttp"
+
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+const maxEventSize = 1024 * 1024
+
+func (c *client) Events(
+	ctx types.Context,
+	services ...string) (<-chan *types.Event, error) {
+
+	q := url.Values{}
+	for _, s := range services {
+		q.Add("service", s)
+	}
+
+	path := "/events"
+	if len(q) > 0 {
+		path = fmt.Sprintf("%s?%s", path, q.Encode())
+	}
+
+	req, ctx, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	c.logRequest(req)
+
+	res, err := ctxhttp.Do(ctx, &c.Client, req)
+	if err != nil {
+		return nil, err
+	}
+	c.setServerName(res)
+
+	c.logResponse(res)
+
+	if res.StatusCode > 299 {
+		defer res.Body.Close()
+		httpErr, err := goof.DecodeHTTPError(res.Body)
+		if err != nil {
+			return nil, goof.WithField("status", res.StatusCode, "http error")
+		}
+		return nil, httpErr
+	}
+
+	events := make(chan *types.Event)
+
+	go func() {
+		defer close(events)
+		defer res.Body.Close()
+
+		scanner := bufio.NewScanner(res.Body)
+		scanner.Buffer(make([]byte, bufio.MaxScanTokenSize), maxEventSize)
+
+		data := &bytes.Buffer{}
+
+		for scanner.Scan() {
+			line := scanner.Text()
+
+			// lines that begin with "data:" hold the event's payload
+			if strings.HasPrefix(line, "data:") {
+				if data.Len() > 0 {
+					data.WriteByte('\n')
+				}
+				data.WriteString(
+					strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+				continue
+			}
+
+			// an empty line dispatches the event
+			if line != "" || data.Len() == 0 {
+				continue
+			}
+
+			ev := &types.Event{}
+			err := json.Unmarshal(data.Bytes(), ev)
+			data.Reset()
+			if err != nil {
+				ctx.WithError(err).Error("error decoding event")
+				continue
+			}
+
+			select {
+			case events <- ev:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		if err := scanner.Err(); err != nil && ctx.Err() == nil {
+			ctx.WithError(err).Error("error reading event stream")
+		}
+	}()
+
+	return events, nil
+}
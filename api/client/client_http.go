@@ -40,16 +40,69 @@ func (c *client) httpDo(
 	method, path string,
 	payload, reply interface{}) (*http.Response, error) {
 
-	reqBody, err := encPayload(payload)
+	req, ctx, err := c.newRequest(ctx, method, path, payload)
 	if err != nil {
 		return nil, err
 	}
 
-	url := fmt.Sprintf("http://%s%s", c.host, path)
-	req, err := http.NewRequest(method, url, reqBody)
+	c.logRequest(req)
+
+	res, err := ctxhttp.Do(ctx, &c.Client, req)
 	if err != nil {
 		return nil, err
 	}
+	defer c.setServerName(res)
+
+	c.logResponse(res)
+
+	if res.StatusCode > 299 {
+		httpErr, err := goof.DecodeHTTPError(res.Body)
+		if err != nil {
+			return res, goof.WithField("status", res.StatusCode, "http error")
+		}
+		return res, httpErr
+	}
+
+	// the task of an asynchronous request is returned in place of the reply
+	if res.StatusCode == http.StatusAccepted {
+		if task, ok := asyncTask(ctx); ok {
+			if err := decRes(res.Body, task); err != nil {
+				return nil, err
+			}
+			return res, nil
+		}
+	}
+
+	if req.Method != http.MethodHead && reply != nil {
+		if err := decRes(res.Body, reply); err != nil {
+			return nil, err
+		}
+	}
+
+	if page, ok := pageOpts(ctx); ok {
+		page.Next = res.Header.Get(types.NextPageHeader)
+	}
+
+	return res, nil
+}
+
+// newRequest returns a new HTTP request with the headers derived from the
+// context, as well as the context with which the request should be sent.
+func (c *client) newRequest(
+	ctx types.Context,
+	method, path string,
+	payload interface{}) (*http.Request, types.Context, error) {
+
+	reqBody, err := encPayload(payload)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	url := fmt.Sprintf("http://%s%s", c.host, withQuery(ctx, path))
+	req, err := http.NewRequest(method, url, reqBody)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	ctx = context.RequireTX(ctx)
 	tx := context.MustTransaction(ctx)
@@ -119,31 +172,12 @@ func (c *client) httpDo(
 		}
 	}
 
-	c.logRequest(req)
-
-	res, err := ctxhttp.Do(ctx, &c.Client, req)
-	if err != nil {
-		return nil, err
-	}
-	defer c.setServerName(res)
-
-	c.logResponse(res)
-
-	if res.StatusCode > 299 {
-		httpErr, err := goof.DecodeHTTPError(res.Body)
-		if err != nil {
-			return res, goof.WithField("status", res.StatusCode, "http error")
-		}
-		return res, httpErr
-	}
-
-	if req.Method != http.MethodHead && reply != nil {
-		if err := decRes(res.Body, reply); err != nil {
-			return nil, err
-		}
+	if c.authToken != "" {
+		req.Header.Set(
+			types.AuthorizationHeader, fmt.Sprintf("Bearer %s", c.authToken))
 	}
 
-	return res, nil
+	return req, ctx, nil
 }
 
 func (c *client) setServerName(res *http.Response) {
@@ -182,6 +216,15 @@ func (c *client) httpDelete(
 	return c.httpDo(ctx, "DELETE", path, nil, reply)
 }
 
+func (c *client) httpPatch(
+	ctx types.Context,
+	path string,
+	payload interface{},
+	reply interface{}) (*http.Response, error) {
+
+	return c.httpDo(ctx, "PATCH", path, payload, reply)
+}
+
 func encPayload(payload interface{}) (io.Reader, error) {
 	if payload == nil {
 		return nil, nil
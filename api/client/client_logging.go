@@ -9,6 +9,8 @@ import (
 
 	log "github.com/Sirupsen/logrus"
 	"github.com/akutz/gotil"
+
+	"github.com/codedellemc/libstorage/api/types"
 )
 
 func (c *client) logRequest(req *http.Request) {
@@ -24,6 +26,12 @@ func (c *client) logRequest(req *http.Request) {
 	fmt.Fprint(w, "HTTP REQUEST (CLIENT)")
 	fmt.Fprintln(w, " -------------------------")
 
+	// redact the token while the request is dumped
+	if auth := req.Header.Get(types.AuthorizationHeader); auth != "" {
+		req.Header.Set(types.AuthorizationHeader, "******")
+		defer req.Header.Set(types.AuthorizationHeader, auth)
+	}
+
 	buf, err := httputil.DumpRequest(req, true)
 	if err != nil {
 		return
@@ -46,9 +54,11 @@ func (c *client) logResponse(res *http.Response) {
 	fmt.Fprint(w, "HTTP RESPONSE (CLIENT)")
 	fmt.Fprintln(w, " -------------------------")
 
+	contentType := res.Header.Get("Content-Type")
 	buf, err := httputil.DumpResponse(
 		res,
-		res.Header.Get("Content-Type") != "application/octet-stream")
+		contentType != "application/octet-stream" &&
+			contentType != "text/event-stream")
 	if err != nil {
 		return
 	}
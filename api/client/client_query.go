@@ -0,0 +1,87 @@
+package client
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+type queryKey int
+
+const (
+	filterQueryKey queryKey = iota
+	asyncQueryKey
+	pageQueryKey
+)
+
+// WithFilter returns a context with which the requests to the routes that
+// list volumes or snapshots return only the resources that match the
+// LDAP-style filter.
+func WithFilter(ctx types.Context, filter string) types.Context {
+	return ctx.WithValue(filterQueryKey, filter)
+}
+
+// WithAsync returns a context with which the requests to the routes that
+// execute a task return as soon as the task is queued rather than when the
+// task is complete. The queued task is decoded into the returned task, and
+// the reply of the function that sent the request is left empty.
+func WithAsync(ctx types.Context) (types.Context, *types.Task) {
+	task := &types.Task{}
+	return ctx.WithValue(asyncQueryKey, task), task
+}
+
+// asyncTask returns the task into which the queued task of an asynchronous
+// request is decoded.
+func asyncTask(ctx types.Context) (*types.Task, bool) {
+	task, ok := ctx.Value(asyncQueryKey).(*types.Task)
+	return task, ok
+}
+
+// WithPage returns a context with which the requests to the routes that
+// list volumes or snapshots return the page described by the page's Limit,
+// Sort, and Token fields. The page's Next field is set to the token of the
+// next page when a request completes, and the next page is requested by
+// setting the page's Token to its Next.
+func WithPage(ctx types.Context, page *types.Page) types.Context {
+	return ctx.WithValue(pageQueryKey, page)
+}
+
+// pageOpts returns the page into which the token of the next page is set.
+func pageOpts(ctx types.Context) (*types.Page, bool) {
+	page, ok := ctx.Value(pageQueryKey).(*types.Page)
+	return page, ok && page != nil
+}
+
+// withQuery returns the path with the query parameters derived from the
+// context.
+func withQuery(ctx types.Context, path string) string {
+	q := url.Values{}
+	if filter, ok := ctx.Value(filterQueryKey).(string); ok && filter != "" {
+		q.Set("filter", filter)
+	}
+	if _, ok := asyncTask(ctx); ok {
+		q.Set("async", "true")
+	}
+	if page, ok := pageOpts(ctx); ok {
+		if page.Limit > 0 {
+			q.Set("limit", strconv.Itoa(page.Limit))
+		}
+		if page.Sort != "" {
+			q.Set("sort", page.Sort)
+		}
+		if page.Token != "" {
+			q.Set("next", page.Token)
+		}
+	}
+	if len(q) == 0 {
+		return path
+	}
+
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	return path + sep + q.Encode()
+}
@@ -78,6 +78,19 @@ func Background() types.Context {
 	return New(nil)
 }
 
+// WithCancel returns a copy of parent with a new Done channel. The returned
+// context's Done channel is closed when the returned cancel function is called
+// or when the parent context's Done channel is closed, whichever happens
+// first.
+func WithCancel(parent types.Context) (types.Context, context.CancelFunc) {
+	cctx, cancel := context.WithCancel(parent)
+	ctx := newContext(cctx, nil, nil, nil, nil)
+	if pctx, ok := parent.(*lsc); ok {
+		ctx.logger = pctx.logger
+	}
+	return ctx, cancel
+}
+
 // WithRequestRoute returns a new context with the injected *http.Request
 // and Route.
 func WithRequestRoute(
@@ -368,6 +381,29 @@ func Route(ctx context.Context) (types.Route, bool) {
 	return v, ok
 }
 
+// AuthIdentity returns the context's authenticated identity. This value is
+// only valid on the server when the auth handler is enabled.
+func AuthIdentity(ctx context.Context) (*types.AuthIdentity, bool) {
+	v, ok := ctx.Value(AuthIdentityKey).(*types.AuthIdentity)
+	return v, ok
+}
+
+// Authorized returns a flag indicating whether the context's authenticated
+// identity is granted at least the specified role for the specified service.
+// The flag is always true for a context without an authenticated identity,
+// such as when the auth handler is disabled.
+func Authorized(
+	ctx context.Context, service string, role types.AuthRole) bool {
+
+	identity, ok := AuthIdentity(ctx)
+	return !ok || identity.Grants.Role(service) >= role
+}
+
+// User returns the context's user name.
+func User(ctx context.Context) (string, bool) {
+	return stringValue(ctx, UserKey)
+}
+
 // Server returns the context's server name. This value is valid on both the
 // client and the server.
 func Server(ctx context.Context) (string, bool) {
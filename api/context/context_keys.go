@@ -30,7 +30,8 @@ const (
 	// value that maps all drivers to their instance IDs.
 	AllLocalDevicesKey
 
-	// AdminTokenKey is the key for the server's admin token.
+	// AdminTokenKey is the key for the types.AdminTokenValidator that
+	// validates the tokens for the server's admin routes.
 	AdminTokenKey
 
 	// SessionKey is the key for the storage driver's session.
@@ -79,6 +80,10 @@ const (
 	// TLSKey is a context key.
 	TLSKey
 
+	// AuthIdentityKey is the key for the *types.AuthIdentity value of an
+	// authenticated request.
+	AuthIdentityKey
+
 	// keyEOF should always be the final key
 	keyEOF
 )
@@ -0,0 +1,216 @@
+/*
+Package csi serves libStorage as a Container Storage Interface (CSI) plug-in.
+
+The controller plug-in runs inside a libStorage server and performs the CSI
+controller operations with the storage driver of one of the server's storage
+services. The operations are executed as tasks of the storage service, just
+as the operations requested by way of the libStorage API are.
+
+The node plug-in runs alongside a libStorage client and stages and publishes
+volumes on the node with the client's executor and OS driver.
+
+Both plug-ins serve the CSI identity service.
+*/
+package csi
+
+import (
+	"net"
+	"os"
+	"path"
+	"sync"
+
+	gofig "github.com/akutz/gofig/types"
+	"github.com/akutz/goof"
+	"github.com/akutz/gotil"
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+const (
+	// PublishContextAttachToken is the key of the attach token in the publish
+	// context that the controller returns when it publishes a volume.
+	PublishContextAttachToken = "attachToken"
+
+	// PublishContextDeviceName is the key of the device name in the publish
+	// context that the controller returns when it publishes a volume.
+	PublishContextDeviceName = "deviceName"
+
+	// gib is the number of bytes in a GiB, the unit of a libStorage volume's
+	// size.
+	gib int64 = 1024 * 1024 * 1024
+)
+
+// Server is a CSI plug-in's gRPC server.
+type Server struct {
+	sync.Mutex
+	ctx    types.Context
+	addr   string
+	srv    *grpc.Server
+	closed bool
+}
+
+// ServeController starts serving the CSI identity and controller services at
+// the configured controller endpoint. The context must be the context of the
+// libStorage server whose storage service backs the plug-in. The returned
+// channel receives an error if the server stops unexpectedly and is closed
+// when the server stops.
+func ServeController(
+	ctx types.Context,
+	config gofig.Config) (*Server, <-chan error, error) {
+
+	service := getService(config)
+	if service == "" {
+		return nil, nil, goof.WithField(
+			"configKey", types.ConfigCSIService, "missing csi service")
+	}
+
+	return serve(
+		ctx,
+		config.GetString(types.ConfigCSIControllerEndpoint),
+		func(s *grpc.Server) {
+			csi.RegisterIdentityServer(s, newIdentity(config))
+			csi.RegisterControllerServer(s, newController(ctx, service))
+		})
+}
+
+// ServeNode starts serving the CSI identity and node services at the
+// configured node endpoint. The volumes are staged and published with the
+// client's executor and OS driver. The returned channel receives an error if
+// the server stops unexpectedly and is closed when the server stops.
+func ServeNode(
+	ctx types.Context,
+	client types.Client,
+	config gofig.Config) (*Server, <-chan error, error) {
+
+	service := getService(config)
+	if service == "" {
+		return nil, nil, goof.WithField(
+			"configKey", types.ConfigCSIService, "missing csi service")
+	}
+	if client.OS() == nil || client.Executor() == nil {
+		return nil, nil, goof.New("client has no os driver or executor")
+	}
+
+	return serve(
+		ctx,
+		config.GetString(types.ConfigCSINodeEndpoint),
+		func(s *grpc.Server) {
+			csi.RegisterIdentityServer(s, newIdentity(config))
+			csi.RegisterNodeServer(s, newNode(ctx, client, config, service))
+		})
+}
+
+func serve(
+	ctx types.Context,
+	endpoint string,
+	register func(s *grpc.Server)) (*Server, <-chan error, error) {
+
+	if endpoint == "" {
+		return nil, nil, goof.New("missing csi endpoint")
+	}
+
+	proto, addr, err := gotil.ParseAddress(endpoint)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the plug-in's services are not authenticated, authorized, or audited
+	// as the libStorage API is, so access to the plug-in is restricted to
+	// the local users permitted to access the socket file
+	if proto != "unix" {
+		return nil, nil, goof.WithField(
+			"endpoint", endpoint, "csi endpoint must be a unix socket")
+	}
+
+	if err := os.MkdirAll(path.Dir(addr), 0755); err != nil {
+		return nil, nil, goof.WithFieldE(
+			"path", addr, "error creating csi socket dir", err)
+	}
+	// a socket file that remains from a previous process prevents listening
+	os.RemoveAll(addr)
+
+	l, err := net.Listen(proto, addr)
+	if err != nil {
+		return nil, nil, goof.WithFieldE(
+			"endpoint", endpoint, "error listening on csi endpoint", err)
+	}
+
+	s := &Server{
+		ctx:  ctx,
+		addr: addr,
+		srv:  grpc.NewServer(),
+	}
+	register(s.srv)
+
+	errs := make(chan error, 1)
+	go func() {
+		defer close(errs)
+		if err := s.srv.Serve(l); err != nil {
+			errs <- err
+		}
+	}()
+
+	ctx.WithField("endpoint", endpoint).Info("serving csi plug-in")
+	return s, errs, nil
+}
+
+// Addr returns the address on which the server is listening.
+func (s *Server) Addr() string {
+	return s.addr
+}
+
+// Close stops the server.
+func (s *Server) Close() error {
+	s.Lock()
+	defer s.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	s.srv.Stop()
+	os.RemoveAll(s.addr)
+	s.ctx.WithField("address", s.addr).Info("closed csi plug-in")
+	return nil
+}
+
+// getService returns the name of the storage service that backs the plug-in.
+func getService(config gofig.Config) string {
+	if service := config.GetString(types.ConfigCSIService); service != "" {
+		return service
+	}
+	return config.GetString(types.ConfigService)
+}
+
+// toStatusError returns a gRPC status error with the code that corresponds to
+// the type of the provided error.
+func toStatusError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	switch err.(type) {
+	case *types.ErrNotFound:
+		return status.Error(codes.NotFound, err.Error())
+	case *types.ErrUnauthorized:
+		return status.Error(codes.Unauthenticated, err.Error())
+	case *types.ErrBadAdminToken, *types.ErrForbidden:
+		return status.Error(codes.PermissionDenied, err.Error())
+	case *types.ErrPolicyViolation:
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if err == types.ErrNotImplemented {
+		return status.Error(codes.Unimplemented, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+func isNotFound(err error) bool {
+	_, ok := err.(*types.ErrNotFound)
+	return ok
+}
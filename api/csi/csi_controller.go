@@ -0,0 +1,663 @@
+package csi
+
+import (
+	"strconv"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	gocontext "golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/server/handlers"
+	"github.com/codedellemc/libstorage/api/server/services"
+	"github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/api/utils"
+)
+
+type controller struct {
+	ctx     types.Context
+	service string
+}
+
+func newController(ctx types.Context, service string) *controller {
+	return &controller{
+		ctx:     ctx.WithValue(context.ServiceKey, service),
+		service: service,
+	}
+}
+
+// driverFunc is a function that is executed with the storage driver of the
+// controller's storage service.
+type driverFunc func(
+	ctx types.Context, d types.StorageDriver) (interface{}, error)
+
+// run executes the function as a task of the controller's storage service
+// and waits for the task to complete or the request to be canceled, in which
+// case the task is cancelled as well. The instance ID, if provided, is the
+// instance on whose behalf the function is executed.
+func (c *controller) run(
+	goCtx gocontext.Context,
+	iid *types.InstanceID,
+	f driverFunc) (interface{}, error) {
+
+	svc := services.GetStorageService(c.ctx, c.service)
+	if svc == nil {
+		return nil, status.Errorf(
+			codes.Unavailable, "unknown storage service: %s", c.service)
+	}
+
+	ctx := c.ctx
+	if iid != nil {
+		ctx = ctx.WithValue(context.InstanceIDKey, iid)
+	}
+
+	run := func(
+		ctx types.Context,
+		svc types.StorageService) (interface{}, error) {
+
+		ctx = context.WithStorageService(ctx, svc)
+
+		var err error
+		if ctx, err = context.WithStorageSession(ctx); err != nil {
+			return nil, err
+		}
+
+		return f(ctx, svc.Driver())
+	}
+
+	task := svc.TaskExecute(ctx, run, nil)
+
+	select {
+	case <-services.TaskWaitC(ctx, task.ID):
+		if task.Error != nil {
+			return nil, toStatusError(task.Error)
+		}
+		return task.Result, nil
+	case <-goCtx.Done():
+		// the task is cancelled so that the operation is not performed on
+		// behalf of a request that is no longer waiting for its result
+		if _, err := services.TaskCancel(ctx, task.ID); err != nil {
+			ctx.WithError(err).Debug("error cancelling csi task")
+		}
+		return nil, status.FromContextError(goCtx.Err()).Err()
+	}
+}
+
+func (c *controller) CreateVolume(
+	goCtx gocontext.Context,
+	req *csi.CreateVolumeRequest) (*csi.CreateVolumeResponse, error) {
+
+	if req.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing name")
+	}
+	if len(req.VolumeCapabilities) == 0 {
+		return nil, status.Error(
+			codes.InvalidArgument, "missing volume capabilities")
+	}
+	if err := validateCapabilities(req.VolumeCapabilities); err != nil {
+		return nil, err
+	}
+
+	size, err := getSize(req.CapacityRange)
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshotID string
+	if src := req.VolumeContentSource; src != nil {
+		if src.GetSnapshot() == nil {
+			return nil, status.Error(
+				codes.InvalidArgument, "unsupported volume content source")
+		}
+		snapshotID = src.GetSnapshot().SnapshotId
+	}
+
+	opts := &types.VolumeCreateOpts{
+		Opts: utils.NewStoreWithVars(req.Parameters),
+	}
+	if size > 0 {
+		opts.Size = &size
+	}
+	if v, ok := req.Parameters["type"]; ok {
+		opts.Type = &v
+	}
+	if v, ok := req.Parameters["availabilityZone"]; ok {
+		opts.AvailabilityZone = &v
+	}
+	if v, ok := req.Parameters["iops"]; ok {
+		iops, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, status.Errorf(
+				codes.InvalidArgument, "invalid iops: %s", v)
+		}
+		opts.IOPS = &iops
+	}
+
+	res, err := c.run(goCtx, nil, func(
+		ctx types.Context, d types.StorageDriver) (interface{}, error) {
+
+		// a volume with the requested name satisfies the request if it is
+		// large enough since the request may be a retry
+		vols, err := d.Volumes(ctx, &types.VolumesOpts{Opts: utils.NewStore()})
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range vols {
+			if v.Name != req.Name {
+				continue
+			}
+			if size > 0 && v.Size < size {
+				return nil, status.Errorf(codes.AlreadyExists,
+					"volume %s exists with size %dGiB", v.Name, v.Size)
+			}
+			return v, nil
+		}
+
+		// the service's policies are checked by the task since the request
+		// does not pass through the HTTP server's policy handler
+		store := utils.NewStore()
+		if snapshotID != "" {
+			store.Set("snapshotID", snapshotID)
+		}
+		err = handlers.CheckVolumeCreatePolicy(
+			ctx, context.MustService(ctx), &types.VolumeCreateRequest{
+				Name:             req.Name,
+				AvailabilityZone: opts.AvailabilityZone,
+				IOPS:             opts.IOPS,
+				Size:             opts.Size,
+				Type:             opts.Type,
+			}, store)
+		if err != nil {
+			return nil, err
+		}
+
+		if snapshotID != "" {
+			return d.VolumeCreateFromSnapshot(
+				ctx, snapshotID, req.Name, opts)
+		}
+		return d.VolumeCreate(ctx, req.Name, opts)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	v := res.(*types.Volume)
+	cv := toCSIVolume(v)
+	if snapshotID != "" {
+		cv.ContentSource = req.VolumeContentSource
+	}
+	return &csi.CreateVolumeResponse{Volume: cv}, nil
+}
+
+func (c *controller) DeleteVolume(
+	goCtx gocontext.Context,
+	req *csi.DeleteVolumeRequest) (*csi.DeleteVolumeResponse, error) {
+
+	if req.VolumeId == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing volume id")
+	}
+
+	_, err := c.run(goCtx, nil, func(
+		ctx types.Context, d types.StorageDriver) (interface{}, error) {
+
+		err := d.VolumeRemove(ctx, req.VolumeId, utils.NewStore())
+		if isNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &csi.DeleteVolumeResponse{}, nil
+}
+
+func (c *controller) ControllerPublishVolume(
+	goCtx gocontext.Context,
+	req *csi.ControllerPublishVolumeRequest) (
+	*csi.ControllerPublishVolumeResponse, error) {
+
+	if req.VolumeId == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing volume id")
+	}
+	if req.VolumeCapability == nil {
+		return nil, status.Error(
+			codes.InvalidArgument, "missing volume capability")
+	}
+	err := validateCapabilities(
+		[]*csi.VolumeCapability{req.VolumeCapability})
+	if err != nil {
+		return nil, err
+	}
+	iid, err := parseNodeID(req.NodeId)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.run(goCtx, iid, func(
+		ctx types.Context, d types.StorageDriver) (interface{}, error) {
+
+		v, err := d.VolumeInspect(ctx, req.VolumeId,
+			&types.VolumeInspectOpts{
+				Attachments: types.VolAttReq,
+				Opts:        utils.NewStore(),
+			})
+		if err != nil {
+			return nil, err
+		}
+
+		// a volume that is already attached to the node has been published
+		if getAttachment(v, iid) != nil {
+			return &types.VolumeAttachResponse{Volume: v}, nil
+		}
+
+		v, token, err := d.VolumeAttach(ctx, req.VolumeId,
+			&types.VolumeAttachOpts{Opts: utils.NewStore()})
+		if err != nil {
+			return nil, err
+		}
+		return &types.VolumeAttachResponse{
+			Volume:      v,
+			AttachToken: token,
+		}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ar := res.(*types.VolumeAttachResponse)
+	pc := map[string]string{}
+	if ar.AttachToken != "" {
+		pc[PublishContextAttachToken] = ar.AttachToken
+	}
+	if att := getAttachment(ar.Volume, iid); att != nil &&
+		att.DeviceName != "" {
+		pc[PublishContextDeviceName] = att.DeviceName
+	}
+
+	return &csi.ControllerPublishVolumeResponse{PublishContext: pc}, nil
+}
+
+func (c *controller) ControllerUnpublishVolume(
+	goCtx gocontext.Context,
+	req *csi.ControllerUnpublishVolumeRequest) (
+	*csi.ControllerUnpublishVolumeResponse, error) {
+
+	if req.VolumeId == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing volume id")
+	}
+
+	// the volume is unpublished from all nodes if no node is specified
+	var iid *types.InstanceID
+	if req.NodeId != "" {
+		var err error
+		if iid, err = parseNodeID(req.NodeId); err != nil {
+			return nil, err
+		}
+	}
+
+	_, err := c.run(goCtx, nil, func(
+		ctx types.Context, d types.StorageDriver) (interface{}, error) {
+
+		v, err := d.VolumeInspect(ctx, req.VolumeId,
+			&types.VolumeInspectOpts{
+				Attachments: types.VolAttReq,
+				Opts:        utils.NewStore(),
+			})
+		if isNotFound(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for _, a := range v.Attachments {
+			if a.InstanceID == nil {
+				continue
+			}
+			if iid != nil && a.InstanceID.ID != iid.ID {
+				continue
+			}
+			actx := ctx.WithValue(context.InstanceIDKey, a.InstanceID)
+			if _, err := d.VolumeDetach(actx, req.VolumeId,
+				&types.VolumeDetachOpts{Opts: utils.NewStore()}); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &csi.ControllerUnpublishVolumeResponse{}, nil
+}
+
+func (c *controller) ValidateVolumeCapabilities(
+	goCtx gocontext.Context,
+	req *csi.ValidateVolumeCapabilitiesRequest) (
+	*csi.ValidateVolumeCapabilitiesResponse, error) {
+
+	if req.VolumeId == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing volume id")
+	}
+	if len(req.VolumeCapabilities) == 0 {
+		return nil, status.Error(
+			codes.InvalidArgument, "missing volume capabilities")
+	}
+
+	_, err := c.run(goCtx, nil, func(
+		ctx types.Context, d types.StorageDriver) (interface{}, error) {
+		return d.VolumeInspect(ctx, req.VolumeId,
+			&types.VolumeInspectOpts{Opts: utils.NewStore()})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateCapabilities(req.VolumeCapabilities); err != nil {
+		return &csi.ValidateVolumeCapabilitiesResponse{
+			Message: status.Convert(err).Message(),
+		}, nil
+	}
+
+	return &csi.ValidateVolumeCapabilitiesResponse{
+		Confirmed: &csi.ValidateVolumeCapabilitiesResponse_Confirmed{
+			VolumeContext:      req.VolumeContext,
+			VolumeCapabilities: req.VolumeCapabilities,
+			Parameters:         req.Parameters,
+		},
+	}, nil
+}
+
+func (c *controller) ListVolumes(
+	goCtx gocontext.Context,
+	req *csi.ListVolumesRequest) (*csi.ListVolumesResponse, error) {
+
+	res, err := c.run(goCtx, nil, func(
+		ctx types.Context, d types.StorageDriver) (interface{}, error) {
+		return d.Volumes(ctx, &types.VolumesOpts{Opts: utils.NewStore()})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	vols := res.([]*types.Volume)
+	start, end, next, err := getPage(
+		len(vols), req.MaxEntries, req.StartingToken)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []*csi.ListVolumesResponse_Entry{}
+	for _, v := range vols[start:end] {
+		entries = append(entries,
+			&csi.ListVolumesResponse_Entry{Volume: toCSIVolume(v)})
+	}
+
+	return &csi.ListVolumesResponse{Entries: entries, NextToken: next}, nil
+}
+
+func (c *controller) GetCapacity(
+	goCtx gocontext.Context,
+	req *csi.GetCapacityRequest) (*csi.GetCapacityResponse, error) {
+
+	return nil, status.Error(codes.Unimplemented, "get capacity")
+}
+
+func (c *controller) ControllerGetCapabilities(
+	goCtx gocontext.Context,
+	req *csi.ControllerGetCapabilitiesRequest) (
+	*csi.ControllerGetCapabilitiesResponse, error) {
+
+	caps := []*csi.ControllerServiceCapability{}
+	for _, t := range []csi.ControllerServiceCapability_RPC_Type{
+		csi.ControllerServiceCapability_RPC_CREATE_DELETE_VOLUME,
+		csi.ControllerServiceCapability_RPC_PUBLISH_UNPUBLISH_VOLUME,
+		csi.ControllerServiceCapability_RPC_LIST_VOLUMES,
+		csi.ControllerServiceCapability_RPC_CREATE_DELETE_SNAPSHOT,
+		csi.ControllerServiceCapability_RPC_LIST_SNAPSHOTS,
+	} {
+		caps = append(caps, &csi.ControllerServiceCapability{
+			Type: &csi.ControllerServiceCapability_Rpc{
+				Rpc: &csi.ControllerServiceCapability_RPC{Type: t},
+			},
+		})
+	}
+
+	return &csi.ControllerGetCapabilitiesResponse{Capabilities: caps}, nil
+}
+
+func (c *controller) CreateSnapshot(
+	goCtx gocontext.Context,
+	req *csi.CreateSnapshotRequest) (*csi.CreateSnapshotResponse, error) {
+
+	if req.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing name")
+	}
+	if req.SourceVolumeId == "" {
+		return nil, status.Error(
+			codes.InvalidArgument, "missing source volume id")
+	}
+
+	res, err := c.run(goCtx, nil, func(
+		ctx types.Context, d types.StorageDriver) (interface{}, error) {
+
+		// a snapshot with the requested name satisfies the request if it is
+		// of the requested volume since the request may be a retry
+		snaps, err := d.Snapshots(ctx, utils.NewStore())
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range snaps {
+			if s.Name != req.Name {
+				continue
+			}
+			if s.VolumeID != req.SourceVolumeId {
+				return nil, status.Errorf(codes.AlreadyExists,
+					"snapshot %s exists of volume %s", s.Name, s.VolumeID)
+			}
+			return s, nil
+		}
+
+		return d.VolumeSnapshot(
+			ctx, req.SourceVolumeId, req.Name, utils.NewStore())
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &csi.CreateSnapshotResponse{
+		Snapshot: toCSISnapshot(res.(*types.Snapshot)),
+	}, nil
+}
+
+func (c *controller) DeleteSnapshot(
+	goCtx gocontext.Context,
+	req *csi.DeleteSnapshotRequest) (*csi.DeleteSnapshotResponse, error) {
+
+	if req.SnapshotId == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing snapshot id")
+	}
+
+	_, err := c.run(goCtx, nil, func(
+		ctx types.Context, d types.StorageDriver) (interface{}, error) {
+
+		err := d.SnapshotRemove(ctx, req.SnapshotId, utils.NewStore())
+		if isNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &csi.DeleteSnapshotResponse{}, nil
+}
+
+func (c *controller) ListSnapshots(
+	goCtx gocontext.Context,
+	req *csi.ListSnapshotsRequest) (*csi.ListSnapshotsResponse, error) {
+
+	res, err := c.run(goCtx, nil, func(
+		ctx types.Context, d types.StorageDriver) (interface{}, error) {
+		return d.Snapshots(ctx, utils.NewStore())
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	snaps := []*types.Snapshot{}
+	for _, s := range res.([]*types.Snapshot) {
+		if req.SnapshotId != "" && s.ID != req.SnapshotId {
+			continue
+		}
+		if req.SourceVolumeId != "" && s.VolumeID != req.SourceVolumeId {
+			continue
+		}
+		snaps = append(snaps, s)
+	}
+
+	start, end, next, err := getPage(
+		len(snaps), req.MaxEntries, req.StartingToken)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []*csi.ListSnapshotsResponse_Entry{}
+	for _, s := range snaps[start:end] {
+		entries = append(entries,
+			&csi.ListSnapshotsResponse_Entry{Snapshot: toCSISnapshot(s)})
+	}
+
+	return &csi.ListSnapshotsResponse{Entries: entries, NextToken: next}, nil
+}
+
+// validateCapabilities returns an error unless each of the capabilities
+// requests a mounted volume that is accessed by a single node.
+func validateCapabilities(caps []*csi.VolumeCapability) error {
+	for _, c := range caps {
+		if c.GetMount() == nil {
+			return status.Error(
+				codes.InvalidArgument, "only mount access is supported")
+		}
+		switch c.GetAccessMode().GetMode() {
+		case csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER,
+			csi.VolumeCapability_AccessMode_SINGLE_NODE_READER_ONLY:
+		default:
+			return status.Errorf(codes.InvalidArgument,
+				"unsupported access mode: %s", c.GetAccessMode().GetMode())
+		}
+	}
+	return nil
+}
+
+// getSize returns the size in GiB of a volume that satisfies the capacity
+// range. A size of zero indicates the storage driver's default size.
+func getSize(cr *csi.CapacityRange) (int64, error) {
+	if cr == nil || (cr.RequiredBytes == 0 && cr.LimitBytes == 0) {
+		return 0, nil
+	}
+	if cr.RequiredBytes < 0 || cr.LimitBytes < 0 {
+		return 0, status.Error(codes.InvalidArgument, "negative capacity")
+	}
+	size := (cr.RequiredBytes + gib - 1) / gib
+	if size == 0 {
+		size = 1
+	}
+	if cr.LimitBytes > 0 && size*gib > cr.LimitBytes {
+		return 0, status.Errorf(codes.OutOfRange,
+			"no size in GiB satisfies the capacity range %d-%d",
+			cr.RequiredBytes, cr.LimitBytes)
+	}
+	return size, nil
+}
+
+// getPage returns the bounds of the page of a list of the given length that
+// begins at the index in the starting token, as well as the token of the
+// next page.
+func getPage(
+	length int,
+	maxEntries int32,
+	startingToken string) (int, int, string, error) {
+
+	if maxEntries < 0 {
+		return 0, 0, "", status.Error(
+			codes.InvalidArgument, "negative max entries")
+	}
+
+	start := 0
+	if startingToken != "" {
+		i, err := strconv.Atoi(startingToken)
+		if err != nil || i < 0 || i > length {
+			return 0, 0, "", status.Errorf(
+				codes.Aborted, "invalid starting token: %s", startingToken)
+		}
+		start = i
+	}
+
+	end := length
+	if maxEntries > 0 && start+int(maxEntries) < length {
+		end = start + int(maxEntries)
+	}
+
+	next := ""
+	if end < length {
+		next = strconv.Itoa(end)
+	}
+	return start, end, next, nil
+}
+
+// parseNodeID returns the instance ID encoded in a node ID.
+func parseNodeID(nodeID string) (*types.InstanceID, error) {
+	if nodeID == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing node id")
+	}
+	iid := &types.InstanceID{}
+	if err := iid.UnmarshalText([]byte(nodeID)); err != nil {
+		return nil, status.Errorf(
+			codes.InvalidArgument, "invalid node id: %s", nodeID)
+	}
+	return iid, nil
+}
+
+// getAttachment returns the volume's attachment to the instance.
+func getAttachment(
+	v *types.Volume, iid *types.InstanceID) *types.VolumeAttachment {
+
+	for _, a := range v.Attachments {
+		if a.InstanceID != nil && a.InstanceID.ID == iid.ID {
+			return a
+		}
+	}
+	return nil
+}
+
+func toCSIVolume(v *types.Volume) *csi.Volume {
+	ctx := map[string]string{"name": v.Name}
+	if v.Type != "" {
+		ctx["type"] = v.Type
+	}
+	if v.AvailabilityZone != "" {
+		ctx["availabilityZone"] = v.AvailabilityZone
+	}
+	return &csi.Volume{
+		VolumeId:      v.ID,
+		CapacityBytes: v.Size * gib,
+		VolumeContext: ctx,
+	}
+}
+
+func toCSISnapshot(s *types.Snapshot) *csi.Snapshot {
+	return &csi.Snapshot{
+		SnapshotId:     s.ID,
+		SourceVolumeId: s.VolumeID,
+		SizeBytes:      s.VolumeSize * gib,
+		CreationTime:   &timestamp.Timestamp{Seconds: s.StartTime},
+		ReadyToUse:     true,
+	}
+}
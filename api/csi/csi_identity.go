@@ -0,0 +1,67 @@
+package csi
+
+import (
+	gofig "github.com/akutz/gofig/types"
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	gocontext "golang.org/x/net/context"
+
+	"github.com/codedellemc/libstorage/api"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+type identity struct {
+	name string
+}
+
+func newIdentity(config gofig.Config) *identity {
+	name := config.GetString(types.ConfigCSIName)
+	if name == "" {
+		name = "libstorage.codedellemc.com"
+	}
+	return &identity{name: name}
+}
+
+func (i *identity) GetPluginInfo(
+	ctx gocontext.Context,
+	req *csi.GetPluginInfoRequest) (*csi.GetPluginInfoResponse, error) {
+
+	version := "0.0.0"
+	if api.Version != nil && api.Version.SemVer != "" {
+		version = api.Version.SemVer
+	}
+
+	return &csi.GetPluginInfoResponse{
+		Name:          i.name,
+		VendorVersion: version,
+	}, nil
+}
+
+// GetPluginCapabilities reports the controller service for both plug-ins
+// since a node plug-in is only useful when a controller plug-in exists.
+func (i *identity) GetPluginCapabilities(
+	ctx gocontext.Context,
+	req *csi.GetPluginCapabilitiesRequest) (
+	*csi.GetPluginCapabilitiesResponse, error) {
+
+	return &csi.GetPluginCapabilitiesResponse{
+		Capabilities: []*csi.PluginCapability{
+			{
+				Type: &csi.PluginCapability_Service_{
+					Service: &csi.PluginCapability_Service{
+						Type: csi.PluginCapability_Service_CONTROLLER_SERVICE,
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+func (i *identity) Probe(
+	ctx gocontext.Context,
+	req *csi.ProbeRequest) (*csi.ProbeResponse, error) {
+
+	return &csi.ProbeResponse{
+		Ready: &wrappers.BoolValue{Value: true},
+	}, nil
+}
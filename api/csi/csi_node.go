@@ -0,0 +1,325 @@
+package csi
+
+import (
+	"os"
+	"strings"
+
+	log "github.com/Sirupsen/logrus"
+	gofig "github.com/akutz/gofig/types"
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	gocontext "golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/api/utils"
+	apiconfig "github.com/codedellemc/libstorage/api/utils/config"
+)
+
+type node struct {
+	ctx    types.Context
+	client types.Client
+	config gofig.Config
+}
+
+func newNode(
+	ctx types.Context,
+	client types.Client,
+	config gofig.Config,
+	service string) *node {
+
+	return &node{
+		ctx:    ctx.WithValue(context.ServiceKey, service),
+		client: client,
+		config: config,
+	}
+}
+
+// requestContext returns a context that is canceled with the request and
+// that has the values of the node's context.
+func (n *node) requestContext(goCtx gocontext.Context) types.Context {
+	return context.New(goCtx).Join(n.ctx)
+}
+
+func (n *node) NodeStageVolume(
+	goCtx gocontext.Context,
+	req *csi.NodeStageVolumeRequest) (*csi.NodeStageVolumeResponse, error) {
+
+	if req.VolumeId == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing volume id")
+	}
+	if req.StagingTargetPath == "" {
+		return nil, status.Error(
+			codes.InvalidArgument, "missing staging target path")
+	}
+	if req.VolumeCapability == nil {
+		return nil, status.Error(
+			codes.InvalidArgument, "missing volume capability")
+	}
+	mnt := req.VolumeCapability.GetMount()
+	if mnt == nil {
+		return nil, status.Error(
+			codes.InvalidArgument, "only mount access is supported")
+	}
+
+	ctx := n.requestContext(goCtx)
+	fields := log.Fields{
+		"volumeID":    req.VolumeId,
+		"stagingPath": req.StagingTargetPath,
+	}
+
+	ok, err := n.client.OS().IsMounted(
+		ctx, req.StagingTargetPath, utils.NewStore())
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	if ok {
+		return &csi.NodeStageVolumeResponse{}, nil
+	}
+
+	if token := req.PublishContext[PublishContextAttachToken]; token != "" {
+		_, _, err := n.client.Executor().WaitForDevice(
+			ctx, &types.WaitForDeviceOpts{
+				LocalDevicesOpts: types.LocalDevicesOpts{
+					ScanType: apiconfig.DeviceScanType(n.config),
+					Opts:     utils.NewStore(),
+				},
+				Token:   token,
+				Timeout: apiconfig.DeviceAttachTimeout(n.config),
+			})
+		if err != nil {
+			return nil, status.Errorf(codes.DeadlineExceeded,
+				"error waiting for device: %v", err)
+		}
+	}
+
+	deviceName, err := n.getDeviceName(ctx, req.VolumeId)
+	if err != nil {
+		return nil, err
+	}
+
+	fsType := mnt.FsType
+	if fsType == "" {
+		fsType = n.config.GetString(types.ConfigIgVolOpsCreateDefaultFsType)
+	}
+	if fsType == "" {
+		fsType = "ext4"
+	}
+
+	if err := n.client.OS().Format(
+		ctx,
+		deviceName,
+		&types.DeviceFormatOpts{NewFSType: fsType}); err != nil {
+		return nil, toStatusError(err)
+	}
+
+	if err := os.MkdirAll(req.StagingTargetPath, 0755); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	if err := n.client.OS().Mount(
+		ctx,
+		deviceName,
+		req.StagingTargetPath,
+		&types.DeviceMountOpts{
+			MountOptions: strings.Join(mnt.MountFlags, ","),
+			Opts:         utils.NewStore(),
+		}); err != nil {
+		return nil, toStatusError(err)
+	}
+
+	fields["deviceName"] = deviceName
+	ctx.WithFields(fields).Info("staged volume")
+	return &csi.NodeStageVolumeResponse{}, nil
+}
+
+// getDeviceName returns the name of the local device of the volume's
+// attachment to this node.
+func (n *node) getDeviceName(
+	ctx types.Context, volumeID string) (string, error) {
+
+	iid, err := n.client.Executor().InstanceID(ctx, utils.NewStore())
+	if err != nil {
+		return "", toStatusError(err)
+	}
+
+	v, err := n.client.Storage().VolumeInspect(
+		ctx, volumeID, &types.VolumeInspectOpts{
+			Attachments: types.VolAttReqWithDevMapOnlyVolsAttachedToInstance,
+			Opts:        utils.NewStore(),
+		})
+	if err != nil {
+		return "", toStatusError(err)
+	}
+
+	att := getAttachment(v, iid)
+	if att == nil {
+		return "", status.Errorf(codes.FailedPrecondition,
+			"volume %s is not attached to this node", volumeID)
+	}
+	if att.DeviceName == "" {
+		return "", status.Errorf(codes.Internal,
+			"volume %s has no local device", volumeID)
+	}
+	return att.DeviceName, nil
+}
+
+func (n *node) NodeUnstageVolume(
+	goCtx gocontext.Context,
+	req *csi.NodeUnstageVolumeRequest) (
+	*csi.NodeUnstageVolumeResponse, error) {
+
+	if req.VolumeId == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing volume id")
+	}
+	if req.StagingTargetPath == "" {
+		return nil, status.Error(
+			codes.InvalidArgument, "missing staging target path")
+	}
+
+	if err := n.unmount(goCtx, req.StagingTargetPath); err != nil {
+		return nil, err
+	}
+	return &csi.NodeUnstageVolumeResponse{}, nil
+}
+
+func (n *node) NodePublishVolume(
+	goCtx gocontext.Context,
+	req *csi.NodePublishVolumeRequest) (
+	*csi.NodePublishVolumeResponse, error) {
+
+	if req.VolumeId == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing volume id")
+	}
+	if req.StagingTargetPath == "" {
+		return nil, status.Error(
+			codes.InvalidArgument, "missing staging target path")
+	}
+	if req.TargetPath == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing target path")
+	}
+	if req.VolumeCapability.GetMount() == nil {
+		return nil, status.Error(
+			codes.InvalidArgument, "only mount access is supported")
+	}
+
+	ctx := n.requestContext(goCtx)
+
+	ok, err := n.client.OS().IsMounted(ctx, req.TargetPath, utils.NewStore())
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	if ok {
+		return &csi.NodePublishVolumeResponse{}, nil
+	}
+
+	if err := os.MkdirAll(req.TargetPath, 0755); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	options := "bind"
+	if req.Readonly {
+		options = "bind,ro"
+	}
+
+	// the staged volume is bind mounted to the target path
+	if err := n.client.OS().Mount(
+		ctx,
+		req.StagingTargetPath,
+		req.TargetPath,
+		&types.DeviceMountOpts{
+			MountOptions: options,
+			Opts:         utils.NewStore(),
+		}); err != nil {
+		return nil, toStatusError(err)
+	}
+
+	ctx.WithFields(log.Fields{
+		"volumeID":   req.VolumeId,
+		"targetPath": req.TargetPath,
+	}).Info("published volume")
+	return &csi.NodePublishVolumeResponse{}, nil
+}
+
+func (n *node) NodeUnpublishVolume(
+	goCtx gocontext.Context,
+	req *csi.NodeUnpublishVolumeRequest) (
+	*csi.NodeUnpublishVolumeResponse, error) {
+
+	if req.VolumeId == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing volume id")
+	}
+	if req.TargetPath == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing target path")
+	}
+
+	if err := n.unmount(goCtx, req.TargetPath); err != nil {
+		return nil, err
+	}
+	return &csi.NodeUnpublishVolumeResponse{}, nil
+}
+
+// unmount unmounts the path if it is mounted.
+func (n *node) unmount(goCtx gocontext.Context, path string) error {
+	ctx := n.requestContext(goCtx)
+
+	ok, err := n.client.OS().IsMounted(ctx, path, utils.NewStore())
+	if err != nil {
+		return toStatusError(err)
+	}
+	if !ok {
+		return nil
+	}
+
+	if err := n.client.OS().Unmount(ctx, path, utils.NewStore()); err != nil {
+		return toStatusError(err)
+	}
+	ctx.WithField("path", path).Info("unmounted volume")
+	return nil
+}
+
+func (n *node) NodeGetCapabilities(
+	goCtx gocontext.Context,
+	req *csi.NodeGetCapabilitiesRequest) (
+	*csi.NodeGetCapabilitiesResponse, error) {
+
+	stage := csi.NodeServiceCapability_RPC_STAGE_UNSTAGE_VOLUME
+	return &csi.NodeGetCapabilitiesResponse{
+		Capabilities: []*csi.NodeServiceCapability{
+			{
+				Type: &csi.NodeServiceCapability_Rpc{
+					Rpc: &csi.NodeServiceCapability_RPC{Type: stage},
+				},
+			},
+		},
+	}, nil
+}
+
+// NodeGetInfo returns the node's instance ID as its node ID so that the
+// controller is able to attach volumes to the node.
+func (n *node) NodeGetInfo(
+	goCtx gocontext.Context,
+	req *csi.NodeGetInfoRequest) (*csi.NodeGetInfoResponse, error) {
+
+	ctx := n.requestContext(goCtx)
+
+	iid, err := n.client.Executor().InstanceID(ctx, utils.NewStore())
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	nodeID, err := iid.MarshalText()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &csi.NodeGetInfoResponse{NodeId: string(nodeID)}, nil
+}
+
+func (n *node) NodeGetVolumeStats(
+	goCtx gocontext.Context,
+	req *csi.NodeGetVolumeStatsRequest) (
+	*csi.NodeGetVolumeStatsResponse, error) {
+
+	return nil, status.Error(codes.Unimplemented, "get volume stats")
+}
@@ -27,6 +27,9 @@ var (
 	intDriverCtors    = map[string]types.NewIntegrationDriver{}
 	intDriverCtorsRWL = &sync.RWMutex{}
 
+	taskStoreCtors    = map[string]types.NewTaskStore{}
+	taskStoreCtorsRWL = &sync.RWMutex{}
+
 	routers    = []types.Router{}
 	routersRWL = &sync.RWMutex{}
 )
@@ -75,6 +78,13 @@ func RegisterIntegrationDriver(name string, ctor types.NewIntegrationDriver) {
 	intDriverCtors[strings.ToLower(name)] = ctor
 }
 
+// RegisterTaskStore registers a TaskStore.
+func RegisterTaskStore(name string, ctor types.NewTaskStore) {
+	taskStoreCtorsRWL.Lock()
+	defer taskStoreCtorsRWL.Unlock()
+	taskStoreCtors[strings.ToLower(name)] = ctor
+}
+
 // NewStorageExecutor returns a new instance of the executor specified by the
 // executor name.
 func NewStorageExecutor(name string) (types.StorageExecutor, error) {
@@ -180,6 +190,26 @@ func NewIntegrationDriver(name string) (types.IntegrationDriver, error) {
 	return NewIntegrationDriverManager(ctor()), nil
 }
 
+// NewTaskStore returns a new instance of the task store specified by the
+// store name.
+func NewTaskStore(name string) (types.TaskStore, error) {
+
+	var ok bool
+	var ctor types.NewTaskStore
+
+	func() {
+		taskStoreCtorsRWL.RLock()
+		defer taskStoreCtorsRWL.RUnlock()
+		ctor, ok = taskStoreCtors[strings.ToLower(name)]
+	}()
+
+	if !ok {
+		return nil, goof.WithField("store", name, "invalid task store name")
+	}
+
+	return ctor(), nil
+}
+
 // StorageExecutors returns a channel on which new instances of all registered
 // storage executors can be received.
 func StorageExecutors() <-chan types.StorageExecutor {
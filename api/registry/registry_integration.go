@@ -1,10 +1,9 @@
 package registry
 
 import (
-	"sync"
-
 	log "github.com/Sirupsen/logrus"
 	gofig "github.com/akutz/gofig/types"
+	"github.com/akutz/goof"
 
 	"github.com/codedellemc/libstorage/api/context"
 	"github.com/codedellemc/libstorage/api/types"
@@ -13,16 +12,15 @@ import (
 
 type idm struct {
 	types.IntegrationDriver
-	sync.RWMutex
 	ctx    types.Context
 	config gofig.Config
-	used   map[string]int
+	state  *mountStateStore
 }
 
 // NewIntegrationDriverManager returns a new integration driver manager.
 func NewIntegrationDriverManager(
 	d types.IntegrationDriver) types.IntegrationDriver {
-	return &idm{IntegrationDriver: d, used: map[string]int{}}
+	return &idm{IntegrationDriver: d}
 }
 
 func (d *idm) Name() string {
@@ -36,7 +34,7 @@ func (d *idm) Init(ctx types.Context, config gofig.Config) error {
 
 	d.ctx = ctx
 	d.config = config
-	d.used = map[string]int{}
+	d.state = getMountStateStore(MountStatePath(config))
 
 	d.initPathCache(ctx)
 
@@ -45,6 +43,7 @@ func (d *idm) Init(ctx types.Context, config gofig.Config) error {
 		types.ConfigIgVolOpsPathCacheAsync:    d.pathCacheAsync(),
 		types.ConfigIgVolOpsUnmountIgnoreUsed: d.ignoreUsedCount(),
 		types.ConfigIgVolOpsMountPreempt:      d.preempt(),
+		types.ConfigIgVolOpsMountStateFile:    d.state.path,
 		types.ConfigIgVolOpsCreateDisable:     d.disableCreate(),
 		types.ConfigIgVolOpsRemoveDisable:     d.disableRemove(),
 	}).Info("libStorage integration driver successfully initialized")
@@ -107,13 +106,7 @@ func (d *idm) List(
 		return volMapsWithNames, nil
 	}
 
-	for _, vm := range volMapsWithNames {
-		vmn := vm.VolumeName()
-		if !d.isCounted(vmn) && vm.MountPoint() != "" {
-			d.initCount(vmn)
-		}
-	}
-
+	d.reconcileState(volMapsWithNames)
 	return volMapsWithNames, nil
 }
 
@@ -155,7 +148,7 @@ func (d *idm) Mount(
 		vol.Attachments[0].MountPoint = mp
 	}
 
-	d.incCount(volumeName)
+	d.addConsumer(volumeID, volumeName, mp, opts.ConsumerID)
 	return mp, vol, err
 }
 
@@ -170,17 +163,25 @@ func (d *idm) Unmount(
 		"opts":       opts}
 	ctx.WithFields(fields).Debug("unmounting volume")
 
-	if d.ignoreUsedCount() ||
-		d.resetCount(volumeName) ||
-		!d.isCounted(volumeName) {
+	unmount := d.ignoreUsedCount()
+	if !unmount {
+		var consumerID string
+		if opts != nil {
+			consumerID = opts.GetString(types.ConsumerIDOptKey)
+		}
+		var err error
+		if unmount, err = d.removeConsumer(volumeName, consumerID); err != nil {
+			return nil, err
+		}
+	}
 
-		d.initCount(volumeName)
-		return d.IntegrationDriver.Unmount(
-			ctx.Join(d.ctx), volumeID, volumeName, opts)
+	if !unmount {
+		return nil, nil
 	}
 
-	d.decCount(volumeName)
-	return nil, nil
+	d.resetState(volumeName)
+	return d.IntegrationDriver.Unmount(
+		ctx.Join(d.ctx), volumeID, volumeName, opts)
 }
 
 func (d *idm) Path(
@@ -269,60 +270,113 @@ func (d *idm) Detach(
 
 }
 
-func (d *idm) initCount(volumeName string) {
-	d.Lock()
-	defer d.Unlock()
-	d.used[volumeName] = 0
-	d.ctx.WithFields(log.Fields{
-		"volumeName": volumeName,
-		"count":      0,
-	}).Debug("init count")
+// reconcileState updates the persisted mount state with the volumes' actual
+// mount points. A mounted volume that has no mount state is recorded without
+// any consumers, and the mount state of a volume that is not mounted is
+// removed.
+func (d *idm) reconcileState(volMaps []types.VolumeMapping) {
+	err := d.state.update(func(states types.VolumeMountStates) error {
+		for _, vm := range volMaps {
+			vmn := vm.VolumeName()
+			_, ok := states[vmn]
+			if ok && vm.MountPoint() == "" {
+				d.ctx.WithField("volumeName", vmn).Info(
+					"removed mount state of unmounted volume")
+				delete(states, vmn)
+			} else if !ok && vm.MountPoint() != "" {
+				states[vmn] = &types.VolumeMountState{
+					MountPoint: vm.MountPoint(),
+					Consumers:  map[string]int{},
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		d.ctx.WithError(err).Error("error reconciling mount state")
+	}
 }
 
-func (d *idm) resetCount(volumeName string) bool {
-	d.Lock()
-	defer d.Unlock()
-	c, ok := d.used[volumeName]
-	if ok && c < 2 {
+// addConsumer records a mount of the volume on behalf of the consumer.
+func (d *idm) addConsumer(
+	volumeID, volumeName, mountPoint, consumerID string) {
+
+	err := d.state.update(func(states types.VolumeMountStates) error {
+		vs, ok := states[volumeName]
+		if !ok {
+			vs = &types.VolumeMountState{Consumers: map[string]int{}}
+			states[volumeName] = vs
+		}
+		vs.VolumeID = volumeID
+		vs.MountPoint = mountPoint
+		vs.Consumers[consumerID]++
 		d.ctx.WithFields(log.Fields{
 			"volumeName": volumeName,
-			"count":      c,
-		}).Info("count reset")
-		d.used[volumeName] = 0
-		return true
+			"consumerID": consumerID,
+			"count":      vs.Count(),
+		}).Debug("added consumer")
+		return nil
+	})
+	if err != nil {
+		d.ctx.WithError(err).Error("error recording mount state")
 	}
-	return false
 }
 
-func (d *idm) addCount(volumeName string, delta int) {
-	d.Lock()
-	defer d.Unlock()
-	c, ok := d.used[volumeName]
-	if ok {
-		c = c + delta
-	} else {
-		c = 1
+// removeConsumer removes one of the consumer's mounts of the volume and
+// returns a flag indicating whether the volume should be unmounted. A volume
+// is unmounted when it has no mount state or its last mount is removed.
+func (d *idm) removeConsumer(volumeName, consumerID string) (bool, error) {
+	var unmount bool
+	err := d.state.update(func(states types.VolumeMountStates) error {
+		vs, ok := states[volumeName]
+		if !ok {
+			unmount = true
+			return nil
+		}
+		if c, ok := vs.Consumers[consumerID]; ok {
+			if c > 1 {
+				vs.Consumers[consumerID] = c - 1
+			} else {
+				delete(vs.Consumers, consumerID)
+			}
+		}
+		unmount = vs.Count() == 0
+		d.ctx.WithFields(log.Fields{
+			"volumeName": volumeName,
+			"consumerID": consumerID,
+			"count":      vs.Count(),
+		}).Debug("removed consumer")
+		return nil
+	})
+	if err != nil {
+		return false, goof.WithFieldE(
+			"volumeName", volumeName, "error updating mount state", err)
 	}
-	d.used[volumeName] = c
-	d.ctx.WithFields(log.Fields{
-		"volumeName": volumeName,
-		"count":      c,
-	}).Debug("set count")
+	return unmount, nil
 }
 
-func (d *idm) isCounted(volumeName string) bool {
-	d.RLock()
-	defer d.RUnlock()
-	_, ok := d.used[volumeName]
-	return ok
-}
-
-func (d *idm) incCount(volumeName string) {
-	d.addCount(volumeName, 1)
+// resetState records that the volume is not used by any consumer.
+func (d *idm) resetState(volumeName string) {
+	err := d.state.update(func(states types.VolumeMountStates) error {
+		if vs, ok := states[volumeName]; ok {
+			vs.Consumers = map[string]int{}
+		}
+		return nil
+	})
+	if err != nil {
+		d.ctx.WithError(err).Error("error resetting mount state")
+	}
 }
 
-func (d *idm) decCount(volumeName string) {
-	d.addCount(volumeName, -1)
+// isCounted returns a flag indicating whether the volume has mount state.
+func (d *idm) isCounted(volumeName string) bool {
+	states, err := LoadMountState(d.state.path)
+	if err != nil {
+		d.ctx.WithError(err).Error("error reading mount state")
+		return false
+	}
+	_, ok := states[volumeName]
+	return ok
 }
 
 func (d *idm) preempt() bool {
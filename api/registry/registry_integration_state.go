@@ -0,0 +1,205 @@
+package registry
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	gofig "github.com/akutz/gofig/types"
+	"github.com/akutz/goof"
+
+	"github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/api/utils"
+)
+
+const (
+	// mountStateLockRetryInterval is how often an attempt is made to acquire
+	// the mount state lock while it is held by another process.
+	mountStateLockRetryInterval = 50 * time.Millisecond
+
+	// mountStateLockTimeout is how long an update waits to acquire the mount
+	// state lock.
+	mountStateLockTimeout = 30 * time.Second
+)
+
+// mountStateStore persists the mount state of volumes to a local file. The
+// file is locked, read, and written during each update so that changes made
+// by other processes, such as the executor's mounts command, are not lost.
+type mountStateStore struct {
+	sync.Mutex
+	path string
+}
+
+var (
+	mountStateStores    = map[string]*mountStateStore{}
+	mountStateStoresRWL = &sync.Mutex{}
+)
+
+// MountStatePath returns the path of the file to which the integration
+// driver manager persists the mount state of volumes.
+func MountStatePath(config gofig.Config) string {
+	if p := config.GetString(types.ConfigIgVolOpsMountStateFile); p != "" {
+		return p
+	}
+	return types.Lib.Join("mounts.json")
+}
+
+// getMountStateStore returns the store for the file at the specified path.
+// All integration driver managers in a process that use the same file share
+// a store.
+func getMountStateStore(path string) *mountStateStore {
+	mountStateStoresRWL.Lock()
+	defer mountStateStoresRWL.Unlock()
+	s, ok := mountStateStores[path]
+	if !ok {
+		s = &mountStateStore{path: path}
+		mountStateStores[path] = s
+	}
+	return s
+}
+
+// LoadMountState returns the mount state persisted to the file at the
+// specified path.
+func LoadMountState(path string) (types.VolumeMountStates, error) {
+	s := getMountStateStore(path)
+	s.Lock()
+	defer s.Unlock()
+	return s.load()
+}
+
+// RepairMountState removes the specified consumer from the mount state of a
+// volume. If the consumer ID is empty then the volume is removed from the
+// mount state altogether. The repaired state is returned.
+func RepairMountState(
+	path, volumeName, consumerID string) (types.VolumeMountStates, error) {
+
+	var states types.VolumeMountStates
+	err := getMountStateStore(path).update(
+		func(s types.VolumeMountStates) error {
+			vs, ok := s[volumeName]
+			if !ok {
+				return goof.WithField(
+					"volumeName", volumeName, "volume has no mount state")
+			}
+			if consumerID == "" {
+				delete(s, volumeName)
+			} else if _, ok := vs.Consumers[consumerID]; ok {
+				delete(vs.Consumers, consumerID)
+			} else {
+				return goof.WithFields(goof.Fields{
+					"volumeName": volumeName,
+					"consumerID": consumerID,
+				}, "volume has no such consumer")
+			}
+			states = s
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+	return states, nil
+}
+
+func (s *mountStateStore) load() (types.VolumeMountStates, error) {
+	states := types.VolumeMountStates{}
+	buf, err := ioutil.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return states, nil
+		}
+		return nil, goof.WithFieldE(
+			"path", s.path, "error reading mount state", err)
+	}
+	if len(buf) == 0 {
+		return states, nil
+	}
+	if err := json.Unmarshal(buf, &states); err != nil {
+		return nil, goof.WithFieldE(
+			"path", s.path, "error decoding mount state", err)
+	}
+	for _, vs := range states {
+		if vs.Consumers == nil {
+			vs.Consumers = map[string]int{}
+		}
+	}
+	return states, nil
+}
+
+// save writes the state to a temporary file that then replaces the state
+// file so that a reader never sees a partially written file.
+func (s *mountStateStore) save(states types.VolumeMountStates) error {
+	buf, err := json.MarshalIndent(states, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return goof.WithFieldE(
+			"path", s.path, "error creating mount state dir", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, buf, 0644); err != nil {
+		return goof.WithFieldE(
+			"path", s.path, "error writing mount state", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return goof.WithFieldE(
+			"path", s.path, "error writing mount state", err)
+	}
+	return nil
+}
+
+// lockFile acquires the lock file that serializes the updates of the
+// processes that share the state file, such as the executor's mounts command.
+func (s *mountStateStore) lockFile() (*os.File, error) {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return nil, goof.WithFieldE(
+			"path", s.path, "error creating mount state dir", err)
+	}
+
+	path := s.path + ".lock"
+	timeout := time.After(mountStateLockTimeout)
+	for {
+		f, err := utils.TryLockFile(path)
+		if err != nil {
+			return nil, goof.WithFieldE(
+				"path", path, "error locking mount state", err)
+		}
+		if f != nil {
+			return f, nil
+		}
+		select {
+		case <-timeout:
+			return nil, goof.WithField(
+				"path", path, "timed out locking mount state")
+		case <-time.After(mountStateLockRetryInterval):
+		}
+	}
+}
+
+// update loads the state, applies the function to it, and saves the state if
+// the function does not return an error. The state file is locked throughout
+// so that the updates of other processes are not lost.
+func (s *mountStateStore) update(
+	f func(states types.VolumeMountStates) error) error {
+
+	s.Lock()
+	defer s.Unlock()
+
+	lock, err := s.lockFile()
+	if err != nil {
+		return err
+	}
+	defer utils.UnlockFile(lock)
+
+	states, err := s.load()
+	if err != nil {
+		return err
+	}
+	if err := f(states); err != nil {
+		return err
+	}
+	return s.save(states)
+}
@@ -0,0 +1,205 @@
+package registry
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"sync"
+	"testing"
+
+	gofigCore "github.com/akutz/gofig"
+	gofig "github.com/akutz/gofig/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/api/utils"
+)
+
+type testIntegrationDriver struct {
+	types.IntegrationDriver
+	unmounts int
+}
+
+func (d *testIntegrationDriver) Name() string {
+	return "test"
+}
+
+func (d *testIntegrationDriver) Init(
+	ctx types.Context, config gofig.Config) error {
+	return nil
+}
+
+func (d *testIntegrationDriver) Mount(
+	ctx types.Context,
+	volumeID, volumeName string,
+	opts *types.VolumeMountOpts) (string, *types.Volume, error) {
+	return "/mnt/" + volumeName, &types.Volume{ID: volumeID}, nil
+}
+
+func (d *testIntegrationDriver) Unmount(
+	ctx types.Context,
+	volumeID, volumeName string,
+	opts types.Store) (*types.Volume, error) {
+	d.unmounts++
+	return &types.Volume{ID: volumeID}, nil
+}
+
+var mountStateConfigFormat = `
+libstorage:
+  integration:
+    volume:
+      operations:
+        mount:
+          stateFile: %s
+`
+
+func newTestIDM(t *testing.T, stateFile string) (
+	types.IntegrationDriver, *testIntegrationDriver) {
+
+	config := gofigCore.New()
+	buf := []byte(fmt.Sprintf(mountStateConfigFormat, stateFile))
+	if err := config.ReadConfig(bytes.NewReader(buf)); err != nil {
+		t.Fatal(err)
+	}
+
+	td := &testIntegrationDriver{}
+	d := NewIntegrationDriverManager(td)
+	if err := d.Init(context.Background(), config); err != nil {
+		t.Fatal(err)
+	}
+	return d, td
+}
+
+func TestIntegrationMountState(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	stateFile := path.Join(dir, "mounts.json")
+
+	ctx := context.Background()
+	d, td := newTestIDM(t, stateFile)
+
+	mount := func(consumerID string) {
+		_, _, err := d.Mount(ctx, "vol-000", "vol0",
+			&types.VolumeMountOpts{ConsumerID: consumerID})
+		assert.NoError(t, err)
+	}
+	unmount := func(consumerID string) {
+		_, err := d.Unmount(ctx, "vol-000", "vol0",
+			utils.NewStoreWithData(map[string]interface{}{
+				types.ConsumerIDOptKey: consumerID,
+			}))
+		assert.NoError(t, err)
+	}
+
+	mount("c1")
+	mount("c2")
+	mount("c2")
+
+	states, err := LoadMountState(stateFile)
+	assert.NoError(t, err)
+	if !assert.Contains(t, states, "vol0") {
+		t.FailNow()
+	}
+	assert.Equal(t, "vol-000", states["vol0"].VolumeID)
+	assert.Equal(t, "/mnt/vol0", states["vol0"].MountPoint)
+	assert.Equal(t, 3, states["vol0"].Count())
+
+	// the state survives a restart of the manager
+	d, td = newTestIDM(t, stateFile)
+
+	// an unknown consumer does not release another consumer's mounts
+	unmount("c3")
+	unmount("c1")
+	unmount("c2")
+	assert.Equal(t, 0, td.unmounts)
+
+	unmount("c2")
+	assert.Equal(t, 1, td.unmounts)
+
+	states, err = LoadMountState(stateFile)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, states["vol0"].Count())
+}
+
+func TestRepairMountState(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	stateFile := path.Join(dir, "mounts.json")
+
+	ctx := context.Background()
+	d, td := newTestIDM(t, stateFile)
+
+	for _, c := range []string{"c1", "c2"} {
+		_, _, err := d.Mount(ctx, "vol-000", "vol0",
+			&types.VolumeMountOpts{ConsumerID: c})
+		assert.NoError(t, err)
+	}
+
+	// remove a consumer that exited without unmounting the volume
+	states, err := RepairMountState(stateFile, "vol0", "c1")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, states["vol0"].Count())
+
+	_, err = RepairMountState(stateFile, "vol0", "c1")
+	assert.Error(t, err)
+	_, err = RepairMountState(stateFile, "vol1", "")
+	assert.Error(t, err)
+
+	_, err = d.Unmount(ctx, "vol-000", "vol0",
+		utils.NewStoreWithData(map[string]interface{}{
+			types.ConsumerIDOptKey: "c2",
+		}))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, td.unmounts)
+
+	states, err = RepairMountState(stateFile, "vol0", "")
+	assert.NoError(t, err)
+	assert.NotContains(t, states, "vol0")
+}
+
+func TestMountStateStoreUpdateLocked(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	stateFile := path.Join(dir, "mounts.json")
+
+	// separate stores for the same file behave like separate processes
+	// since they do not share a mutex
+	const updates = 20
+	wg := &sync.WaitGroup{}
+	for i := 0; i < updates; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s := &mountStateStore{path: stateFile}
+			err := s.update(func(states types.VolumeMountStates) error {
+				vs, ok := states["vol0"]
+				if !ok {
+					vs = &types.VolumeMountState{
+						Consumers: map[string]int{},
+					}
+					states["vol0"] = vs
+				}
+				vs.Consumers[""]++
+				return nil
+			})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	states, err := LoadMountState(stateFile)
+	assert.NoError(t, err)
+	assert.Equal(t, updates, states["vol0"].Consumers[""])
+}
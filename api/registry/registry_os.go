@@ -63,3 +63,15 @@ func (d *odm) Format(
 	}
 	return d.OSDriver.Format(ctx, deviceName, opts)
 }
+
+func (d *odm) Resize(
+	ctx types.Context,
+	deviceName, mountPoint string,
+	opts types.Store) error {
+
+	od, ok := d.OSDriver.(types.OSDriverWithResize)
+	if !ok {
+		return types.ErrNotImplemented
+	}
+	return od.Resize(ctx.Join(d.Context), deviceName, mountPoint, opts)
+}
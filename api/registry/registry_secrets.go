@@ -0,0 +1,182 @@
+package registry
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	gofig "github.com/akutz/gofig/types"
+)
+
+// RedactedValue replaces the value of a secret in configuration and
+// environment dumps.
+const RedactedValue = "******"
+
+var (
+	secretConfigKeys    = map[string]bool{}
+	secretConfigKeysRWL = &sync.RWMutex{}
+
+	secretEnvVarConfigKeys    = map[string]string{}
+	secretEnvVarConfigKeysRWL = &sync.RWMutex{}
+)
+
+// RegisterSecretConfigKeys registers configuration keys whose values are
+// secrets, such as passwords or access keys. Drivers should register their
+// secret keys alongside their gofig registration.
+//
+// A key also matches the same key when it is scoped beneath another key, such
+// as the key "ebs.secretKey" scoped to the service at
+// "libstorage.server.services.ebs-00". A key that refers to an object, such
+// as a map of tokens, marks all of the object's values as secrets.
+func RegisterSecretConfigKeys(keys ...string) {
+	secretConfigKeysRWL.Lock()
+	defer secretConfigKeysRWL.Unlock()
+	for _, k := range keys {
+		secretConfigKeys[strings.ToLower(k)] = true
+	}
+}
+
+// SecretConfigKeys returns the registered secret configuration keys.
+func SecretConfigKeys() []string {
+	secretConfigKeysRWL.RLock()
+	defer secretConfigKeysRWL.RUnlock()
+	var keys []string
+	for k := range secretConfigKeys {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// RegisterSecretEnvVarConfigKey registers a configuration key whose value is
+// the name of an environment variable that holds a secret, such as the
+// variable from which a LUKS key is read. The default name is used when the
+// key is not set.
+func RegisterSecretEnvVarConfigKey(key, defaultName string) {
+	secretEnvVarConfigKeysRWL.Lock()
+	defer secretEnvVarConfigKeysRWL.Unlock()
+	secretEnvVarConfigKeys[strings.ToLower(key)] = defaultName
+}
+
+// SecretEnvVars returns the names of the environment variables that hold
+// secrets according to the configuration keys registered with
+// RegisterSecretEnvVarConfigKey.
+func SecretEnvVars(config gofig.Config) []string {
+	secretEnvVarConfigKeysRWL.RLock()
+	defer secretEnvVarConfigKeysRWL.RUnlock()
+	var names []string
+	for k, defaultName := range secretEnvVarConfigKeys {
+		name := defaultName
+		if config != nil && config.IsSet(k) {
+			name = config.GetString(k)
+		}
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IsSecretConfigKey returns a flag indicating whether the value of the
+// configuration key is a secret.
+func IsSecretConfigKey(key string) bool {
+	key = strings.ToLower(key)
+	secretConfigKeysRWL.RLock()
+	defer secretConfigKeysRWL.RUnlock()
+	for k := range secretConfigKeys {
+		if key == k || strings.HasSuffix(key, "."+k) {
+			return true
+		}
+	}
+	return false
+}
+
+// isSecretEnvVar returns a flag indicating whether the value of the
+// environment variable is a secret. An environment variable matches a key
+// when its name is the key, upper-cased and with its periods replaced by
+// underscores, or the key scoped beneath another key.
+func isSecretEnvVar(name string) bool {
+	name = strings.ToUpper(name)
+	secretConfigKeysRWL.RLock()
+	defer secretConfigKeysRWL.RUnlock()
+	for k := range secretConfigKeys {
+		ek := strings.ToUpper(strings.Replace(k, ".", "_", -1))
+		if name == ek || strings.HasSuffix(name, "_"+ek) ||
+			strings.HasPrefix(name, ek+"_") {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactConfig returns a copy of the configuration settings, such as those
+// returned by a gofig.Config's AllSettings function, with the values of the
+// secret keys redacted.
+func RedactConfig(settings map[string]interface{}) map[string]interface{} {
+	return redactMap("", settings)
+}
+
+func redactMap(
+	parent string, m map[string]interface{}) map[string]interface{} {
+
+	redacted := map[string]interface{}{}
+	for k, v := range m {
+		key := k
+		if parent != "" {
+			key = parent + "." + k
+		}
+		redacted[k] = redactValue(key, v)
+	}
+	return redacted
+}
+
+func redactValue(key string, v interface{}) interface{} {
+	if IsSecretConfigKey(key) {
+		return RedactedValue
+	}
+	switch tv := v.(type) {
+	case map[string]interface{}:
+		return redactMap(key, tv)
+	case map[interface{}]interface{}:
+		m := map[string]interface{}{}
+		for k, v := range tv {
+			if ks, ok := k.(string); ok {
+				m[ks] = v
+			}
+		}
+		return redactMap(key, m)
+	case []interface{}:
+		a := make([]interface{}, len(tv))
+		for i, v := range tv {
+			a[i] = redactValue(key, v)
+		}
+		return a
+	}
+	return v
+}
+
+// RedactEnv returns a copy of the environment, such as the one returned by
+// os.Environ, with the values of the secret keys and of the named secret
+// environment variables, such as those returned by SecretEnvVars, redacted.
+func RedactEnv(env []string, secretNames ...string) []string {
+	redacted := make([]string, len(env))
+	for i, kv := range env {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) == 2 && (isSecretEnvVar(parts[0]) ||
+			containsEnvVar(secretNames, parts[0])) {
+			kv = parts[0] + "=" + RedactedValue
+		}
+		redacted[i] = kv
+	}
+	return redacted
+}
+
+func containsEnvVar(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
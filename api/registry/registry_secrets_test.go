@@ -0,0 +1,100 @@
+package registry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsSecretConfigKey(t *testing.T) {
+	RegisterSecretConfigKeys("testSecrets.password")
+	assert.True(t, IsSecretConfigKey("testsecrets.password"))
+	assert.True(t, IsSecretConfigKey("testSecrets.Password"))
+	assert.True(t, IsSecretConfigKey(
+		"libstorage.server.services.test-00.testSecrets.password"))
+	assert.False(t, IsSecretConfigKey("testSecrets.userName"))
+	assert.False(t, IsSecretConfigKey("notTestSecrets.password"))
+	assert.Contains(t, SecretConfigKeys(), "testsecrets.password")
+}
+
+func TestRedactConfig(t *testing.T) {
+	RegisterSecretConfigKeys("testSecrets.password", "testSecrets.tokens")
+
+	settings := map[string]interface{}{
+		"testsecrets": map[string]interface{}{
+			"username": "user",
+			"password": "pass",
+			"tokens": map[string]interface{}{
+				"a": "tokenA",
+				"b": "tokenB",
+			},
+		},
+		"libstorage": map[interface{}]interface{}{
+			"server": map[interface{}]interface{}{
+				"services": map[interface{}]interface{}{
+					"test-00": map[interface{}]interface{}{
+						"testsecrets": map[interface{}]interface{}{
+							"password": "scopedPass",
+						},
+					},
+					"test-01": []interface{}{
+						map[interface{}]interface{}{
+							"testsecrets": map[interface{}]interface{}{
+								"password": "listedPass",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	redacted := RedactConfig(settings)
+
+	ts := redacted["testsecrets"].(map[string]interface{})
+	assert.Equal(t, "user", ts["username"])
+	assert.Equal(t, RedactedValue, ts["password"])
+	assert.Equal(t, RedactedValue, ts["tokens"])
+
+	svcs := redacted["libstorage"].(map[string]interface{})
+	for _, k := range []string{"server", "services"} {
+		svcs = svcs[k].(map[string]interface{})
+	}
+	svc := svcs["test-00"].(map[string]interface{})
+	svc = svc["testsecrets"].(map[string]interface{})
+	assert.Equal(t, RedactedValue, svc["password"])
+
+	// the values of lists are redacted as well
+	svc = svcs["test-01"].([]interface{})[0].(map[string]interface{})
+	svc = svc["testsecrets"].(map[string]interface{})
+	assert.Equal(t, RedactedValue, svc["password"])
+
+	// the original settings are not modified
+	assert.Equal(t, "pass",
+		settings["testsecrets"].(map[string]interface{})["password"])
+}
+
+func TestRedactEnv(t *testing.T) {
+	RegisterSecretConfigKeys("testSecrets.password", "testSecrets.tokens")
+
+	redacted := RedactEnv([]string{
+		"TESTSECRETS_USERNAME=user",
+		"TESTSECRETS_PASSWORD=pass",
+		"LIBSTORAGE_SERVER_SERVICES_TEST-00_TESTSECRETS_PASSWORD=pass",
+		"TESTSECRETS_TOKENS_A=tokenA",
+		"HOME=/root",
+		"LUKS_KEY=key",
+		"NOVALUE",
+	}, "LUKS_KEY")
+
+	assert.Equal(t, []string{
+		"TESTSECRETS_USERNAME=user",
+		"TESTSECRETS_PASSWORD=" + RedactedValue,
+		"LIBSTORAGE_SERVER_SERVICES_TEST-00_TESTSECRETS_PASSWORD=" +
+			RedactedValue,
+		"TESTSECRETS_TOKENS_A=" + RedactedValue,
+		"HOME=/root",
+		"LUKS_KEY=" + RedactedValue,
+		"NOVALUE",
+	}, redacted)
+}
@@ -1,6 +1,11 @@
 package registry
 
-import "github.com/codedellemc/libstorage/api/types"
+import (
+	"time"
+
+	"github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/api/utils/metrics"
+)
 
 type sdm struct {
 	types.StorageDriver
@@ -8,8 +13,8 @@ type sdm struct {
 }
 
 type sdmWithLogin struct {
-	types.StorageDriverWithLogin
-	types.Context
+	*sdm
+	loginDriver types.StorageDriverWithLogin
 }
 
 // NewStorageDriverManager returns a new storage driver manager.
@@ -21,7 +26,15 @@ func NewStorageDriverManager(
 // NewStorageDriverManagerWithLogin returns a new storage driver manager.
 func NewStorageDriverManagerWithLogin(
 	d types.StorageDriverWithLogin) types.StorageDriverWithLogin {
-	return &sdmWithLogin{StorageDriverWithLogin: d}
+	return &sdmWithLogin{sdm: &sdm{StorageDriver: d}, loginDriver: d}
+}
+
+// observe records the latency and result of a call to one of the driver's
+// methods. It is deferred with the time the call started and a pointer to
+// the method's named error result.
+func (d *sdm) observe(method string, start time.Time, err *error) {
+	metrics.ObserveDriverCall(
+		d.StorageDriver.Name(), method, time.Since(start), *err)
 }
 
 func (d *sdm) API() types.APIClient {
@@ -39,44 +52,50 @@ func (d *sdm) XCLI() types.StorageExecutorCLI {
 }
 
 func (d *sdm) NextDeviceInfo(
-	ctx types.Context) (*types.NextDeviceInfo, error) {
+	ctx types.Context) (info *types.NextDeviceInfo, err error) {
 
+	defer d.observe("NextDeviceInfo", time.Now(), &err)
 	return d.StorageDriver.NextDeviceInfo(ctx.Join(d.Context))
 }
 
 func (d *sdm) Type(
-	ctx types.Context) (types.StorageType, error) {
+	ctx types.Context) (st types.StorageType, err error) {
 
+	defer d.observe("Type", time.Now(), &err)
 	return d.StorageDriver.Type(ctx.Join(d.Context))
 }
 
 func (d *sdm) InstanceInspect(
 	ctx types.Context,
-	opts types.Store) (*types.Instance, error) {
+	opts types.Store) (i *types.Instance, err error) {
 
+	defer d.observe("InstanceInspect", time.Now(), &err)
 	return d.StorageDriver.InstanceInspect(ctx.Join(d.Context), opts)
 }
 
 func (d *sdm) Volumes(
 	ctx types.Context,
-	opts *types.VolumesOpts) ([]*types.Volume, error) {
+	opts *types.VolumesOpts) (vols []*types.Volume, err error) {
 
+	defer d.observe("Volumes", time.Now(), &err)
 	return d.StorageDriver.Volumes(ctx.Join(d.Context), opts)
 }
 
 func (d *sdm) VolumeInspect(
 	ctx types.Context,
 	volumeID string,
-	opts *types.VolumeInspectOpts) (*types.Volume, error) {
+	opts *types.VolumeInspectOpts) (vol *types.Volume, err error) {
 
+	defer d.observe("VolumeInspect", time.Now(), &err)
 	return d.StorageDriver.VolumeInspect(ctx.Join(d.Context), volumeID, opts)
 }
 
 func (d *sdm) VolumeCreate(
 	ctx types.Context,
 	name string,
-	opts *types.VolumeCreateOpts) (*types.Volume, error) {
+	opts *types.VolumeCreateOpts) (vol *types.Volume, err error) {
 
+	defer d.observe("VolumeCreate", time.Now(), &err)
 	return d.StorageDriver.VolumeCreate(ctx.Join(d.Context), name, opts)
 }
 
@@ -84,8 +103,9 @@ func (d *sdm) VolumeCreateFromSnapshot(
 	ctx types.Context,
 	snapshotID,
 	volumeName string,
-	opts *types.VolumeCreateOpts) (*types.Volume, error) {
+	opts *types.VolumeCreateOpts) (vol *types.Volume, err error) {
 
+	defer d.observe("VolumeCreateFromSnapshot", time.Now(), &err)
 	return d.StorageDriver.VolumeCreateFromSnapshot(
 		ctx.Join(d.Context), snapshotID, volumeName, opts)
 }
@@ -94,8 +114,9 @@ func (d *sdm) VolumeCopy(
 	ctx types.Context,
 	volumeID,
 	volumeName string,
-	opts types.Store) (*types.Volume, error) {
+	opts types.Store) (vol *types.Volume, err error) {
 
+	defer d.observe("VolumeCopy", time.Now(), &err)
 	return d.StorageDriver.VolumeCopy(
 		ctx.Join(d.Context), volumeID, volumeName, opts)
 }
@@ -104,8 +125,9 @@ func (d *sdm) VolumeSnapshot(
 	ctx types.Context,
 	volumeID,
 	snapshotName string,
-	opts types.Store) (*types.Snapshot, error) {
+	opts types.Store) (snap *types.Snapshot, err error) {
 
+	defer d.observe("VolumeSnapshot", time.Now(), &err)
 	return d.StorageDriver.VolumeSnapshot(
 		ctx.Join(d.Context), volumeID, snapshotName, opts)
 }
@@ -113,17 +135,39 @@ func (d *sdm) VolumeSnapshot(
 func (d *sdm) VolumeRemove(
 	ctx types.Context,
 	volumeID string,
-	opts types.Store) error {
+	opts types.Store) (err error) {
 
+	defer d.observe("VolumeRemove", time.Now(), &err)
 	return d.StorageDriver.VolumeRemove(
 		ctx.Join(d.Context), volumeID, opts)
 }
 
+func (d *sdm) VolumeResize(
+	ctx types.Context,
+	volumeID string,
+	opts *types.VolumeResizeOpts) (vol *types.Volume, err error) {
+
+	defer d.observe("VolumeResize", time.Now(), &err)
+	return d.StorageDriver.VolumeResize(
+		ctx.Join(d.Context), volumeID, opts)
+}
+
+func (d *sdm) VolumeUpdate(
+	ctx types.Context,
+	volumeID string,
+	opts *types.VolumeUpdateOpts) (vol *types.Volume, err error) {
+
+	defer d.observe("VolumeUpdate", time.Now(), &err)
+	return d.StorageDriver.VolumeUpdate(
+		ctx.Join(d.Context), volumeID, opts)
+}
+
 func (d *sdm) VolumeAttach(
 	ctx types.Context,
 	volumeID string,
-	opts *types.VolumeAttachOpts) (*types.Volume, string, error) {
+	opts *types.VolumeAttachOpts) (vol *types.Volume, token string, err error) {
 
+	defer d.observe("VolumeAttach", time.Now(), &err)
 	return d.StorageDriver.VolumeAttach(
 		ctx.Join(d.Context), volumeID, opts)
 }
@@ -131,24 +175,27 @@ func (d *sdm) VolumeAttach(
 func (d *sdm) VolumeDetach(
 	ctx types.Context,
 	volumeID string,
-	opts *types.VolumeDetachOpts) (*types.Volume, error) {
+	opts *types.VolumeDetachOpts) (vol *types.Volume, err error) {
 
+	defer d.observe("VolumeDetach", time.Now(), &err)
 	return d.StorageDriver.VolumeDetach(
 		ctx.Join(d.Context), volumeID, opts)
 }
 
 func (d *sdm) Snapshots(
 	ctx types.Context,
-	opts types.Store) ([]*types.Snapshot, error) {
+	opts types.Store) (snaps []*types.Snapshot, err error) {
 
+	defer d.observe("Snapshots", time.Now(), &err)
 	return d.StorageDriver.Snapshots(ctx.Join(d.Context), opts)
 }
 
 func (d *sdm) SnapshotInspect(
 	ctx types.Context,
 	snapshotID string,
-	opts types.Store) (*types.Snapshot, error) {
+	opts types.Store) (snap *types.Snapshot, err error) {
 
+	defer d.observe("SnapshotInspect", time.Now(), &err)
 	return d.StorageDriver.SnapshotInspect(
 		ctx.Join(d.Context), snapshotID, opts)
 }
@@ -158,8 +205,9 @@ func (d *sdm) SnapshotCopy(
 	snapshotID,
 	snapshotName,
 	destinationID string,
-	opts types.Store) (*types.Snapshot, error) {
+	opts types.Store) (snap *types.Snapshot, err error) {
 
+	defer d.observe("SnapshotCopy", time.Now(), &err)
 	return d.StorageDriver.SnapshotCopy(
 		ctx.Join(d.Context), snapshotID, snapshotName, destinationID, opts)
 }
@@ -167,13 +215,15 @@ func (d *sdm) SnapshotCopy(
 func (d *sdm) SnapshotRemove(
 	ctx types.Context,
 	snapshotID string,
-	opts types.Store) error {
+	opts types.Store) (err error) {
 
+	defer d.observe("SnapshotRemove", time.Now(), &err)
 	return d.StorageDriver.SnapshotRemove(ctx.Join(d.Context), snapshotID, opts)
 }
 
 func (d *sdmWithLogin) Login(
-	ctx types.Context) (interface{}, error) {
+	ctx types.Context) (v interface{}, err error) {
 
-	return d.StorageDriverWithLogin.Login(ctx.Join(d.Context))
+	defer d.observe("Login", time.Now(), &err)
+	return d.loginDriver.Login(ctx.Join(d.Context))
 }
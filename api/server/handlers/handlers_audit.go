@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/server/httputils"
+	"github.com/codedellemc/libstorage/api/server/services"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+// auditHandler is a global HTTP filter that writes a record to the server's
+// audit log for each request that uses a mutating HTTP method.
+type auditHandler struct {
+	handler types.APIFunc
+}
+
+// NewAuditHandler returns a new global filter that writes a record to the
+// server's audit log for each request that uses a mutating HTTP method.
+func NewAuditHandler() types.Middleware {
+	return &auditHandler{}
+}
+
+func (h *auditHandler) Name() string {
+	return "audit-handler"
+}
+
+func (h *auditHandler) Handler(m types.APIFunc) types.APIFunc {
+	return (&auditHandler{m}).Handle
+}
+
+// statusRecorder records the status code written to a response.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusRecorder) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Flush flushes the underlying response if it supports flushing so that
+// streaming routes may be wrapped by the recorder.
+func (w *statusRecorder) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// CloseNotify returns the underlying response's close notification channel.
+// A nil channel, which never receives, is returned if the response does not
+// support close notification.
+func (w *statusRecorder) CloseNotify() <-chan bool {
+	if cn, ok := w.ResponseWriter.(http.CloseNotifier); ok {
+		return cn.CloseNotify()
+	}
+	return nil
+}
+
+// auditStateKeyType is the type of the key for the *auditState value the
+// audit handler injects into a request's context.
+type auditStateKeyType int
+
+const auditStateKey auditStateKeyType = 0
+
+// auditState is the request state recorded by the handlers that run after the
+// audit handler. The audit handler is registered before the auth handler so
+// that rejected requests are audited, which means the values the later
+// handlers inject into the context are not visible to it.
+type auditState struct {
+	user        string
+	name        string
+	instanceIDs types.InstanceIDMap
+}
+
+// auditNameKeys are the keys of the fields of a request object that name the
+// volume or snapshot on which the request operates.
+var auditNameKeys = []string{"name", "volumeName", "snapshotName"}
+
+// getAuditState returns the audit state of the request's context or nil if
+// the request is not audited.
+func getAuditState(ctx types.Context) *auditState {
+	s, _ := ctx.Value(auditStateKey).(*auditState)
+	return s
+}
+
+// Handle is the type's Handler function.
+func (h *auditHandler) Handle(
+	ctx types.Context,
+	w http.ResponseWriter,
+	req *http.Request,
+	store types.Store) error {
+
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		return h.handler(ctx, w, req, store)
+	}
+
+	state := &auditState{}
+	if user, ok := context.User(ctx); ok {
+		state.user = user
+	}
+
+	start := time.Now()
+	rw := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	err := h.handler(ctx.WithValue(auditStateKey, state), rw, req, store)
+
+	// the identifiers are read from the route's path and the name from the
+	// request object rather than from the store, which also holds the
+	// request's query parameters
+	vars := mux.Vars(req)
+	rec := &types.AuditRecord{
+		Time:       start.Unix(),
+		User:       state.user,
+		Service:    vars["service"],
+		Method:     req.Method,
+		Path:       req.URL.Path,
+		VolumeID:   vars["volumeID"],
+		SnapshotID: vars["snapshotID"],
+		TaskID:     vars["taskID"],
+		Name:       state.name,
+		Status:     rw.status,
+		Duration:   int64(time.Since(start) / time.Millisecond),
+	}
+
+	if tx, ok := context.Transaction(ctx); ok && tx.ID != nil {
+		rec.TransactionID = tx.ID.String()
+	}
+	if route, ok := context.Route(ctx); ok {
+		rec.Operation = route.GetName()
+	}
+	if rec.Service != "" {
+		rec.InstanceID = getAuditInstanceID(
+			ctx, state.instanceIDs, rec.Service)
+	}
+
+	if err != nil {
+		rec.Status = getStatus(err)
+		rec.Error = err.Error()
+	}
+
+	// the outcome of an asynchronous request is not known until its task is
+	// complete, so the record is written once the task completes
+	if task, ok := store.Get(httputils.AsyncTaskKey).(*types.Task); ok &&
+		err == nil && rw.status == http.StatusAccepted {
+		rec.TaskID = strconv.Itoa(task.ID)
+		rec.Status = store.GetInt(httputils.AsyncTaskStatusKey)
+		go auditTask(ctx, rec, start, task.ID)
+		return nil
+	}
+
+	services.Audit(ctx, rec)
+	return err
+}
+
+// auditTask writes the record of an asynchronous request once the request's
+// task is complete.
+func auditTask(
+	ctx types.Context, rec *types.AuditRecord, start time.Time, taskID int) {
+
+	<-services.TaskWaitC(ctx, taskID)
+
+	rec.Duration = int64(time.Since(start) / time.Millisecond)
+	if task := services.TaskInspect(ctx, taskID); task != nil &&
+		task.Error != nil {
+		rec.Status = getStatus(task.Error)
+		rec.Error = task.Error.Error()
+	}
+
+	services.Audit(ctx, rec)
+}
+
+// getAuditInstanceID returns the ID of the instance sent with the request
+// for the specified service's driver.
+func getAuditInstanceID(
+	ctx types.Context, iidm types.InstanceIDMap, service string) string {
+
+	if iidm == nil {
+		return ""
+	}
+	svc := services.GetStorageService(ctx, service)
+	if svc == nil {
+		return ""
+	}
+	if iid, ok := iidm[strings.ToLower(svc.Driver().Name())]; ok {
+		return iid.ID
+	}
+	return ""
+}
@@ -0,0 +1,351 @@
+package handlers
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/subtle"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	log "github.com/Sirupsen/logrus"
+	gofig "github.com/akutz/gofig/types"
+	"github.com/akutz/goof"
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/gorilla/mux"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/api/utils"
+)
+
+// authHandler is a global HTTP filter for authenticating requests with a
+// bearer token and authorizing the authenticated identity for the requested
+// route and service.
+type authHandler struct {
+	handler types.APIFunc
+	tokens  []*authToken
+	jwtKey  interface{}
+}
+
+// authToken is a static token defined in the server's configuration.
+type authToken struct {
+	token    []byte
+	identity *types.AuthIdentity
+}
+
+// attachRoutes are the routes that require the attach role. All other routes
+// that do not use the GET or HEAD methods require the admin role. Attaching or
+// detaching a volume on an instance other than the caller's own requires the
+// admin role as well, which is enforced by the target instance handler.
+var attachRoutes = map[string]bool{
+	"volumeAttach":            true,
+	"volumeDetach":            true,
+	"volumesDetachAll":        true,
+	"volumesDetachForService": true,
+}
+
+// adminRoutes are the routes that require the admin role regardless of their
+// HTTP method.
+var adminRoutes = map[string]bool{
+	"audit":      true,
+	"helpConfig": true,
+	"helpEnv":    true,
+}
+
+// multiServiceRoutes are the routes without a service that either return no
+// data that belongs to a service or that check the roles granted for each
+// service themselves, such as by omitting the services for which a role is not
+// granted. These routes require the role to be granted for at least one
+// service. All other routes without a service require the role to be granted
+// for all services.
+var multiServiceRoutes = map[string]bool{
+	"root":            true,
+	"help":            true,
+	"version":         true,
+	"executors":       true,
+	"executorInspect": true,
+	"executorHead":    true,
+	"services":        true,
+	"volumes":         true,
+	"snapshots":       true,
+	"events":          true,
+	"tasks":           true,
+	"taskInspect":     true,
+	"taskCancel":      true,
+}
+
+// NewAuthHandler returns a new global filter for authenticating requests with
+// the static tokens and JWT key defined in the server's configuration.
+func NewAuthHandler(
+	ctx types.Context, config gofig.Config) (types.Middleware, error) {
+
+	h := &authHandler{}
+
+	tokens, err := parseAuthTokens(config.Get(types.ConfigServerAuthTokens))
+	if err != nil {
+		return nil, err
+	}
+	h.tokens = tokens
+
+	if key := config.GetString(types.ConfigServerAuthJWTKey); key != "" {
+		h.jwtKey = []byte(key)
+	} else if keyFile := config.GetString(
+		types.ConfigServerAuthJWTKeyFile); keyFile != "" {
+		if h.jwtKey, err = readJWTKeyFile(keyFile); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(h.tokens) == 0 && h.jwtKey == nil {
+		return nil, goof.New("auth enabled without tokens or jwt key")
+	}
+
+	ctx.WithFields(log.Fields{
+		"tokens": len(h.tokens),
+		"jwt":    h.jwtKey != nil,
+	}).Info("configured auth handler")
+
+	return h, nil
+}
+
+func (h *authHandler) Name() string {
+	return "auth-handler"
+}
+
+func (h *authHandler) Handler(m types.APIFunc) types.APIFunc {
+	return (&authHandler{m, h.tokens, h.jwtKey}).Handle
+}
+
+// Handle is the type's Handler function.
+func (h *authHandler) Handle(
+	ctx types.Context,
+	w http.ResponseWriter,
+	req *http.Request,
+	store types.Store) error {
+
+	token, err := getBearerToken(req)
+	if err != nil {
+		return err
+	}
+
+	identity, err := h.authenticate(token)
+	if err != nil {
+		return err
+	}
+	if state := getAuditState(ctx); state != nil {
+		state.user = identity.User
+	}
+
+	// the service is read from the route's path rather than the store, which
+	// also holds the request's query parameters
+	var (
+		service   = mux.Vars(req)["service"]
+		routeName = getRouteName(ctx)
+		required  = getRequiredRole(routeName, req)
+		granted   = identity.Grants.Role(service)
+	)
+	if service == "" && multiServiceRoutes[routeName] {
+		granted = identity.Grants.MaxRole()
+	}
+	if granted < required {
+		return utils.NewForbiddenError(
+			identity.User, service, required, granted)
+	}
+
+	ctx = ctx.WithValue(context.UserKey, identity.User)
+	ctx = ctx.WithValue(context.AuthIdentityKey, identity)
+	ctx.WithField("service", service).Debug("authorized request")
+
+	return h.handler(ctx, w, req, store)
+}
+
+func (h *authHandler) authenticate(token string) (*types.AuthIdentity, error) {
+	for _, t := range h.tokens {
+		if subtle.ConstantTimeCompare(t.token, []byte(token)) == 1 {
+			return t.identity, nil
+		}
+	}
+
+	if h.jwtKey == nil {
+		return nil, utils.NewUnauthorizedError("invalid token")
+	}
+
+	jwtToken, err := jwt.Parse(token, h.getJWTKey)
+	if err != nil || !jwtToken.Valid {
+		return nil, utils.NewUnauthorizedError("invalid token")
+	}
+
+	claims, ok := jwtToken.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, utils.NewUnauthorizedError("invalid token claims")
+	}
+
+	user, _ := claims["sub"].(string)
+	if user == "" {
+		return nil, utils.NewUnauthorizedError("missing token subject")
+	}
+
+	grants, err := parseAuthGrants(claims["grants"])
+	if err != nil {
+		return nil, utils.NewUnauthorizedError("invalid token grants")
+	}
+
+	return &types.AuthIdentity{User: user, Grants: grants}, nil
+}
+
+// getJWTKey returns the key used to verify a JWT. The token's signing method
+// must match the type of the configured key so that a token signed with a
+// public key as an HMAC secret is rejected.
+func (h *authHandler) getJWTKey(token *jwt.Token) (interface{}, error) {
+	var ok bool
+	switch h.jwtKey.(type) {
+	case []byte:
+		_, ok = token.Method.(*jwt.SigningMethodHMAC)
+	case *rsa.PublicKey:
+		_, ok = token.Method.(*jwt.SigningMethodRSA)
+	case *ecdsa.PublicKey:
+		_, ok = token.Method.(*jwt.SigningMethodECDSA)
+	}
+	if !ok {
+		return nil, goof.WithField(
+			"alg", token.Header["alg"], "unexpected signing method")
+	}
+	return h.jwtKey, nil
+}
+
+func getBearerToken(req *http.Request) (string, error) {
+	hdr := req.Header.Get(types.AuthorizationHeader)
+	if hdr == "" {
+		return "", utils.NewUnauthorizedError("missing token")
+	}
+	parts := strings.SplitN(hdr, " ", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], "bearer") {
+		return "", utils.NewUnauthorizedError("invalid authorization scheme")
+	}
+	token := strings.TrimSpace(parts[1])
+	if token == "" {
+		return "", utils.NewUnauthorizedError("missing token")
+	}
+	return token, nil
+}
+
+func getRouteName(ctx types.Context) string {
+	if route, ok := context.Route(ctx); ok {
+		return route.GetName()
+	}
+	return ""
+}
+
+func getRequiredRole(routeName string, req *http.Request) types.AuthRole {
+	if adminRoutes[routeName] {
+		return types.AuthRoleAdmin
+	}
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		return types.AuthRoleRead
+	}
+	if attachRoutes[routeName] {
+		return types.AuthRoleAttach
+	}
+	return types.AuthRoleAdmin
+}
+
+func readJWTKeyFile(path string) (interface{}, error) {
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, goof.WithFieldE("path", path, "error reading jwt key", err)
+	}
+	if key, err := jwt.ParseRSAPublicKeyFromPEM(buf); err == nil {
+		return key, nil
+	}
+	if key, err := jwt.ParseECPublicKeyFromPEM(buf); err == nil {
+		return key, nil
+	}
+	return nil, goof.WithField("path", path, "invalid jwt key")
+}
+
+// parseAuthTokens parses the static tokens from the server's configuration.
+// The tokens are a map of token names to each token's definition:
+//
+//	tokens:
+//	  ops:
+//	    token: 5f3c...
+//	    user:  ops
+//	    grants:
+//	      "*":    read
+//	      ebs-00: admin
+func parseAuthTokens(v interface{}) ([]*authToken, error) {
+	if v == nil {
+		return nil, nil
+	}
+	tokensMap, ok := toStringMap(v)
+	if !ok {
+		return nil, goof.WithField(
+			"configKey", types.ConfigServerAuthTokens, "invalid format")
+	}
+
+	var tokens []*authToken
+	for name, tv := range tokensMap {
+		tm, ok := toStringMap(tv)
+		if !ok {
+			return nil, goof.WithField("name", name, "invalid token format")
+		}
+		token := fmt.Sprintf("%v", tm["token"])
+		if tm["token"] == nil || token == "" {
+			return nil, goof.WithField("name", name, "missing token")
+		}
+		user := name
+		if u, ok := tm["user"].(string); ok && u != "" {
+			user = u
+		}
+		grants, err := parseAuthGrants(tm["grants"])
+		if err != nil {
+			return nil, goof.WithFieldE("name", name, "invalid grants", err)
+		}
+		tokens = append(tokens, &authToken{
+			token:    []byte(token),
+			identity: &types.AuthIdentity{User: user, Grants: grants},
+		})
+	}
+
+	return tokens, nil
+}
+
+func parseAuthGrants(v interface{}) (types.AuthGrants, error) {
+	grants := types.AuthGrants{}
+	if v == nil {
+		return grants, nil
+	}
+	gm, ok := toStringMap(v)
+	if !ok {
+		return nil, goof.New("invalid grants format")
+	}
+	for service, rv := range gm {
+		rs, _ := rv.(string)
+		role := types.ParseAuthRole(rs)
+		if role == types.AuthRoleNone {
+			return nil, goof.WithFields(goof.Fields{
+				"service": service,
+				"role":    rv,
+			}, "invalid role")
+		}
+		grants[strings.ToLower(service)] = role
+	}
+	return grants, nil
+}
+
+func toStringMap(v interface{}) (map[string]interface{}, bool) {
+	switch tv := v.(type) {
+	case map[string]interface{}:
+		return tv, true
+	case map[interface{}]interface{}:
+		m := map[string]interface{}{}
+		for k, v := range tv {
+			m[fmt.Sprintf("%v", k)] = v
+		}
+		return m, true
+	default:
+		return nil, false
+	}
+}
@@ -52,6 +52,20 @@ func getStatus(err error) int {
 		return http.StatusUnauthorized
 	case *types.ErrNotFound:
 		return http.StatusNotFound
+	case *types.ErrUnauthorized:
+		return http.StatusUnauthorized
+	case *types.ErrForbidden:
+		return http.StatusForbidden
+	case *types.ErrPolicyViolation:
+		return http.StatusForbidden
+	case *types.ErrBadFilter:
+		return http.StatusBadRequest
+	case *types.ErrBadInstanceID:
+		return http.StatusBadRequest
+	case *types.ErrBadPage:
+		return http.StatusBadRequest
+	case *types.ErrTaskCompleted:
+		return http.StatusConflict
 	default:
 		return http.StatusInternalServerError
 	}
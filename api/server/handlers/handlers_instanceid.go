@@ -47,6 +47,10 @@ func (h *instanceIDHandler) Handle(
 		valMap[strings.ToLower(val.Driver)] = val
 	}
 
+	if state := getAuditState(ctx); state != nil {
+		state.instanceIDs = valMap
+	}
+
 	ctx = ctx.WithValue(context.AllInstanceIDsKey, valMap)
 	return h.handler(ctx, w, req, store)
 }
@@ -59,6 +59,13 @@ func (h *loggingHandler) Handle(
 	req *http.Request,
 	store types.Store) error {
 
+	// a streamed response cannot be recorded, so only the request is logged
+	if isEventStreamRequest(req) {
+		fmt.Fprintln(h.writer, string(buildCommonLogLine(
+			req, *req.URL, time.Now(), http.StatusOK, 0)))
+		return h.handler(ctx, w, req, store)
+	}
+
 	bw := &bytes.Buffer{}
 	defer func(w io.Writer) {
 		h.writer.Write(bw.Bytes())
@@ -67,7 +74,7 @@ func (h *loggingHandler) Handle(
 	var err error
 	var reqDump []byte
 	if h.logRequests {
-		if reqDump, err = httputil.DumpRequest(req, true); err != nil {
+		if reqDump, err = dumpRequest(req); err != nil {
 			return err
 		}
 	}
@@ -138,6 +145,18 @@ func logResponse(
 	}
 }
 
+// dumpRequest dumps the request with the value of its authorization header
+// redacted.
+func dumpRequest(req *http.Request) ([]byte, error) {
+	auth := req.Header.Get(types.AuthorizationHeader)
+	if auth == "" {
+		return httputil.DumpRequest(req, true)
+	}
+	req.Header.Set(types.AuthorizationHeader, "******")
+	defer req.Header.Set(types.AuthorizationHeader, auth)
+	return httputil.DumpRequest(req, true)
+}
+
 func isBinaryContent(headers http.Header) bool {
 	v, ok := headers["Content-Type"]
 	if !ok || len(v) == 0 {
@@ -146,6 +165,10 @@ func isBinaryContent(headers http.Header) bool {
 	return v[0] == "application/octet-stream"
 }
 
+func isEventStreamRequest(req *http.Request) bool {
+	return strings.Contains(req.Header.Get("Accept"), "text/event-stream")
+}
+
 // buildCommonLogLine builds a log entry for req in Apache Common Log Format.
 // ts is the timestamp with which the entry should be logged.
 // status and size are used to provide the response HTTP status and size.
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/api/utils/metrics"
+)
+
+// metricsHandler is a global HTTP filter that records the number and latency
+// of the requests handled by each route.
+type metricsHandler struct {
+	handler types.APIFunc
+}
+
+// NewMetricsHandler returns a new global filter that records the number and
+// latency of the requests handled by each route.
+func NewMetricsHandler() types.Middleware {
+	return &metricsHandler{}
+}
+
+func (h *metricsHandler) Name() string {
+	return "metrics-handler"
+}
+
+func (h *metricsHandler) Handler(m types.APIFunc) types.APIFunc {
+	return (&metricsHandler{m}).Handle
+}
+
+// Handle is the type's Handler function.
+func (h *metricsHandler) Handle(
+	ctx types.Context,
+	w http.ResponseWriter,
+	req *http.Request,
+	store types.Store) error {
+
+	start := time.Now()
+	rw := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	err := h.handler(ctx, rw, req, store)
+
+	status := rw.status
+	if err != nil {
+		status = getStatus(err)
+	}
+
+	var routeName string
+	if route, ok := context.Route(ctx); ok {
+		routeName = route.GetName()
+	}
+
+	metrics.ObserveRequest(routeName, req.Method, status, time.Since(start))
+	return err
+}
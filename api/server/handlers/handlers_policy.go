@@ -0,0 +1,293 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	gofig "github.com/akutz/gofig/types"
+	"github.com/akutz/goof"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/api/utils"
+)
+
+// policyHandler is an HTTP filter for enforcing a service's quotas and
+// policies on requests that create, copy, resize, or update volumes.
+type policyHandler struct {
+	handler types.APIFunc
+}
+
+// NewPolicyHandler returns a new filter for enforcing a service's quotas and
+// policies. The filter must be preceded by the service validator, the
+// storage session handler, and the post args handler.
+func NewPolicyHandler() types.Middleware {
+	return &policyHandler{}
+}
+
+func (h *policyHandler) Name() string {
+	return "policy-handler"
+}
+
+func (h *policyHandler) Handler(m types.APIFunc) types.APIFunc {
+	return (&policyHandler{m}).Handle
+}
+
+// servicePolicy is a service's quotas and policies. A zero or empty value
+// indicates the policy is not enforced.
+type servicePolicy struct {
+	service        string
+	maxVolumes     int
+	maxCapacity    int64
+	volTypes       []string
+	maxIOPS        int64
+	requiredFields []string
+}
+
+func newServicePolicy(service string, config gofig.Config) *servicePolicy {
+	p := &servicePolicy{service: service}
+	p.maxVolumes = config.GetInt(types.ConfigPolicyMaxVolumes)
+	p.maxCapacity = int64(config.GetInt(types.ConfigPolicyMaxCapacity))
+	p.volTypes = getPolicyList(config, types.ConfigPolicyTypes)
+	p.maxIOPS = int64(config.GetInt(types.ConfigPolicyMaxIOPS))
+	p.requiredFields = getPolicyList(config, types.ConfigPolicyRequiredFields)
+	return p
+}
+
+// getPolicyList returns the list value for the given key. The value may be
+// a YAML list or a comma-separated string, such as when the value is
+// provided via an environment variable.
+func getPolicyList(config gofig.Config, key string) []string {
+	var list []string
+	for _, v := range config.GetStringSlice(key) {
+		for _, s := range strings.Split(v, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				list = append(list, s)
+			}
+		}
+	}
+	return list
+}
+
+func (p *servicePolicy) enforcesCapacity() bool {
+	return p.maxVolumes > 0 || p.maxCapacity > 0
+}
+
+// Handle is the type's Handler function.
+func (h *policyHandler) Handle(
+	ctx types.Context,
+	w http.ResponseWriter,
+	req *http.Request,
+	store types.Store) error {
+
+	service := context.MustService(ctx)
+	p := newServicePolicy(service.Name(), service.Config())
+
+	var err error
+	switch reqObj := ctx.Value("reqObj").(type) {
+	case *types.VolumeCreateRequest:
+		err = p.checkCreate(ctx, service, reqObj, store)
+	case *types.VolumeCopyRequest:
+		err = p.checkCopy(ctx, service, reqObj, store)
+	case *types.VolumeResizeRequest:
+		err = p.checkResize(ctx, service, reqObj, store)
+	case *types.VolumeUpdateRequest:
+		err = p.checkUpdate(reqObj)
+	}
+	if err != nil {
+		return err
+	}
+
+	return h.handler(ctx, w, req, store)
+}
+
+// CheckVolumeCreatePolicy enforces a service's quotas and policies on a
+// request to create a volume that is not received by the HTTP server, such
+// as a CreateVolume request received by the CSI controller. The store holds
+// the ID of the snapshot from which the volume is created, if any.
+func CheckVolumeCreatePolicy(
+	ctx types.Context,
+	service types.StorageService,
+	reqObj *types.VolumeCreateRequest,
+	store types.Store) error {
+
+	p := newServicePolicy(service.Name(), service.Config())
+	return p.checkCreate(ctx, service, reqObj, store)
+}
+
+func (p *servicePolicy) checkCreate(
+	ctx types.Context,
+	service types.StorageService,
+	reqObj *types.VolumeCreateRequest,
+	store types.Store) error {
+
+	if len(p.volTypes) > 0 {
+		volType := ""
+		if reqObj.Type != nil {
+			volType = *reqObj.Type
+		}
+		if !containsString(p.volTypes, volType) {
+			return p.violation("volume type not allowed", goof.Fields{
+				"type":         volType,
+				"allowedTypes": p.volTypes,
+			})
+		}
+	}
+
+	if p.maxIOPS > 0 && reqObj.IOPS != nil && *reqObj.IOPS > p.maxIOPS {
+		return p.violation("maximum iops exceeded", goof.Fields{
+			"iops":    *reqObj.IOPS,
+			"maxIOPS": p.maxIOPS,
+		})
+	}
+
+	if err := p.checkRequiredFields(reqObj.Fields); err != nil {
+		return err
+	}
+
+	if !p.enforcesCapacity() {
+		return nil
+	}
+
+	var size int64
+	if reqObj.Size != nil {
+		size = *reqObj.Size
+	} else if store.IsSet("snapshotID") {
+		snap, err := service.Driver().SnapshotInspect(
+			ctx, store.GetString("snapshotID"), store)
+		if err != nil {
+			return err
+		}
+		size = snap.VolumeSize
+	}
+
+	return p.checkCapacity(ctx, service, 1, size)
+}
+
+func (p *servicePolicy) checkCopy(
+	ctx types.Context,
+	service types.StorageService,
+	reqObj *types.VolumeCopyRequest,
+	store types.Store) error {
+
+	if err := p.checkRequiredFields(reqObj.Fields); err != nil {
+		return err
+	}
+
+	if !p.enforcesCapacity() {
+		return nil
+	}
+
+	vol, err := service.Driver().VolumeInspect(
+		ctx,
+		store.GetString("volumeID"),
+		&types.VolumeInspectOpts{Opts: store})
+	if err != nil {
+		return err
+	}
+
+	return p.checkCapacity(ctx, service, 1, vol.Size)
+}
+
+func (p *servicePolicy) checkResize(
+	ctx types.Context,
+	service types.StorageService,
+	reqObj *types.VolumeResizeRequest,
+	store types.Store) error {
+
+	if p.maxCapacity <= 0 {
+		return nil
+	}
+
+	vol, err := service.Driver().VolumeInspect(
+		ctx,
+		store.GetString("volumeID"),
+		&types.VolumeInspectOpts{Opts: store})
+	if err != nil {
+		return err
+	}
+
+	return p.checkCapacity(ctx, service, 0, reqObj.Size-vol.Size)
+}
+
+func (p *servicePolicy) checkUpdate(reqObj *types.VolumeUpdateRequest) error {
+	for _, k := range reqObj.RemoveFields {
+		if containsString(p.requiredFields, k) {
+			return p.violation(
+				"cannot remove required field", goof.Fields{"field": k})
+		}
+	}
+	for k, v := range reqObj.Fields {
+		if v == "" && containsString(p.requiredFields, k) {
+			return p.violation(
+				"cannot remove required field", goof.Fields{"field": k})
+		}
+	}
+	return nil
+}
+
+// checkRequiredFields verifies that the fields a request sets on a new volume
+// include a non-empty value for each of the required fields.
+func (p *servicePolicy) checkRequiredFields(fields map[string]string) error {
+	for _, k := range p.requiredFields {
+		if fields[k] == "" {
+			return p.violation(
+				"missing required field", goof.Fields{"field": k})
+		}
+	}
+	return nil
+}
+
+// checkCapacity verifies that adding the specified number of volumes and
+// the specified amount of capacity (GB) does not exceed the service's
+// quotas.
+func (p *servicePolicy) checkCapacity(
+	ctx types.Context,
+	service types.StorageService,
+	addVolumes int,
+	addCapacity int64) error {
+
+	vols, err := service.Driver().Volumes(
+		ctx, &types.VolumesOpts{Opts: utils.NewStore()})
+	if err != nil {
+		return err
+	}
+
+	if p.maxVolumes > 0 && len(vols)+addVolumes > p.maxVolumes {
+		return p.violation("maximum volume count exceeded", goof.Fields{
+			"volumes":    len(vols),
+			"maxVolumes": p.maxVolumes,
+		})
+	}
+
+	if p.maxCapacity > 0 {
+		var capacity int64
+		for _, v := range vols {
+			capacity += v.Size
+		}
+		if capacity+addCapacity > p.maxCapacity {
+			return p.violation("maximum capacity exceeded", goof.Fields{
+				"capacity":    capacity,
+				"requested":   addCapacity,
+				"maxCapacity": p.maxCapacity,
+			})
+		}
+	}
+
+	return nil
+}
+
+func (p *servicePolicy) violation(msg string, fields goof.Fields) error {
+	return utils.NewPolicyViolationError(
+		p.service, fmt.Sprintf("policy violation: %s", msg), fields)
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
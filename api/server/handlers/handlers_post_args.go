@@ -47,6 +47,11 @@ func (h *postArgsHandler) Handle(
 	v := reflect.ValueOf(reqObj).Elem()
 	t := v.Type()
 
+	// the request object's fields are also kept apart from the store, which
+	// holds the request's query parameters, so that the audit record names
+	// the object the request named
+	args := utils.NewStore()
+
 	for i := 0; i < v.NumField(); i++ {
 		ft := t.Field(i)
 		fv := v.Field(i).Interface()
@@ -59,6 +64,16 @@ func (h *postArgsHandler) Handle(
 		default:
 			// add it to the store
 			store.Set(getFieldName(ft), fv)
+			args.Set(getFieldName(ft), fv)
+		}
+	}
+
+	if state := getAuditState(ctx); state != nil {
+		for _, k := range auditNameKeys {
+			if v := args.GetString(k); v != "" {
+				state.name = v
+				break
+			}
 		}
 	}
 
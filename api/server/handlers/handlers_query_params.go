@@ -5,6 +5,7 @@ import (
 	"strconv"
 
 	log "github.com/Sirupsen/logrus"
+	"github.com/gorilla/mux"
 
 	"github.com/codedellemc/libstorage/api/types"
 )
@@ -36,7 +37,14 @@ func (h *queryParamsHandler) Handle(
 	req *http.Request,
 	store types.Store) error {
 
+	vars := mux.Vars(req)
+
 	for k, v := range req.URL.Query() {
+		// a query parameter may not replace one of the route's path
+		// variables, such as the service that the request is authorized for
+		if _, ok := vars[k]; ok {
+			continue
+		}
 		ctx.WithFields(log.Fields{
 			"key":        k,
 			"value":      v,
@@ -105,11 +105,13 @@ func (h *schemaValidator) Handle(
 		return err
 	}
 
-	// write the recorded result of the next handler to the resposne writer
-	w.WriteHeader(rec.Code)
+	// write the recorded result of the next handler to the resposne writer.
+	// the headers must be copied before the status code is written or they
+	// are ignored.
 	for k, v := range rec.HeaderMap {
 		w.Header()[k] = v
 	}
+	w.WriteHeader(rec.Code)
 	if _, err = w.Write(resBody); err != nil {
 		return err
 	}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	log "github.com/Sirupsen/logrus"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/api/utils"
+)
+
+// targetInstanceHandler is an HTTP filter for replacing the caller's instance
+// ID with the instance ID targeted by an attach or detach request.
+type targetInstanceHandler struct {
+	handler types.APIFunc
+}
+
+// NewTargetInstanceHandler returns a new filter for replacing the caller's
+// instance ID with the instance ID targeted by an attach or detach request.
+// The filter must be preceded by the post args handler.
+//
+// Targeting an instance other than the caller's own requires the auth handler
+// to be enabled and the admin role for the requested service.
+func NewTargetInstanceHandler() types.Middleware {
+	return &targetInstanceHandler{}
+}
+
+func (h *targetInstanceHandler) Name() string {
+	return "target-instance-handler"
+}
+
+func (h *targetInstanceHandler) Handler(m types.APIFunc) types.APIFunc {
+	return (&targetInstanceHandler{m}).Handle
+}
+
+// Handle is the type's Handler function.
+func (h *targetInstanceHandler) Handle(
+	ctx types.Context,
+	w http.ResponseWriter,
+	req *http.Request,
+	store types.Store) error {
+
+	iid, ok := store.Get("instanceID").(*types.InstanceID)
+	if !ok || iid == nil {
+		return h.handler(ctx, w, req, store)
+	}
+
+	// requests for all services have no single driver to which the target
+	// instance ID belongs
+	service, ok := context.Service(ctx)
+	if !ok {
+		return utils.NewBadInstanceIDError(
+			"", "a target instance requires a service")
+	}
+
+	driverName := service.Driver().Name()
+	if iid.Driver == "" {
+		iid.Driver = driverName
+	} else if !strings.EqualFold(iid.Driver, driverName) {
+		return utils.NewBadInstanceIDError(
+			service.Name(), "instance ID is for driver "+iid.Driver)
+	}
+	if iid.ID == "" {
+		return utils.NewBadInstanceIDError(service.Name(), "missing ID")
+	}
+
+	if callerIID, ok := context.InstanceID(ctx); ok && callerIID.ID == iid.ID {
+		return h.handler(ctx, w, req, store)
+	}
+
+	// without an authenticated identity there is no way to verify the caller
+	// may act on behalf of another instance
+	identity, ok := context.AuthIdentity(ctx)
+	if !ok {
+		return utils.NewForbiddenError(
+			"", service.Name(), types.AuthRoleAdmin, types.AuthRoleNone)
+	}
+	granted := identity.Grants.Role(service.Name())
+	if granted < types.AuthRoleAdmin {
+		return utils.NewForbiddenError(
+			identity.User, service.Name(), types.AuthRoleAdmin, granted)
+	}
+
+	ctx.WithFields(log.Fields{
+		"service":        service.Name(),
+		"targetInstance": iid.ID,
+	}).Info("targeting remote instance")
+
+	// the caller's local devices do not describe the target instance
+	ctx = ctx.WithValue(context.InstanceIDKey, iid)
+	ctx = ctx.WithValue(context.LocalDevicesKey, nil)
+
+	return h.handler(ctx, w, req, store)
+}
@@ -4,12 +4,28 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	gofig "github.com/akutz/gofig/types"
 
 	"github.com/codedellemc/libstorage/api/server/services"
 	"github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/api/utils"
+)
+
+const (
+	// AsyncTaskKey is the key of the store value set to the task written to
+	// the response of an asynchronous request so that the handlers wrapping
+	// the route may track the task's completion.
+	AsyncTaskKey = "asyncTask"
+
+	// AsyncTaskStatusKey is the key of the store value set to the status
+	// code the response of an asynchronous request would have had if the
+	// request was synchronous.
+	AsyncTaskStatusKey = "asyncTaskStatus"
 )
 
 // WriteJSON writes the value v to the http response stream as json with
@@ -40,10 +56,10 @@ func WriteData(w http.ResponseWriter, code int, v []byte) error {
 
 // WriteResponse writes a recorded response to a ResponseWriter.
 func WriteResponse(w http.ResponseWriter, rec *httptest.ResponseRecorder) {
-	w.WriteHeader(rec.Code)
 	for k, v := range rec.HeaderMap {
 		w.Header()[k] = v
 	}
+	w.WriteHeader(rec.Code)
 	w.Write(rec.Body.Bytes())
 }
 
@@ -57,6 +73,8 @@ func WriteTask(
 	okStatus int) error {
 
 	if store.GetBool("async") {
+		store.Set(AsyncTaskKey, task)
+		store.Set(AsyncTaskStatusKey, okStatus)
 		WriteJSON(w, http.StatusAccepted, task)
 		return nil
 	}
@@ -73,6 +91,19 @@ func WriteTask(
 		if task.Error != nil {
 			return task.Error
 		}
+		if task.Next != "" {
+			w.Header().Set(types.NextPageHeader, task.Next)
+		}
+		if len(task.ServiceErrors) > 0 {
+			setServiceErrorsHeader(w, task.ServiceErrors)
+		}
+		if store.GetBool("serviceErrors") {
+			WriteJSON(w, okStatus, &types.ServiceResult{
+				Result: task.Result,
+				Errors: task.ServiceErrors,
+			})
+			return nil
+		}
 		WriteJSON(w, okStatus, task.Result)
 	case <-exeTimeout.C:
 		WriteJSON(w, http.StatusRequestTimeout, task)
@@ -80,3 +111,35 @@ func WriteTask(
 
 	return nil
 }
+
+// setServiceErrorsHeader sets the header that contains the names of the
+// services that failed to respond to a request that lists the resources of
+// multiple services. The errors themselves are written to the response body
+// only when the request includes the serviceErrors query parameter, so that
+// they cannot be mistaken for the resources of a service by older clients.
+func setServiceErrorsHeader(
+	w http.ResponseWriter, errs types.ServiceErrorMap) {
+
+	names := make([]string, 0, len(errs))
+	for k := range errs {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	w.Header().Set(types.ServiceErrorsHeader, strings.Join(names, ","))
+}
+
+// GetQueryInt64 returns the value of the specified query parameter as an
+// int64. A zero value is returned if the parameter is not set. The value is
+// read directly from the request's URL so that it is never coerced into
+// another type.
+func GetQueryInt64(req *http.Request, key string) (int64, error) {
+	v := req.URL.Query().Get(key)
+	if v == "" {
+		return 0, nil
+	}
+	i, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return 0, utils.NewBadFilterErr(key+"="+v, err)
+	}
+	return i, nil
+}
@@ -121,6 +121,14 @@ func NewPutRoute(
 	return NewRoute(name, "PUT", path, handler, middlewares...)
 }
 
+// NewPatchRoute initializes a new route with the http method PATCH.
+func NewPatchRoute(
+	name, path string,
+	handler types.APIFunc,
+	middlewares ...types.Middleware) types.Route {
+	return NewRoute(name, "PATCH", path, handler, middlewares...)
+}
+
 // NewDeleteRoute initializes a new route with the http method DELETE.
 func NewDeleteRoute(
 	name, path string,
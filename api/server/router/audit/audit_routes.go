@@ -0,0 +1,46 @@
+package audit
+
+import (
+	"net/http"
+
+	"github.com/codedellemc/libstorage/api/server/httputils"
+	"github.com/codedellemc/libstorage/api/server/services"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+// audit returns the server's most recent audit records. The records may be
+// filtered with the query parameters service, since, and until, and limited
+// with the query parameter limit.
+func (r *router) audit(
+	ctx types.Context,
+	w http.ResponseWriter,
+	req *http.Request,
+	store types.Store) error {
+
+	opts, err := parseAuditOpts(req)
+	if err != nil {
+		return err
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, services.AuditRecords(ctx, opts))
+	return nil
+}
+
+func parseAuditOpts(req *http.Request) (*types.AuditOpts, error) {
+	opts := &types.AuditOpts{Service: req.URL.Query().Get("service")}
+
+	var err error
+	if opts.Since, err = httputils.GetQueryInt64(req, "since"); err != nil {
+		return nil, err
+	}
+	if opts.Until, err = httputils.GetQueryInt64(req, "until"); err != nil {
+		return nil, err
+	}
+	limit, err := httputils.GetQueryInt64(req, "limit")
+	if err != nil {
+		return nil, err
+	}
+	opts.Limit = int(limit)
+
+	return opts, nil
+}
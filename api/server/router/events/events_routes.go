@@ -0,0 +1,93 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/akutz/goof"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/server/services"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+// keepAliveInterval is how often a comment is written to an idle event
+// stream so that intermediaries do not close the connection.
+const keepAliveInterval = 30 * time.Second
+
+// events streams the server's events to the client using the server-sent
+// events format. The optional, repeatable query parameter "service" limits
+// the stream to events related to the specified services. The events of the
+// services for which the authenticated identity is not granted the read role
+// are omitted.
+func (r *router) events(
+	ctx types.Context,
+	w http.ResponseWriter,
+	req *http.Request,
+	store types.Store) error {
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return goof.New("streaming unsupported")
+	}
+
+	var closed <-chan bool
+	if cn, ok := w.(http.CloseNotifier); ok {
+		closed = cn.CloseNotify()
+	}
+
+	svcFilter := map[string]bool{}
+	for _, s := range req.URL.Query()["service"] {
+		svcFilter[strings.ToLower(s)] = true
+	}
+
+	events, unsubscribe := services.SubscribeEvents(ctx)
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ctx.Debug("streaming events")
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-closed:
+			ctx.Debug("event stream closed by client")
+			return nil
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return nil
+			}
+			flusher.Flush()
+		case ev, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if len(svcFilter) > 0 &&
+				!svcFilter[strings.ToLower(ev.Service)] {
+				continue
+			}
+			if !context.Authorized(ctx, ev.Service, types.AuthRoleRead) {
+				continue
+			}
+			buf, err := json.Marshal(ev)
+			if err != nil {
+				ctx.WithError(err).Error("error encoding event")
+				continue
+			}
+			if _, err := fmt.Fprintf(
+				w, "event: %s\ndata: %s\n\n", ev.Type, buf); err != nil {
+				return nil
+			}
+			flusher.Flush()
+		}
+	}
+}
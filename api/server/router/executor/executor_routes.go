@@ -12,6 +12,7 @@ import (
 	"github.com/codedellemc/libstorage/api/server/executors"
 	"github.com/codedellemc/libstorage/api/server/httputils"
 	"github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/api/utils/metrics"
 )
 
 func (r *router) executors(
@@ -39,6 +40,7 @@ func (r *router) executorInspect(
 		return err
 	}
 
+	metrics.IncExecutorDownloads(ei.Name)
 	return writeFile(w, ei)
 }
 
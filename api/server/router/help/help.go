@@ -34,9 +34,9 @@ func (r *router) Routes() []types.Route {
 func (r *router) initRoutes() {
 	r.routes = []types.Route{
 		// GET
-		httputils.NewGetRoute("version", "/help", r.helpInspect),
-		httputils.NewGetRoute("version", "/help/config", r.configInspect),
-		httputils.NewGetRoute("version", "/help/env", r.envInspect),
+		httputils.NewGetRoute("help", "/help", r.helpInspect),
+		httputils.NewGetRoute("helpConfig", "/help/config", r.configInspect),
+		httputils.NewGetRoute("helpEnv", "/help/env", r.envInspect),
 		httputils.NewGetRoute("version", "/help/version", r.versionInspect),
 	}
 }
@@ -7,6 +7,7 @@ import (
 
 	"github.com/codedellemc/libstorage/api"
 	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/registry"
 	"github.com/codedellemc/libstorage/api/server/httputils"
 	"github.com/codedellemc/libstorage/api/types"
 	"github.com/codedellemc/libstorage/api/utils"
@@ -50,17 +51,12 @@ func (r *router) configInspect(
 	req *http.Request,
 	store types.Store) error {
 
-	expectedToken, ok := ctx.Value(context.AdminTokenKey).(string)
-	if !ok {
-		return utils.NewBadAdminTokenError("missing")
-	}
-
-	actualToken := store.GetString("admin")
-	if expectedToken != actualToken {
-		return utils.NewBadAdminTokenError(actualToken)
+	if err := validateAdminToken(ctx, req); err != nil {
+		return err
 	}
 
-	httputils.WriteJSON(w, http.StatusOK, r.config.AllSettings())
+	httputils.WriteJSON(
+		w, http.StatusOK, registry.RedactConfig(r.config.AllSettings()))
 	return nil
 }
 
@@ -70,16 +66,31 @@ func (r *router) envInspect(
 	req *http.Request,
 	store types.Store) error {
 
-	expectedToken, ok := ctx.Value(context.AdminTokenKey).(string)
-	if !ok {
-		return utils.NewBadAdminTokenError("missing")
+	if err := validateAdminToken(ctx, req); err != nil {
+		return err
 	}
 
-	actualToken := store.GetString("admin")
-	if expectedToken != actualToken {
-		return utils.NewBadAdminTokenError(actualToken)
+	httputils.WriteJSON(w, http.StatusOK, registry.RedactEnv(
+		os.Environ(), registry.SecretEnvVars(r.config)...))
+	return nil
+}
+
+// validateAdminToken validates the admin token in the request's
+// Libstorage-Admintoken header. The token is not accepted as a query
+// parameter so that it is not recorded by proxies' access logs.
+func validateAdminToken(ctx types.Context, req *http.Request) error {
+	tokens, ok := ctx.Value(
+		context.AdminTokenKey).(types.AdminTokenValidator)
+	if !ok {
+		return utils.NewBadAdminTokenError("admin routes disabled")
 	}
 
-	httputils.WriteJSON(w, http.StatusOK, os.Environ())
+	token := req.Header.Get(types.AdminTokenHeader)
+	if token == "" {
+		return utils.NewBadAdminTokenError("missing token")
+	}
+	if !tokens.ValidAdminToken(token) {
+		return utils.NewBadAdminTokenError("invalid token")
+	}
 	return nil
 }
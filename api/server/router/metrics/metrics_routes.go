@@ -0,0 +1,19 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/api/utils/metrics"
+)
+
+// metrics writes the server's metrics in the Prometheus exposition format.
+func (r *router) metrics(
+	ctx types.Context,
+	w http.ResponseWriter,
+	req *http.Request,
+	store types.Store) error {
+
+	metrics.Handler().ServeHTTP(w, req)
+	return nil
+}
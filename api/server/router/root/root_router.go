@@ -26,11 +26,14 @@ func (r *router) root(
 	rootURL := fmt.Sprintf("%s://%s", proto, req.Host)
 
 	reply := []string{
+		fmt.Sprintf("%s/audit", rootURL),
+		fmt.Sprintf("%s/events", rootURL),
 		fmt.Sprintf("%s/executors", rootURL),
 		fmt.Sprintf("%s/services", rootURL),
 		fmt.Sprintf("%s/snapshots", rootURL),
 		fmt.Sprintf("%s/tasks", rootURL),
 		fmt.Sprintf("%s/help", rootURL),
+		fmt.Sprintf("%s/metrics", rootURL),
 		fmt.Sprintf("%s/volumes", rootURL),
 	}
 
@@ -48,5 +48,13 @@ func (r *router) initRoutes() {
 			r.serviceInspect,
 			handlers.NewServiceValidator(),
 			handlers.NewSchemaValidator(nil, schema.ServiceInfoSchema, nil)),
+
+		// POST
+		httputils.NewPostRoute(
+			"servicesReload",
+			"/services",
+			r.servicesReload,
+			handlers.NewSchemaValidator(nil, schema.ServiceInfoMapSchema, nil),
+		).Queries("reload"),
 	}
 }
@@ -18,6 +18,9 @@ func (r *router) servicesList(
 
 	reply := map[string]*types.ServiceInfo{}
 	for service := range services.StorageServices(ctx) {
+		if !context.Authorized(ctx, service.Name(), types.AuthRoleRead) {
+			continue
+		}
 		ctx := context.WithStorageService(ctx, service)
 		si, err := toServiceInfo(ctx, service, store)
 		if err != nil {
@@ -30,6 +33,20 @@ func (r *router) servicesList(
 	return nil
 }
 
+// servicesReload reloads the server's storage services from its configuration
+// and then responds with the reloaded services.
+func (r *router) servicesReload(
+	ctx types.Context,
+	w http.ResponseWriter,
+	req *http.Request,
+	store types.Store) error {
+
+	if err := services.Reload(ctx); err != nil {
+		return err
+	}
+	return r.servicesList(ctx, w, req, store)
+}
+
 func (r *router) serviceInspect(
 	ctx types.Context,
 	w http.ResponseWriter,
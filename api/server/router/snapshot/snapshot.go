@@ -82,6 +82,7 @@ func (r *router) initRoutes() {
 				schema.VolumeSchema,
 				func() interface{} { return &types.VolumeCreateRequest{} }),
 			handlers.NewPostArgsHandler(),
+			handlers.NewPolicyHandler(),
 		).Queries("create"),
 
 		// copy snapshot
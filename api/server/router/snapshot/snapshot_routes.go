@@ -2,6 +2,7 @@ package snapshot
 
 import (
 	"net/http"
+	"sync"
 
 	"github.com/akutz/goof"
 	"github.com/codedellemc/libstorage/api/context"
@@ -10,6 +11,8 @@ import (
 	"github.com/codedellemc/libstorage/api/server/services"
 	"github.com/codedellemc/libstorage/api/types"
 	"github.com/codedellemc/libstorage/api/utils"
+	"github.com/codedellemc/libstorage/api/utils/filters"
+	"github.com/codedellemc/libstorage/api/utils/paging"
 	"github.com/codedellemc/libstorage/api/utils/schema"
 )
 
@@ -19,13 +22,30 @@ func (r *router) snapshots(
 	req *http.Request,
 	store types.Store) error {
 
+	filter, err := parseFilter(store)
+	if err != nil {
+		return err
+	}
+	if filter != nil {
+		store.Set("filter", filter)
+	}
+
+	pg, err := parsePage(store)
+	if err != nil {
+		return err
+	}
+
 	var (
-		tasks   = map[string]*types.Task{}
-		taskIDs []int
-		reply   = types.ServiceSnapshotMap{}
+		tasks    = map[string]*types.Task{}
+		reply    = types.ServiceSnapshotMap{}
+		cursors  = map[string]*paging.Cursor{}
+		cursorsL = &sync.Mutex{}
 	)
 
 	for service := range services.StorageServices(ctx) {
+		if !context.Authorized(ctx, service.Name(), types.AuthRoleRead) {
+			continue
+		}
 
 		run := func(
 			ctx types.Context,
@@ -37,41 +57,56 @@ func (r *router) snapshots(
 				return nil, err
 			}
 
-			objs, err := svc.Driver().Snapshots(ctx, store)
+			objMap, cursor, err := getFilteredSnapshots(
+				ctx, store, svc, filter, pg)
 			if err != nil {
 				return nil, err
 			}
 
-			objMap := map[string]*types.Snapshot{}
-			for _, obj := range objs {
-				objMap[obj.ID] = obj
-			}
+			cursorsL.Lock()
+			cursors[svc.Name()] = cursor
+			cursorsL.Unlock()
+
 			return objMap, nil
 		}
 
 		task := service.TaskExecute(ctx, run, schema.SnapshotMapSchema)
-		taskIDs = append(taskIDs, task.ID)
 		tasks[service.Name()] = task
 	}
 
 	run := func(ctx types.Context) (interface{}, error) {
 
-		services.TaskWaitAll(ctx, taskIDs...)
+		errs := services.TaskWaitAllServices(ctx, tasks)
+		if err := services.TaskServicesError(tasks, errs); err != nil {
+			return nil, utils.NewBatchProcessErr(reply, err)
+		}
 
 		for k, v := range tasks {
-			if v.Error != nil {
-				return nil, utils.NewBatchProcessErr(reply, v.Error)
+			if _, ok := errs[k]; ok {
+				continue
 			}
 
-			objMap, ok := v.Result.(map[string]*types.Snapshot)
+			objMap, ok := v.Result.(types.SnapshotMap)
 			if !ok {
 				return nil, utils.NewBatchProcessErr(
-					reply, goof.New("error casting to []*types.Snapshot"))
+					reply, goof.New("error casting to types.SnapshotMap"))
 			}
 			reply[k] = objMap
 		}
 
-		return reply, nil
+		result := &types.PagedResult{Result: reply, ServiceErrors: errs}
+
+		if pg != nil {
+			cursorsL.Lock()
+			defer cursorsL.Unlock()
+			// the services that failed return the same page again
+			for k := range errs {
+				cursors[k] = pg.Cursor(k)
+			}
+			result.Next = pg.Next(cursors)
+		}
+
+		return result, nil
 	}
 
 	return httputils.WriteTask(
@@ -89,23 +124,37 @@ func (r *router) snapshotsForService(
 	req *http.Request,
 	store types.Store) error {
 
+	filter, err := parseFilter(store)
+	if err != nil {
+		return err
+	}
+	if filter != nil {
+		store.Set("filter", filter)
+	}
+
+	pg, err := parsePage(store)
+	if err != nil {
+		return err
+	}
+
 	service := context.MustService(ctx)
 
 	run := func(
 		ctx types.Context,
 		svc types.StorageService) (interface{}, error) {
 
-		var reply types.SnapshotMap = map[string]*types.Snapshot{}
-
-		objs, err := svc.Driver().Snapshots(ctx, store)
+		objMap, cursor, err := getFilteredSnapshots(
+			ctx, store, svc, filter, pg)
 		if err != nil {
 			return nil, err
 		}
 
-		for _, obj := range objs {
-			reply[obj.ID] = obj
+		if pg != nil {
+			next := pg.Next(map[string]*paging.Cursor{svc.Name(): cursor})
+			return &types.PagedResult{Result: objMap, Next: next}, nil
 		}
-		return reply, nil
+
+		return objMap, nil
 	}
 
 	return httputils.WriteTask(
@@ -117,6 +166,45 @@ func (r *router) snapshotsForService(
 		http.StatusOK)
 }
 
+// getFilteredSnapshots returns the service's snapshots that match the filter.
+// If a page is requested then the page of the snapshots is returned along
+// with the position of the service's next page.
+func getFilteredSnapshots(
+	ctx types.Context,
+	store types.Store,
+	storSvc types.StorageService,
+	filter *types.Filter,
+	pg *paging.Opts) (types.SnapshotMap, *paging.Cursor, error) {
+
+	objMap := types.SnapshotMap{}
+
+	if pg.Done(storSvc.Name()) {
+		ctx.Debug("skipping service; all snapshots returned")
+		return objMap, nil, nil
+	}
+
+	objs, err := storSvc.Driver().Snapshots(ctx, store)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var snaps []*types.Snapshot
+	for _, obj := range objs {
+		if filter != nil && !filters.MatchSnapshot(filter, obj) {
+			ctx.WithField("snapshotID", obj.ID).Debug(
+				"omitted snapshot due to filter")
+			continue
+		}
+		snaps = append(snaps, obj)
+	}
+
+	snaps, cursor := pg.Snapshots(storSvc.Name(), snaps)
+	for _, obj := range snaps {
+		objMap[obj.ID] = obj
+	}
+	return objMap, cursor, nil
+}
+
 func (r *router) snapshotInspect(
 	ctx types.Context,
 	w http.ResponseWriter,
@@ -156,10 +244,18 @@ func (r *router) snapshotRemove(
 		ctx types.Context,
 		svc types.StorageService) (interface{}, error) {
 
-		return nil, svc.Driver().SnapshotRemove(
-			ctx,
-			store.GetString("snapshotID"),
-			store)
+		snapshotID := store.GetString("snapshotID")
+		err := svc.Driver().SnapshotRemove(ctx, snapshotID, store)
+		if err != nil {
+			return nil, err
+		}
+
+		services.PublishEvent(ctx, &types.Event{
+			Type:       types.EventTypeSnapshotRemoved,
+			Service:    svc.Name(),
+			SnapshotID: snapshotID,
+		})
+		return nil, nil
 	}
 
 	return httputils.WriteTask(
@@ -199,6 +295,10 @@ func (r *router) volumeCreate(
 			return nil, err
 		}
 
+		if v, err = volume.SetFields(ctx, svc, store, v); err != nil {
+			return nil, err
+		}
+
 		if volume.OnVolume != nil {
 			ok, err := volume.OnVolume(ctx, req, store, v)
 			if err != nil {
@@ -209,6 +309,13 @@ func (r *router) volumeCreate(
 			}
 		}
 
+		services.PublishEvent(ctx, &types.Event{
+			Type:       types.EventTypeVolumeCreated,
+			Service:    svc.Name(),
+			VolumeID:   v.ID,
+			Volume:     v,
+			SnapshotID: store.GetString("snapshotID"),
+		})
 		return v, nil
 	}
 
@@ -233,12 +340,27 @@ func (r *router) snapshotCopy(
 		ctx types.Context,
 		svc types.StorageService) (interface{}, error) {
 
-		return svc.Driver().SnapshotCopy(
+		s, err := svc.Driver().SnapshotCopy(
 			ctx,
 			store.GetString("snapshotID"),
 			store.GetString("snapshotName"),
 			store.GetString("destinationID"),
 			store)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if s != nil {
+			services.PublishEvent(ctx, &types.Event{
+				Type:       types.EventTypeSnapshotCreated,
+				Service:    svc.Name(),
+				VolumeID:   s.VolumeID,
+				SnapshotID: s.ID,
+				Snapshot:   s,
+			})
+		}
+		return s, nil
 	}
 
 	return httputils.WriteTask(
@@ -249,3 +371,26 @@ func (r *router) snapshotCopy(
 		service.TaskExecute(ctx, run, schema.SnapshotSchema),
 		http.StatusCreated)
 }
+
+func parsePage(store types.Store) (*paging.Opts, error) {
+	pg, err := paging.ParseOpts(store)
+	if err != nil {
+		return nil, err
+	}
+	if err := pg.ValidateSnapshots(); err != nil {
+		return nil, err
+	}
+	return pg, nil
+}
+
+func parseFilter(store types.Store) (*types.Filter, error) {
+	if !store.IsSet("filter") {
+		return nil, nil
+	}
+	fsz := store.GetString("filter")
+	filter, err := filters.CompileFilter(fsz)
+	if err != nil {
+		return nil, utils.NewBadFilterErr(fsz, err)
+	}
+	return filter, nil
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/codedellemc/libstorage/api/context"
 	"github.com/codedellemc/libstorage/api/server/httputils"
 	"github.com/codedellemc/libstorage/api/server/services"
 	"github.com/codedellemc/libstorage/api/types"
@@ -16,8 +17,17 @@ func (r *router) tasks(
 	req *http.Request,
 	store types.Store) error {
 
+	opts, err := parseTasksOpts(req)
+	if err != nil {
+		return err
+	}
+
 	tasks := map[string]*types.Task{}
 	for t := range services.Tasks(ctx) {
+		if !opts.Match(t) ||
+			authorizeTask(ctx, t, types.AuthRoleRead) != nil {
+			continue
+		}
 		tasks[fmt.Sprintf("%d", t.ID)] = t
 	}
 	httputils.WriteJSON(w, http.StatusOK, tasks)
@@ -34,7 +44,72 @@ func (r *router) taskInspect(
 	if task == nil {
 		return utils.NewNotFoundError(store.GetString("taskID"))
 	}
+	if err := authorizeTask(ctx, task, types.AuthRoleRead); err != nil {
+		return err
+	}
 
 	httputils.WriteJSON(w, http.StatusOK, task)
 	return nil
 }
+
+func (r *router) taskCancel(
+	ctx types.Context,
+	w http.ResponseWriter,
+	req *http.Request,
+	store types.Store) error {
+
+	task := services.TaskInspect(ctx, store.GetInt("taskID"))
+	if task == nil {
+		return utils.NewNotFoundError(store.GetString("taskID"))
+	}
+	if err := authorizeTask(ctx, task, types.AuthRoleAdmin); err != nil {
+		return err
+	}
+
+	task, err := services.TaskCancel(ctx, task.ID)
+	if err != nil {
+		return err
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, task)
+	return nil
+}
+
+// authorizeTask returns an error if the context's authenticated identity
+// neither created the task nor is granted the specified role for the task's
+// service. A task without a service requires the role to be granted for all
+// services.
+func authorizeTask(
+	ctx types.Context, t *types.Task, role types.AuthRole) error {
+
+	identity, ok := context.AuthIdentity(ctx)
+	if !ok || (t.User != "" && t.User == identity.User) {
+		return nil
+	}
+	if granted := identity.Grants.Role(t.Service); granted < role {
+		return utils.NewForbiddenError(identity.User, t.Service, role, granted)
+	}
+	return nil
+}
+
+// parseTasksOpts parses the query parameters used to filter the list of
+// tasks. The parameters are read directly from the request's URL so that
+// string values such as a user name are never coerced into other types.
+func parseTasksOpts(req *http.Request) (*types.TasksOpts, error) {
+	q := req.URL.Query()
+	opts := &types.TasksOpts{
+		State:   types.TaskState(q.Get("state")),
+		User:    q.Get("user"),
+		Service: q.Get("service"),
+	}
+
+	var err error
+	if opts.Since, err = httputils.GetQueryInt64(req, "since"); err != nil {
+		return nil, err
+	}
+	if opts.Until, err = httputils.GetQueryInt64(req, "until"); err != nil {
+		return nil, err
+	}
+
+	return opts, nil
+}
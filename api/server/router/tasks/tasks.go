@@ -44,5 +44,11 @@ func (r *router) initRoutes() {
 			"taskInspect",
 			"/tasks/{taskID}",
 			r.taskInspect),
+
+		// DELETE
+		httputils.NewDeleteRoute(
+			"taskCancel",
+			"/tasks/{taskID}",
+			r.taskCancel),
 	}
 }
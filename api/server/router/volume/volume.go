@@ -93,6 +93,7 @@ func (r *router) initRoutes() {
 				schema.VolumeMapSchema,
 				func() interface{} { return &types.VolumeDetachRequest{} }),
 			handlers.NewPostArgsHandler(),
+			handlers.NewTargetInstanceHandler(),
 		).Queries("detach"),
 
 		// create a new volume
@@ -107,6 +108,7 @@ func (r *router) initRoutes() {
 				schema.VolumeSchema,
 				func() interface{} { return &types.VolumeCreateRequest{} }),
 			handlers.NewPostArgsHandler(),
+			handlers.NewPolicyHandler(),
 		),
 
 		// create a new volume using an existing volume as the baseline
@@ -121,6 +123,7 @@ func (r *router) initRoutes() {
 				schema.VolumeSchema,
 				func() interface{} { return &types.VolumeCopyRequest{} }),
 			handlers.NewPostArgsHandler(),
+			handlers.NewPolicyHandler(),
 		).Queries("copy"),
 
 		// snapshot an existing volume
@@ -137,6 +140,36 @@ func (r *router) initRoutes() {
 			handlers.NewPostArgsHandler(),
 		).Queries("snapshot"),
 
+		// resize an existing volume
+		httputils.NewPostRoute(
+			"volumeResize",
+			"/volumes/{service}/{volumeID}",
+			r.volumeResize,
+			handlers.NewServiceValidator(),
+			handlers.NewStorageSessionHandler(),
+			handlers.NewSchemaValidator(
+				schema.VolumeResizeRequestSchema,
+				schema.VolumeSchema,
+				func() interface{} { return &types.VolumeResizeRequest{} }),
+			handlers.NewPostArgsHandler(),
+			handlers.NewPolicyHandler(),
+		).Queries("resize"),
+
+		// update an existing volume
+		httputils.NewPatchRoute(
+			"volumeUpdate",
+			"/volumes/{service}/{volumeID}",
+			r.volumeUpdate,
+			handlers.NewServiceValidator(),
+			handlers.NewStorageSessionHandler(),
+			handlers.NewSchemaValidator(
+				schema.VolumeUpdateRequestSchema,
+				schema.VolumeSchema,
+				func() interface{} { return &types.VolumeUpdateRequest{} }),
+			handlers.NewPostArgsHandler(),
+			handlers.NewPolicyHandler(),
+		),
+
 		// attach an existing volume
 		httputils.NewPostRoute(
 			"volumeAttach",
@@ -149,6 +182,7 @@ func (r *router) initRoutes() {
 				schema.VolumeSchema,
 				func() interface{} { return &types.VolumeAttachRequest{} }),
 			handlers.NewPostArgsHandler(),
+			handlers.NewTargetInstanceHandler(),
 		).Queries("attach"),
 
 		// detach all volumes for all services
@@ -161,6 +195,7 @@ func (r *router) initRoutes() {
 				schema.ServiceVolumeMapSchema,
 				func() interface{} { return &types.VolumeDetachRequest{} }),
 			handlers.NewPostArgsHandler(),
+			handlers.NewTargetInstanceHandler(),
 		).Queries("detach"),
 
 		// detach an individual volume
@@ -175,6 +210,7 @@ func (r *router) initRoutes() {
 				schema.VolumeSchema,
 				func() interface{} { return &types.VolumeDetachRequest{} }),
 			handlers.NewPostArgsHandler(),
+			handlers.NewTargetInstanceHandler(),
 		).Queries("detach"),
 
 		// DELETE
@@ -14,6 +14,7 @@ import (
 	"github.com/codedellemc/libstorage/api/types"
 	"github.com/codedellemc/libstorage/api/utils"
 	"github.com/codedellemc/libstorage/api/utils/filters"
+	"github.com/codedellemc/libstorage/api/utils/paging"
 	"github.com/codedellemc/libstorage/api/utils/schema"
 )
 
@@ -31,17 +32,26 @@ func (r *router) volumes(
 		store.Set("filter", filter)
 	}
 
+	pg, err := parsePage(store)
+	if err != nil {
+		return err
+	}
+
 	var (
-		tasks   = map[string]*types.Task{}
-		taskIDs []int
-		opts    = &types.VolumesOpts{
+		tasks = map[string]*types.Task{}
+		opts  = &types.VolumesOpts{
 			Attachments: store.GetAttachments(),
 			Opts:        store,
 		}
-		reply = types.ServiceVolumeMap{}
+		reply    = types.ServiceVolumeMap{}
+		cursors  = map[string]*paging.Cursor{}
+		cursorsL = &sync.Mutex{}
 	)
 
 	for service := range services.StorageServices(ctx) {
+		if !context.Authorized(ctx, service.Name(), types.AuthRoleRead) {
+			continue
+		}
 
 		run := func(
 			ctx types.Context,
@@ -54,21 +64,33 @@ func (r *router) volumes(
 				return nil, err
 			}
 
-			return getFilteredVolumes(ctx, req, store, svc, opts, filter)
+			objMap, cursor, err := getFilteredVolumes(
+				ctx, req, store, svc, opts, filter, pg)
+			if err != nil {
+				return nil, err
+			}
+
+			cursorsL.Lock()
+			cursors[svc.Name()] = cursor
+			cursorsL.Unlock()
+
+			return objMap, nil
 		}
 
 		task := service.TaskExecute(ctx, run, schema.VolumeMapSchema)
-		taskIDs = append(taskIDs, task.ID)
 		tasks[service.Name()] = task
 	}
 
 	run := func(ctx types.Context) (interface{}, error) {
 
-		services.TaskWaitAll(ctx, taskIDs...)
+		errs := services.TaskWaitAllServices(ctx, tasks)
+		if err := services.TaskServicesError(tasks, errs); err != nil {
+			return nil, utils.NewBatchProcessErr(reply, err)
+		}
 
 		for k, v := range tasks {
-			if v.Error != nil {
-				return nil, utils.NewBatchProcessErr(reply, v.Error)
+			if _, ok := errs[k]; ok {
+				continue
 			}
 
 			objMap, ok := v.Result.(types.VolumeMap)
@@ -79,7 +101,19 @@ func (r *router) volumes(
 			reply[k] = objMap
 		}
 
-		return reply, nil
+		result := &types.PagedResult{Result: reply, ServiceErrors: errs}
+
+		if pg != nil {
+			cursorsL.Lock()
+			defer cursorsL.Unlock()
+			// the services that failed return the same page again
+			for k := range errs {
+				cursors[k] = pg.Cursor(k)
+			}
+			result.Next = pg.Next(cursors)
+		}
+
+		return result, nil
 	}
 
 	return httputils.WriteTask(
@@ -105,6 +139,11 @@ func (r *router) volumesForService(
 		store.Set("filter", filter)
 	}
 
+	pg, err := parsePage(store)
+	if err != nil {
+		return err
+	}
+
 	service := context.MustService(ctx)
 
 	opts := &types.VolumesOpts{
@@ -116,7 +155,18 @@ func (r *router) volumesForService(
 		ctx types.Context,
 		svc types.StorageService) (interface{}, error) {
 
-		return getFilteredVolumes(ctx, req, store, svc, opts, filter)
+		objMap, cursor, err := getFilteredVolumes(
+			ctx, req, store, svc, opts, filter, pg)
+		if err != nil {
+			return nil, err
+		}
+
+		if pg != nil {
+			next := pg.Next(map[string]*paging.Cursor{svc.Name(): cursor})
+			return &types.PagedResult{Result: objMap, Next: next}, nil
+		}
+
+		return objMap, nil
 	}
 
 	return httputils.WriteTask(
@@ -208,38 +258,43 @@ func handleVolAttachments(
 	return f(vol.AttachmentState)
 }
 
+// getFilteredVolumes returns the service's volumes that match the filter. If
+// a page is requested then the page of the volumes is returned along with the
+// position of the service's next page.
 func getFilteredVolumes(
 	ctx types.Context,
 	req *http.Request,
 	store types.Store,
 	storSvc types.StorageService,
 	opts *types.VolumesOpts,
-	filter *types.Filter) (types.VolumeMap, error) {
+	filter *types.Filter,
+	pg *paging.Opts) (types.VolumeMap, *paging.Cursor, error) {
 
-	var (
-		filterOp    types.FilterOperator
-		filterLeft  string
-		filterRight string
-		objMap      = types.VolumeMap{}
-	)
+	objMap := types.VolumeMap{}
 
 	iid, iidOK := context.InstanceID(ctx)
 	if opts.Attachments.RequiresInstanceID() && !iidOK {
-		return nil, utils.NewMissingInstanceIDError(storSvc.Name())
+		return nil, nil, utils.NewMissingInstanceIDError(storSvc.Name())
 	}
 
+	if pg.Done(storSvc.Name()) {
+		ctx.Debug("skipping service; all volumes returned")
+		return objMap, nil, nil
+	}
+
+	// the options are copied since the driver sets the token of the
+	// service's next page on the options' page
+	svcOpts := *opts
+	svcOpts.Page = pg.VolumesPage(storSvc.Name())
+
 	ctx.WithField("attachments", opts.Attachments).Debug("querying volumes")
 
-	objs, err := storSvc.Driver().Volumes(ctx, opts)
+	objs, err := storSvc.Driver().Volumes(ctx, &svcOpts)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	if filter != nil {
-		filterOp = filter.Op
-		filterLeft = strings.ToLower(filter.Left)
-		filterRight = strings.ToLower(filter.Right)
-	}
+	var vols []*types.Volume
 
 	for _, obj := range objs {
 
@@ -249,33 +304,38 @@ func getFilteredVolumes(
 			"volumeName":  obj.Name,
 		}
 
-		if filterOp == types.FilterEqualityMatch && filterLeft == "name" {
-			ctx.WithFields(lf).Debug("checking name filter")
-			if !strings.EqualFold(obj.Name, filterRight) {
-				ctx.WithFields(lf).Debug("omitted volume due to name filter")
-				continue
-			}
-		}
-
 		if !handleVolAttachments(ctx, lf, iid, obj, opts.Attachments) {
 			continue
 		}
 
+		if filter != nil {
+			ctx.WithFields(lf).Debug("checking filter")
+			if !filters.MatchVolume(filter, obj) {
+				ctx.WithFields(lf).Debug("omitted volume due to filter")
+				continue
+			}
+		}
+
 		if OnVolume != nil {
 			ctx.WithFields(lf).Debug("invoking OnVolume handler")
 			ok, err := OnVolume(ctx, req, store, obj)
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 			if !ok {
 				continue
 			}
 		}
 
+		vols = append(vols, obj)
+	}
+
+	vols, cursor := pg.Volumes(storSvc.Name(), vols, svcOpts.Page)
+	for _, obj := range vols {
 		objMap[obj.ID] = obj
 	}
 
-	return objMap, nil
+	return objMap, cursor, nil
 }
 
 func (r *router) volumeInspect(
@@ -406,6 +466,10 @@ func (r *router) volumeCreate(
 			return nil, err
 		}
 
+		if v, err = SetFields(ctx, svc, store, v); err != nil {
+			return nil, err
+		}
+
 		if OnVolume != nil {
 			ok, err := OnVolume(ctx, req, store, v)
 			if err != nil {
@@ -419,6 +483,8 @@ func (r *router) volumeCreate(
 		if v.AttachmentState == 0 {
 			v.AttachmentState = types.VolumeAvailable
 		}
+
+		publishVolumeEvent(ctx, types.EventTypeVolumeCreated, svc, v.ID, v)
 		return v, nil
 	}
 
@@ -431,6 +497,46 @@ func (r *router) volumeCreate(
 		http.StatusCreated)
 }
 
+// SetFields sets the fields specified by the request that created a volume on
+// the new volume. The updated volume is returned.
+//
+// The new volume is removed if its fields cannot be set, such as when the
+// service's driver does not support updating volumes, so that a failed
+// request does not leave a volume behind.
+func SetFields(
+	ctx types.Context,
+	svc types.StorageService,
+	store types.Store,
+	v *types.Volume) (*types.Volume, error) {
+
+	fields, _ := store.Get("fields").(map[string]string)
+	if len(fields) == 0 {
+		return v, nil
+	}
+
+	uv, err := svc.Driver().VolumeUpdate(
+		ctx, v.ID, &types.VolumeUpdateOpts{Fields: fields, Opts: store})
+	if err == nil {
+		return uv, nil
+	}
+
+	lf := log.Fields{"service": svc.Name(), "volumeID": v.ID}
+	ctx.WithFields(lf).WithError(err).Error("error setting volume fields")
+
+	if rerr := svc.Driver().VolumeRemove(
+		ctx, v.ID, utils.NewStore()); rerr != nil {
+		ctx.WithFields(lf).WithError(rerr).Error(
+			"error removing volume after setting fields failed")
+	}
+
+	if err == types.ErrNotImplemented {
+		return nil, goof.WithField(
+			"driver", svc.Driver().Name(),
+			"driver does not support volume fields")
+	}
+	return nil, err
+}
+
 func (r *router) volumeCopy(
 	ctx types.Context,
 	w http.ResponseWriter,
@@ -453,6 +559,10 @@ func (r *router) volumeCopy(
 			return nil, err
 		}
 
+		if v, err = SetFields(ctx, svc, store, v); err != nil {
+			return nil, err
+		}
+
 		if OnVolume != nil {
 			ok, err := OnVolume(ctx, req, store, v)
 			if err != nil {
@@ -466,6 +576,8 @@ func (r *router) volumeCopy(
 		if v.AttachmentState == 0 {
 			v.AttachmentState = types.VolumeAvailable
 		}
+
+		publishVolumeEvent(ctx, types.EventTypeVolumeCreated, svc, v.ID, v)
 		return v, nil
 	}
 
@@ -490,11 +602,26 @@ func (r *router) volumeSnapshot(
 		ctx types.Context,
 		svc types.StorageService) (interface{}, error) {
 
-		return svc.Driver().VolumeSnapshot(
+		s, err := svc.Driver().VolumeSnapshot(
 			ctx,
 			store.GetString("volumeID"),
 			store.GetString("snapshotName"),
 			store)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if s != nil {
+			services.PublishEvent(ctx, &types.Event{
+				Type:       types.EventTypeSnapshotCreated,
+				Service:    svc.Name(),
+				VolumeID:   store.GetString("volumeID"),
+				SnapshotID: s.ID,
+				Snapshot:   s,
+			})
+		}
+		return s, nil
 	}
 
 	return httputils.WriteTask(
@@ -506,6 +633,104 @@ func (r *router) volumeSnapshot(
 		http.StatusCreated)
 }
 
+func (r *router) volumeResize(
+	ctx types.Context,
+	w http.ResponseWriter,
+	req *http.Request,
+	store types.Store) error {
+
+	service := context.MustService(ctx)
+
+	run := func(
+		ctx types.Context,
+		svc types.StorageService) (interface{}, error) {
+
+		v, err := svc.Driver().VolumeResize(
+			ctx,
+			store.GetString("volumeID"),
+			&types.VolumeResizeOpts{
+				Size: store.GetInt64("size"),
+				Opts: store,
+			})
+
+		if err != nil {
+			return nil, err
+		}
+
+		if OnVolume != nil {
+			ok, err := OnVolume(ctx, req, store, v)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				return nil, utils.NewNotFoundError(v.ID)
+			}
+		}
+
+		publishVolumeEvent(ctx, types.EventTypeVolumeResized, svc, v.ID, v)
+		return v, nil
+	}
+
+	return httputils.WriteTask(
+		ctx,
+		r.config,
+		w,
+		store,
+		service.TaskExecute(ctx, run, schema.VolumeSchema),
+		http.StatusOK)
+}
+
+func (r *router) volumeUpdate(
+	ctx types.Context,
+	w http.ResponseWriter,
+	req *http.Request,
+	store types.Store) error {
+
+	service := context.MustService(ctx)
+
+	run := func(
+		ctx types.Context,
+		svc types.StorageService) (interface{}, error) {
+
+		opts := &types.VolumeUpdateOpts{
+			Name:         store.GetStringPtr("name"),
+			RemoveFields: store.GetStringSlice("removeFields"),
+			Opts:         store,
+		}
+		if fields, ok := store.Get("fields").(map[string]string); ok {
+			opts.Fields = fields
+		}
+
+		v, err := svc.Driver().VolumeUpdate(
+			ctx, store.GetString("volumeID"), opts)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if OnVolume != nil {
+			ok, err := OnVolume(ctx, req, store, v)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				return nil, utils.NewNotFoundError(v.ID)
+			}
+		}
+
+		publishVolumeEvent(ctx, types.EventTypeVolumeUpdated, svc, v.ID, v)
+		return v, nil
+	}
+
+	return httputils.WriteTask(
+		ctx,
+		r.config,
+		w,
+		store,
+		service.TaskExecute(ctx, run, schema.VolumeSchema),
+		http.StatusOK)
+}
+
 func (r *router) volumeAttach(
 	ctx types.Context,
 	w http.ResponseWriter,
@@ -548,6 +773,8 @@ func (r *router) volumeAttach(
 			v.AttachmentState = types.VolumeAttached
 		}
 
+		publishVolumeEvent(ctx, types.EventTypeVolumeAttached, svc, v.ID, v)
+
 		return &types.VolumeAttachResponse{
 			Volume:      v,
 			AttachToken: attTokn,
@@ -608,6 +835,7 @@ func (r *router) volumeDetach(
 			v.AttachmentState = types.VolumeAvailable
 		}
 
+		publishVolumeEvent(ctx, types.EventTypeVolumeDetached, svc, v.ID, v)
 		return v, nil
 	}
 
@@ -635,6 +863,9 @@ func (r *router) volumeDetachAll(
 	)
 
 	for service := range services.StorageServices(ctx) {
+		if !context.Authorized(ctx, service.Name(), types.AuthRoleAttach) {
+			continue
+		}
 
 		run := func(
 			ctx types.Context,
@@ -702,6 +933,9 @@ func (r *router) volumeDetachAll(
 					v.AttachmentState = types.VolumeAvailable
 				}
 
+				publishVolumeEvent(
+					ctx, types.EventTypeVolumeDetached, svc, v.ID, v)
+
 				volumeMap[v.ID] = v
 			}
 
@@ -790,6 +1024,9 @@ func (r *router) volumeDetachAllForService(
 				v.AttachmentState = types.VolumeAvailable
 			}
 
+			publishVolumeEvent(
+				ctx, types.EventTypeVolumeDetached, svc, v.ID, v)
+
 			reply[v.ID] = v
 		}
 
@@ -817,10 +1054,14 @@ func (r *router) volumeRemove(
 		ctx types.Context,
 		svc types.StorageService) (interface{}, error) {
 
-		return nil, svc.Driver().VolumeRemove(
-			ctx,
-			store.GetString("volumeID"),
-			store)
+		volumeID := store.GetString("volumeID")
+		if err := svc.Driver().VolumeRemove(ctx, volumeID, store); err != nil {
+			return nil, err
+		}
+
+		publishVolumeEvent(
+			ctx, types.EventTypeVolumeRemoved, svc, volumeID, nil)
+		return nil, nil
 	}
 
 	return httputils.WriteTask(
@@ -832,6 +1073,33 @@ func (r *router) volumeRemove(
 		http.StatusNoContent)
 }
 
+// publishVolumeEvent publishes an event related to a volume.
+func publishVolumeEvent(
+	ctx types.Context,
+	evType types.EventType,
+	svc types.StorageService,
+	volumeID string,
+	v *types.Volume) {
+
+	services.PublishEvent(ctx, &types.Event{
+		Type:     evType,
+		Service:  svc.Name(),
+		VolumeID: volumeID,
+		Volume:   v,
+	})
+}
+
+func parsePage(store types.Store) (*paging.Opts, error) {
+	pg, err := paging.ParseOpts(store)
+	if err != nil {
+		return nil, err
+	}
+	if err := pg.ValidateVolumes(); err != nil {
+		return nil, err
+	}
+	return pg, nil
+}
+
 func parseFilter(store types.Store) (*types.Filter, error) {
 	if !store.IsSet("filter") {
 		return nil, nil
@@ -19,6 +19,7 @@ import (
 	glogrus "github.com/codedellemc/gournal/logrus"
 
 	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/csi"
 	"github.com/codedellemc/libstorage/api/server/services"
 	"github.com/codedellemc/libstorage/api/types"
 	"github.com/codedellemc/libstorage/api/utils"
@@ -33,15 +34,23 @@ import (
 
 var (
 	servers []*server
+
+	// LoadConfig is the function used to load the configuration when the
+	// servers' storage services are reloaded. The default function reads the
+	// same configuration files that are read when no configuration is
+	// provided to Serve. Programs that configure servers in another manner
+	// should replace this function in order to support reloading.
+	LoadConfig = apicnfg.NewConfig
 )
 
 type server struct {
 	name         string
-	adminToken   string
+	adminTokens  *adminTokens
 	ctx          types.Context
 	addrs        []string
 	config       gofig.Config
 	servers      []*HTTPServer
+	csi          *csi.Server
 	closeSignal  chan int
 	closedSignal chan int
 	closeOnce    *sync.Once
@@ -60,16 +69,16 @@ type server struct {
 
 func newServer(goCtx gocontext.Context, config gofig.Config) (*server, error) {
 
-	adminTokenUUID, err := types.NewUUID()
+	serverName := randomServerName()
+
+	adminTokens, err := newAdminTokens(config, serverName)
 	if err != nil {
 		return nil, err
 	}
-	adminToken := adminTokenUUID.String()
-	serverName := randomServerName()
 
 	ctx := context.New(goCtx)
 	ctx = ctx.WithValue(context.ServerKey, serverName)
-	ctx = ctx.WithValue(context.AdminTokenKey, adminToken)
+	ctx = ctx.WithValue(context.AdminTokenKey, adminTokens)
 
 	if lvl, ok := context.GetLogLevel(ctx); ok {
 		switch lvl {
@@ -118,7 +127,7 @@ func newServer(goCtx gocontext.Context, config gofig.Config) (*server, error) {
 	s := &server{
 		ctx:          ctx,
 		name:         serverName,
-		adminToken:   adminToken,
+		adminTokens:  adminTokens,
 		config:       config,
 		closeSignal:  make(chan int),
 		closedSignal: make(chan int),
@@ -154,7 +163,7 @@ func newServer(goCtx gocontext.Context, config gofig.Config) (*server, error) {
 	}
 	s.ctx.Info("initialized endpoints")
 
-	if err := services.Init(s.ctx, s.config); err != nil {
+	if err := services.Init(s.ctx, s.config, loadServerConfig); err != nil {
 		return nil, err
 	}
 	s.ctx.Info("initialized services")
@@ -167,7 +176,9 @@ func newServer(goCtx gocontext.Context, config gofig.Config) (*server, error) {
 		s.stdErr = getLogIO(logConfig.Stderr, types.ConfigLogStderr)
 	}
 
-	s.initGlobalMiddleware()
+	if err := s.initGlobalMiddleware(); err != nil {
+		return nil, err
+	}
 
 	if err := s.initRouters(); err != nil {
 		return nil, err
@@ -192,7 +203,21 @@ func Serve(
 	}
 
 	errs := make(chan error, len(s.servers))
-	srvErrs := make(chan error, len(s.servers))
+	srvErrs := make(chan error, len(s.servers)+1)
+
+	if s.config.GetString(types.ConfigCSIControllerEndpoint) != "" {
+		csiSrv, csiErrs, err := csi.ServeController(s.ctx, s.config)
+		if err != nil {
+			s.close()
+			return nil, nil, err
+		}
+		s.csi = csiSrv
+		go func() {
+			for err := range csiErrs {
+				srvErrs <- err
+			}
+		}()
+	}
 
 	for _, srv := range s.servers {
 		srv.srv.Handler = s.createMux(srv.ctx)
@@ -238,6 +263,26 @@ func Serve(
 	return s, errs, nil
 }
 
+// loadServerConfig loads the configuration used to reload a server's storage
+// services.
+func loadServerConfig() (gofig.Config, error) {
+	config, err := LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	return config.Scope(types.ConfigServer), nil
+}
+
+// reloadAdminTokens reloads the tokens that grant access to the server's
+// admin routes.
+func (s *server) reloadAdminTokens() error {
+	config, err := loadServerConfig()
+	if err != nil {
+		return err
+	}
+	return s.adminTokens.reload(config)
+}
+
 // Name returns the name of the server.
 func (s *server) Name() string {
 	return s.name
@@ -276,6 +321,18 @@ func (s *server) close() error {
 		srv.ctx.Debug("shutdown endpoint complete")
 	}
 
+	if s.csi != nil {
+		if err := s.csi.Close(); err != nil {
+			s.ctx.WithError(err).Error("error closing csi plug-in")
+		}
+	}
+
+	if err := services.CloseAudit(s.ctx); err != nil {
+		s.ctx.WithError(err).Error("error closing audit log")
+	}
+
+	s.adminTokens.close()
+
 	if s.stdOut != nil {
 		if err := s.stdOut.Close(); err != nil {
 			log.Error(err)
@@ -300,7 +357,6 @@ func CloseOnAbort() {
 	sigc := make(chan os.Signal, 1)
 	signal.Notify(sigc,
 		syscall.SIGKILL,
-		syscall.SIGHUP,
 		syscall.SIGINT,
 		syscall.SIGTERM,
 		syscall.SIGQUIT)
@@ -313,6 +369,46 @@ func CloseOnAbort() {
 	}()
 }
 
+// ReloadOnSignal is a helper function that can be called by programs, such
+// as a command line or service application, to reload the storage services
+// of all servers when the program receives a SIGHUP signal. Reloading on a
+// signal is not supported on Windows.
+func ReloadOnSignal() {
+	if len(reloadSignals) == 0 {
+		return
+	}
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, reloadSignals...)
+	go func() {
+		for range sigc {
+			log.Info("received reload signal")
+			for err := range Reload() {
+				log.WithError(err).Error("error reloading services")
+			}
+		}
+	}()
+}
+
+// Reload reloads the storage services and admin tokens of all servers. The
+// returned channel receives the error from each server that fails to reload
+// and is closed once all servers have been reloaded.
+func Reload() <-chan error {
+	errs := make(chan error)
+	go func() {
+		for _, server := range servers {
+			if err := services.Reload(server.ctx); err != nil {
+				errs <- err
+			}
+			if err := server.reloadAdminTokens(); err != nil {
+				errs <- err
+			}
+		}
+		close(errs)
+		log.Info("all servers reloaded")
+	}()
+	return errs
+}
+
 // Close closes all servers. This function can be used when a calling program
 // traps UNIX signals or when it exits gracefully.
 func Close() <-chan error {
@@ -0,0 +1,198 @@
+package server
+
+import (
+	"crypto/subtle"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	gofig "github.com/akutz/gofig/types"
+	"github.com/akutz/goof"
+
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+// adminTokens are the tokens that grant access to the server's admin routes.
+//
+// The tokens are the static tokens defined by the property
+// libstorage.server.admin.tokens and the tokens read from the file defined by
+// libstorage.server.admin.tokenFile, one per line. More than one token may be
+// valid at a time, and the file is read again whenever it is modified, so a
+// token is rotated by adding the new token, updating the clients, and then
+// removing the old token.
+//
+// When no tokens are configured a random token is generated and written to
+// a file in the run directory that only the server's user may read.
+type adminTokens struct {
+	sync.RWMutex
+	serverName string
+	static     [][]byte
+	file       string
+	fileMod    time.Time
+	fileSize   int64
+	fileTokens [][]byte
+	generated  string
+	genToken   []byte
+}
+
+func newAdminTokens(
+	config gofig.Config, serverName string) (*adminTokens, error) {
+
+	t := &adminTokens{serverName: serverName}
+	if err := t.reload(config); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// reload loads the tokens from the configuration.
+func (t *adminTokens) reload(config gofig.Config) error {
+	var static [][]byte
+	for _, v := range config.GetStringSlice(types.ConfigServerAdminTokens) {
+		for _, s := range strings.Split(v, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				static = append(static, []byte(s))
+			}
+		}
+	}
+	file := config.GetString(types.ConfigServerAdminTokenFile)
+
+	t.Lock()
+	defer t.Unlock()
+
+	t.static = static
+	t.file = file
+	t.fileMod = time.Time{}
+	t.fileSize = 0
+	t.fileTokens = nil
+
+	if file != "" {
+		if err := t.readFile(); err != nil {
+			return err
+		}
+	}
+
+	if len(static) > 0 || file != "" {
+		t.removeGenerated()
+		return nil
+	}
+
+	if t.genToken == nil {
+		if err := t.generate(); err != nil {
+			return err
+		}
+	}
+	t.static = [][]byte{t.genToken}
+	return nil
+}
+
+// generate generates a random token and writes it to a file in the run
+// directory.
+func (t *adminTokens) generate() error {
+	uuid, err := types.NewUUID()
+	if err != nil {
+		return err
+	}
+	token := []byte(uuid.String())
+	path := types.Run.Join(t.serverName + ".admintoken")
+	if err := ioutil.WriteFile(path, append(token, '\n'), 0600); err != nil {
+		return goof.WithFieldE(
+			"path", path, "error writing admin token file", err)
+	}
+	t.genToken = token
+	t.generated = path
+	return nil
+}
+
+func (t *adminTokens) removeGenerated() {
+	if t.generated == "" {
+		return
+	}
+	os.RemoveAll(t.generated)
+	t.generated = ""
+	t.genToken = nil
+}
+
+// readFile reads the token file if it was modified since it was last read.
+// The lock must be held by the caller.
+func (t *adminTokens) readFile() error {
+	fi, err := os.Stat(t.file)
+	if err != nil {
+		return goof.WithFieldE(
+			"path", t.file, "error reading admin token file", err)
+	}
+	if fi.ModTime().Equal(t.fileMod) && fi.Size() == t.fileSize {
+		return nil
+	}
+
+	buf, err := ioutil.ReadFile(t.file)
+	if err != nil {
+		return goof.WithFieldE(
+			"path", t.file, "error reading admin token file", err)
+	}
+
+	var tokens [][]byte
+	for _, line := range strings.Split(string(buf), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		tokens = append(tokens, []byte(line))
+	}
+
+	t.fileTokens = tokens
+	t.fileMod = fi.ModTime()
+	t.fileSize = fi.Size()
+	return nil
+}
+
+// ValidAdminToken returns a flag indicating whether the token grants access
+// to the server's admin routes. The token is compared to every valid token in
+// constant time.
+func (t *adminTokens) ValidAdminToken(token string) bool {
+	if token == "" {
+		return false
+	}
+
+	t.Lock()
+	if t.file != "" {
+		// the tokens that were last read remain valid if the file cannot
+		// be read again
+		t.readFile()
+	}
+	t.Unlock()
+
+	t.RLock()
+	defer t.RUnlock()
+
+	valid := 0
+	for _, tokens := range [][][]byte{t.static, t.fileTokens} {
+		for _, v := range tokens {
+			valid |= subtle.ConstantTimeCompare(v, []byte(token))
+		}
+	}
+	return valid == 1
+}
+
+// source returns a description of the tokens' source that does not include
+// the tokens themselves.
+func (t *adminTokens) source() string {
+	t.RLock()
+	defer t.RUnlock()
+	switch {
+	case t.generated != "":
+		return t.generated
+	case t.file != "":
+		return t.file
+	default:
+		return "<configured>"
+	}
+}
+
+func (t *adminTokens) close() {
+	t.Lock()
+	defer t.Unlock()
+	t.removeGenerated()
+}
@@ -5,7 +5,7 @@ import (
 	"github.com/codedellemc/libstorage/api/types"
 )
 
-func (s *server) initGlobalMiddleware() {
+func (s *server) initGlobalMiddleware() error {
 
 	s.addGlobalMiddleware(handlers.NewQueryParamsHandler())
 
@@ -16,11 +16,29 @@ func (s *server) initGlobalMiddleware() {
 			s.logHTTPResponses))
 	}
 
+	s.addGlobalMiddleware(handlers.NewMetricsHandler())
 	s.addGlobalMiddleware(handlers.NewTransactionHandler())
 	s.addGlobalMiddleware(handlers.NewErrorHandler())
+
+	// the audit handler precedes the auth handler so that requests rejected
+	// by the latter are audited as well
+	if s.config.GetBool(types.ConfigServerAuditEnabled) {
+		s.addGlobalMiddleware(handlers.NewAuditHandler())
+	}
+
+	if s.config.GetBool(types.ConfigServerAuthEnabled) {
+		authHandler, err := handlers.NewAuthHandler(s.ctx, s.config)
+		if err != nil {
+			return err
+		}
+		s.addGlobalMiddleware(authHandler)
+	}
+
 	s.addGlobalMiddleware(handlers.NewInstanceIDHandler())
 	s.addGlobalMiddleware(handlers.NewLocalDevicesHandler())
 	s.addGlobalMiddleware(handlers.NewOnRequestHandler())
+
+	return nil
 }
 
 func (s *server) initRouteMiddleware() {
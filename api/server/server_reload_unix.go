@@ -0,0 +1,12 @@
+// +build !windows
+
+package server
+
+import (
+	"os"
+	"syscall"
+)
+
+// reloadSignals are the signals that reload the servers' storage services
+// when ReloadOnSignal is called.
+var reloadSignals = []os.Signal{syscall.SIGHUP}
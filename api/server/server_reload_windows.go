@@ -0,0 +1,9 @@
+// +build windows
+
+package server
+
+import "os"
+
+// reloadSignals are the signals that reload the servers' storage services
+// when ReloadOnSignal is called. Windows does not support SIGHUP.
+var reloadSignals []os.Signal
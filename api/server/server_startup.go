@@ -65,7 +65,7 @@ func (s *server) PrintServerStartupHeader(w io.Writer) {
 	fmt.Fprint(b, strings.Repeat(" ", trunc80(n)))
 	fmt.Fprintln(b, "##")
 
-	n, _ = fmt.Fprintf(b, "##      token:      %s", s.adminToken)
+	n, _ = fmt.Fprintf(b, "##      token:      %s", s.adminTokens.source())
 	fmt.Fprint(b, strings.Repeat(" ", trunc80(n)))
 	fmt.Fprintln(b, "##")
 
@@ -21,10 +21,21 @@ type serviceContainer struct {
 	config          gofig.Config
 	storageServices map[string]types.StorageService
 	taskService     *globalTaskService
+	events          *eventBus
+	audit           *auditLog
+	loadConfig      ConfigLoader
+	reloadLock      sync.Mutex
 }
 
-// Init initializes the types.
-func Init(ctx types.Context, config gofig.Config) error {
+// ConfigLoader loads the configuration used to reload a server's storage
+// services.
+type ConfigLoader func() (gofig.Config, error)
+
+// Init initializes the types. The loader is used to load the configuration
+// when the storage services are reloaded and may be nil if reloading is not
+// supported.
+func Init(
+	ctx types.Context, config gofig.Config, loader ConfigLoader) error {
 
 	serverName, ok := context.Server(ctx)
 	if !ok {
@@ -33,15 +44,27 @@ func Init(ctx types.Context, config gofig.Config) error {
 
 	ctx.Info("initializing server services")
 
+	events := newEventBus()
 	sc := &serviceContainer{
-		taskService:     &globalTaskService{name: "global-task-service"},
+		taskService: &globalTaskService{
+			name:   "global-task-service",
+			events: events,
+		},
 		storageServices: map[string]types.StorageService{},
+		events:          events,
+		loadConfig:      loader,
 	}
 
 	if err := sc.Init(ctx, config); err != nil {
 		return err
 	}
 
+	audit, err := newAuditLog(ctx, config)
+	if err != nil {
+		return err
+	}
+	sc.audit = audit
+
 	servicesByServerRWL.Lock()
 	defer servicesByServerRWL.Unlock()
 	servicesByServer[serverName] = sc
@@ -88,9 +111,13 @@ func GetStorageService(
 // StorageServices returns a channel on which all the storage services are
 // received.
 func StorageServices(ctx types.Context) <-chan types.StorageService {
+	servicesByServerRWL.RLock()
+	svcs := getStorageServices(ctx)
+	servicesByServerRWL.RUnlock()
+
 	c := make(chan types.StorageService)
 	go func() {
-		for _, v := range getStorageServices(ctx) {
+		for _, v := range svcs {
 			c <- v
 		}
 		close(c)
@@ -105,16 +132,40 @@ func (sc *serviceContainer) initStorageServices(ctx types.Context) error {
 	if sc.config == nil {
 		panic("sc.config is nil")
 	}
-	cfgSvcs := sc.config.Get(types.ConfigServices)
+
+	cfgSvcsMap, err := getServiceConfigs(sc.config)
+	if err != nil {
+		return err
+	}
+	ctx.WithField("count", len(cfgSvcsMap)).Debug("got services map")
+
+	for serviceName, svcSettings := range cfgSvcsMap {
+		storSvc, err := newStorageService(
+			ctx, sc.config, serviceName,
+			getServiceSettings(sc.config, serviceName, svcSettings))
+		if err != nil {
+			return err
+		}
+		sc.storageServices[storSvc.name] = storSvc
+	}
+
+	return nil
+}
+
+// getServiceConfigs returns the services defined in the configuration, keyed
+// by service name. If no services are defined then a single service is
+// returned for the configured driver.
+func getServiceConfigs(config gofig.Config) (map[string]interface{}, error) {
+	cfgSvcs := config.Get(types.ConfigServices)
 	cfgSvcsMap, ok := cfgSvcs.(map[string]interface{})
 	if !ok {
-		driverName := sc.config.GetString("libstorage.driver")
+		driverName := config.GetString("libstorage.driver")
 		if driverName == "" {
 			err := goof.WithFields(goof.Fields{
 				"configKey": types.ConfigServices,
 				"obj":       cfgSvcs,
 			}, "invalid format")
-			return err
+			return nil, err
 		}
 
 		cfgSvcsMap = map[string]interface{}{
@@ -123,30 +174,41 @@ func (sc *serviceContainer) initStorageServices(ctx types.Context) error {
 			},
 		}
 	}
-	ctx.WithField("count", len(cfgSvcsMap)).Debug("got services map")
+	return cfgSvcsMap, nil
+}
 
-	for serviceName := range cfgSvcsMap {
-		serviceName = strings.ToLower(serviceName)
+func newStorageService(
+	ctx types.Context,
+	config gofig.Config,
+	serviceName string,
+	settings []interface{}) (*storageService, error) {
 
-		storSvc := &storageService{name: serviceName}
+	serviceName = strings.ToLower(serviceName)
 
-		ctx := ctx.WithValue(context.StorageServiceKey, storSvc)
-		ctx.Debug("processing service config")
+	storSvc := &storageService{
+		name:     serviceName,
+		settings: settings,
+	}
 
-		scope := fmt.Sprintf("libstorage.server.services.%s", serviceName)
-		ctx.WithField("scope", scope).Debug(
-			"getting scoped config for service")
-		config := sc.config.Scope(scope)
+	ctx = ctx.WithValue(context.StorageServiceKey, storSvc)
+	ctx.Debug("processing service config")
 
-		if err := storSvc.Init(ctx, config); err != nil {
-			return err
-		}
+	scope := getServiceScope(serviceName)
+	ctx.WithField("scope", scope).Debug(
+		"getting scoped config for service")
 
-		ctx.Info("created new service")
-		sc.storageServices[serviceName] = storSvc
+	if err := storSvc.Init(ctx, config.Scope(scope)); err != nil {
+		return nil, err
 	}
 
-	return nil
+	ctx.Info("created new service")
+	return storSvc, nil
+}
+
+// getServiceScope returns the configuration scope of a service.
+func getServiceScope(serviceName string) string {
+	return fmt.Sprintf(
+		"libstorage.server.services.%s", strings.ToLower(serviceName))
 }
 
 func getTaskService(ctx types.Context) *globalTaskService {
@@ -186,6 +248,11 @@ func TaskInspect(ctx types.Context, taskID int) *types.Task {
 	return getTaskService(ctx).TaskInspect(taskID)
 }
 
+// TaskCancel cancels the task with the specified ID.
+func TaskCancel(ctx types.Context, taskID int) (*types.Task, error) {
+	return getTaskService(ctx).TaskCancel(taskID)
+}
+
 // TaskWait blocks until the specified task is completed.
 func TaskWait(ctx types.Context, taskID int) {
 	getTaskService(ctx).TaskWait(taskID)
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"encoding/json"
+	"sync"
+
+	gofig "github.com/akutz/gofig/types"
+	"github.com/akutz/goof"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+// auditSink is a destination to which audit records are written.
+type auditSink interface {
+	// Write writes an audit record, encoded as a single line of JSON.
+	Write(buf []byte) error
+
+	// Close closes the sink.
+	Close() error
+}
+
+type newAuditSinkFunc func(
+	ctx types.Context, config gofig.Config) (auditSink, error)
+
+// auditSinks are the available audit sinks, keyed by the value of the
+// types.ConfigServerAuditSink property.
+var auditSinks = map[string]newAuditSinkFunc{}
+
+// auditLog writes audit records to the configured sink and keeps the most
+// recent records in memory so they may be queried.
+type auditLog struct {
+	sync.RWMutex
+	sink    auditSink
+	records []*types.AuditRecord
+	next    int
+	full    bool
+}
+
+func newAuditLog(ctx types.Context, config gofig.Config) (*auditLog, error) {
+	if !config.GetBool(types.ConfigServerAuditEnabled) {
+		return nil, nil
+	}
+
+	size := config.GetInt(types.ConfigServerAuditBufferSize)
+	if size <= 0 {
+		size = 1000
+	}
+	l := &auditLog{records: make([]*types.AuditRecord, size)}
+
+	sinkName := config.GetString(types.ConfigServerAuditSink)
+	if sinkName != "" {
+		newSink, ok := auditSinks[sinkName]
+		if !ok {
+			return nil, goof.WithField(
+				"sink", sinkName, "invalid audit sink")
+		}
+		sink, err := newSink(ctx, config)
+		if err != nil {
+			return nil, err
+		}
+		l.sink = sink
+	}
+
+	ctx.WithField("sink", sinkName).Info("initialized audit log")
+	return l, nil
+}
+
+func (l *auditLog) write(ctx types.Context, rec *types.AuditRecord) {
+	if l == nil {
+		return
+	}
+
+	l.Lock()
+	defer l.Unlock()
+
+	l.records[l.next] = rec
+	l.next = (l.next + 1) % len(l.records)
+	if l.next == 0 {
+		l.full = true
+	}
+
+	if l.sink == nil {
+		return
+	}
+
+	buf, err := json.Marshal(rec)
+	if err != nil {
+		ctx.WithError(err).Error("error encoding audit record")
+		return
+	}
+	if err := l.sink.Write(buf); err != nil {
+		ctx.WithError(err).Error("error writing audit record")
+	}
+}
+
+// query returns the records that match the options, oldest first.
+func (l *auditLog) query(opts *types.AuditOpts) []*types.AuditRecord {
+	records := []*types.AuditRecord{}
+	if l == nil {
+		return records
+	}
+
+	l.RLock()
+	defer l.RUnlock()
+
+	start, count := 0, l.next
+	if l.full {
+		start, count = l.next, len(l.records)
+	}
+	for i := 0; i < count; i++ {
+		rec := l.records[(start+i)%len(l.records)]
+		if opts.Match(rec) {
+			records = append(records, rec)
+		}
+	}
+
+	if opts != nil && opts.Limit > 0 && len(records) > opts.Limit {
+		records = records[len(records)-opts.Limit:]
+	}
+	return records
+}
+
+func (l *auditLog) close() error {
+	if l == nil || l.sink == nil {
+		return nil
+	}
+	return l.sink.Close()
+}
+
+func getAuditLog(ctx types.Context) *auditLog {
+	serverName, ok := context.Server(ctx)
+	if !ok {
+		panic("ctx is missing ServerName")
+	}
+
+	servicesByServerRWL.RLock()
+	defer servicesByServerRWL.RUnlock()
+	return servicesByServer[serverName].audit
+}
+
+// Audit writes a record to the server's audit log. The record is discarded
+// if auditing is disabled.
+func Audit(ctx types.Context, rec *types.AuditRecord) {
+	getAuditLog(ctx).write(ctx, rec)
+}
+
+// CloseAudit closes the server's audit log.
+func CloseAudit(ctx types.Context) error {
+	return getAuditLog(ctx).close()
+}
+
+// AuditRecords returns the server's most recent audit records that match the
+// options, oldest first.
+func AuditRecords(
+	ctx types.Context, opts *types.AuditOpts) []*types.AuditRecord {
+	return getAuditLog(ctx).query(opts)
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	log "github.com/Sirupsen/logrus"
+	gofig "github.com/akutz/gofig/types"
+	"github.com/akutz/goof"
+
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+func init() {
+	auditSinks["file"] = newFileAuditSink
+}
+
+// fileAuditSink appends audit records to a file. When the file reaches its
+// maximum size it is rotated: the file is renamed with the suffix ".1", an
+// existing ".1" file is renamed ".2", and so on, up to the maximum number of
+// backups.
+type fileAuditSink struct {
+	sync.Mutex
+	path       string
+	maxSize    int64
+	maxBackups int
+	size       int64
+	f          *os.File
+}
+
+func newFileAuditSink(
+	ctx types.Context, config gofig.Config) (auditSink, error) {
+
+	s := &fileAuditSink{
+		path:       config.GetString(types.ConfigServerAuditFilePath),
+		maxBackups: config.GetInt(types.ConfigServerAuditFileMaxBackups),
+	}
+	if s.path == "" {
+		s.path = types.Log.Join("audit.log")
+	}
+	s.maxSize = int64(config.GetInt(types.ConfigServerAuditFileMaxSize))
+	s.maxSize = s.maxSize * 1024 * 1024
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return nil, goof.WithFieldE(
+			"path", s.path, "error creating audit log dir", err)
+	}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+
+	ctx.WithFields(log.Fields{
+		"path":       s.path,
+		"maxSize":    s.maxSize,
+		"maxBackups": s.maxBackups,
+	}).Info("opened audit log file")
+
+	return s, nil
+}
+
+func (s *fileAuditSink) open() error {
+	f, err := os.OpenFile(
+		s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return goof.WithFieldE("path", s.path, "error opening audit log", err)
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return goof.WithFieldE("path", s.path, "error opening audit log", err)
+	}
+	s.f = f
+	s.size = fi.Size()
+	return nil
+}
+
+func (s *fileAuditSink) rotate() error {
+	if err := s.f.Close(); err != nil {
+		return err
+	}
+
+	if s.maxBackups <= 0 {
+		if err := os.Remove(s.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return s.open()
+	}
+
+	for i := s.maxBackups - 1; i > 0; i-- {
+		src := fmt.Sprintf("%s.%d", s.path, i)
+		dst := fmt.Sprintf("%s.%d", s.path, i+1)
+		if err := os.Rename(src, dst); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if err := os.Rename(s.path, s.path+".1"); err != nil {
+		return err
+	}
+
+	return s.open()
+}
+
+func (s *fileAuditSink) Write(buf []byte) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.f == nil {
+		return goof.WithField("path", s.path, "audit log closed")
+	}
+
+	buf = append(buf, '\n')
+	if s.maxSize > 0 && s.size > 0 && s.size+int64(len(buf)) > s.maxSize {
+		if err := s.rotate(); err != nil {
+			return goof.WithFieldE(
+				"path", s.path, "error rotating audit log", err)
+		}
+	}
+
+	n, err := s.f.Write(buf)
+	s.size += int64(n)
+	return err
+}
+
+func (s *fileAuditSink) Close() error {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.f == nil {
+		return nil
+	}
+	err := s.f.Close()
+	s.f = nil
+	return err
+}
@@ -0,0 +1,50 @@
+// +build !windows
+
+package services
+
+import (
+	"log/syslog"
+
+	gofig "github.com/akutz/gofig/types"
+	"github.com/akutz/goof"
+
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+func init() {
+	auditSinks["syslog"] = newSyslogAuditSink
+}
+
+// syslogAuditSink writes audit records to the local or a remote syslog
+// daemon.
+type syslogAuditSink struct {
+	w *syslog.Writer
+}
+
+func newSyslogAuditSink(
+	ctx types.Context, config gofig.Config) (auditSink, error) {
+
+	network := config.GetString(types.ConfigServerAuditSyslogNetwork)
+	address := config.GetString(types.ConfigServerAuditSyslogAddress)
+	tag := config.GetString(types.ConfigServerAuditSyslogTag)
+
+	w, err := syslog.Dial(
+		network, address, syslog.LOG_INFO|syslog.LOG_AUTHPRIV, tag)
+	if err != nil {
+		return nil, goof.WithFieldsE(goof.Fields{
+			"network": network,
+			"address": address,
+		}, "error connecting to syslog", err)
+	}
+
+	ctx.WithField("address", address).Info("connected to syslog")
+	return &syslogAuditSink{w: w}, nil
+}
+
+func (s *syslogAuditSink) Write(buf []byte) error {
+	return s.w.Info(string(buf))
+}
+
+func (s *syslogAuditSink) Close() error {
+	return s.w.Close()
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+func TestAuditLogQuery(t *testing.T) {
+	ctx := context.Background()
+	l := &auditLog{records: make([]*types.AuditRecord, 3)}
+
+	for i := 1; i <= 5; i++ {
+		l.write(ctx, &types.AuditRecord{
+			Time:    int64(i),
+			Service: fmt.Sprintf("svc-%d", i%2),
+		})
+	}
+
+	records := l.query(nil)
+	if assert.Len(t, records, 3) {
+		assert.EqualValues(t, 3, records[0].Time)
+		assert.EqualValues(t, 5, records[2].Time)
+	}
+
+	records = l.query(&types.AuditOpts{Service: "svc-1"})
+	if assert.Len(t, records, 2) {
+		assert.EqualValues(t, 3, records[0].Time)
+		assert.EqualValues(t, 5, records[1].Time)
+	}
+
+	records = l.query(&types.AuditOpts{Since: 4, Limit: 1})
+	if assert.Len(t, records, 1) {
+		assert.EqualValues(t, 5, records[0].Time)
+	}
+
+	var nilLog *auditLog
+	nilLog.write(ctx, &types.AuditRecord{})
+	assert.Empty(t, nilLog.query(nil))
+}
+
+func TestFileAuditSinkRotate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s := &fileAuditSink{
+		path:       filepath.Join(dir, "audit.log"),
+		maxSize:    10,
+		maxBackups: 2,
+	}
+	if err := s.open(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	for i := 0; i < 4; i++ {
+		assert.NoError(t, s.Write([]byte(strings.Repeat(fmt.Sprint(i), 8))))
+	}
+
+	read := func(path string) string {
+		buf, err := ioutil.ReadFile(path)
+		assert.NoError(t, err)
+		return string(buf)
+	}
+
+	assert.Equal(t, "33333333\n", read(s.path))
+	assert.Equal(t, "22222222\n", read(s.path+".1"))
+	assert.Equal(t, "11111111\n", read(s.path+".2"))
+	_, err = os.Stat(s.path + ".3")
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+// eventBufferSize is the number of events buffered for each subscriber. Events
+// published to a subscriber whose buffer is full are dropped so that a slow
+// consumer can never block a task or a storage operation.
+const eventBufferSize = 64
+
+type eventBus struct {
+	sync.RWMutex
+	nextID      int
+	subscribers map[int]chan *types.Event
+}
+
+func newEventBus() *eventBus {
+	return &eventBus{subscribers: map[int]chan *types.Event{}}
+}
+
+func (b *eventBus) publish(ctx types.Context, ev *types.Event) {
+	if b == nil {
+		return
+	}
+	if ev.Time == 0 {
+		ev.Time = time.Now().Unix()
+	}
+
+	b.RLock()
+	defer b.RUnlock()
+
+	for id, c := range b.subscribers {
+		select {
+		case c <- ev:
+		default:
+			ctx.WithField("subscriber", id).Warn(
+				"dropped event; subscriber buffer full")
+		}
+	}
+}
+
+func (b *eventBus) subscribe() (<-chan *types.Event, func()) {
+	b.Lock()
+	defer b.Unlock()
+
+	id := b.nextID
+	b.nextID++
+
+	c := make(chan *types.Event, eventBufferSize)
+	b.subscribers[id] = c
+
+	var once sync.Once
+	return c, func() {
+		once.Do(func() {
+			b.Lock()
+			defer b.Unlock()
+			delete(b.subscribers, id)
+			close(c)
+		})
+	}
+}
+
+func getEventBus(ctx types.Context) *eventBus {
+	serverName, ok := context.Server(ctx)
+	if !ok {
+		panic("ctx is missing ServerName")
+	}
+
+	servicesByServerRWL.RLock()
+	defer servicesByServerRWL.RUnlock()
+	return servicesByServer[serverName].events
+}
+
+// PublishEvent publishes an event to all of the server's event subscribers.
+func PublishEvent(ctx types.Context, ev *types.Event) {
+	getEventBus(ctx).publish(ctx, ev)
+}
+
+// SubscribeEvents returns a channel on which all of the events published by
+// the server are received. The returned function must be invoked to
+// unsubscribe, after which the channel is closed.
+func SubscribeEvents(ctx types.Context) (<-chan *types.Event, func()) {
+	return getEventBus(ctx).subscribe()
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"reflect"
+	"strings"
+
+	log "github.com/Sirupsen/logrus"
+	gofig "github.com/akutz/gofig/types"
+	"github.com/akutz/goof"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+// Reload loads the server's configuration again and updates the server's
+// storage services to match it. Services that are new are created, services
+// whose settings changed are replaced, and services that were removed are
+// drained. Services whose settings did not change are kept and continue to
+// serve requests throughout the reload.
+//
+// If any of the new or changed services cannot be initialized then an error
+// is returned and the server's services are left as they were.
+func Reload(ctx types.Context) error {
+	sc := getServiceContainer(ctx)
+
+	sc.reloadLock.Lock()
+	defer sc.reloadLock.Unlock()
+
+	if sc.loadConfig == nil {
+		return goof.New("services reload unsupported")
+	}
+
+	config, err := sc.loadConfig()
+	if err != nil {
+		return goof.WithError("error loading config", err)
+	}
+
+	return sc.reload(ctx, config)
+}
+
+func (sc *serviceContainer) reload(
+	ctx types.Context, config gofig.Config) error {
+
+	ctx.Info("reloading server services")
+
+	cfgSvcsMap, err := getServiceConfigs(config)
+	if err != nil {
+		return err
+	}
+
+	servicesByServerRWL.RLock()
+	oldSvcs := sc.storageServices
+	servicesByServerRWL.RUnlock()
+
+	var (
+		newSvcs = map[string]types.StorageService{}
+		added   []string
+		updated []string
+		removed []string
+	)
+
+	for serviceName, svcSettings := range cfgSvcsMap {
+		serviceName = strings.ToLower(serviceName)
+		settings := getServiceSettings(config, serviceName, svcSettings)
+
+		oldSvc, exists := oldSvcs[serviceName].(*storageService)
+		if exists && reflect.DeepEqual(oldSvc.settings, settings) {
+			newSvcs[serviceName] = oldSvc
+			continue
+		}
+
+		storSvc, err := newStorageService(
+			ctx, config, serviceName, settings)
+		if err != nil {
+			return goof.WithFieldE(
+				"service", serviceName, "error reloading service", err)
+		}
+		newSvcs[serviceName] = storSvc
+
+		if exists {
+			updated = append(updated, serviceName)
+		} else {
+			added = append(added, serviceName)
+		}
+	}
+
+	var drained []*storageService
+	for serviceName, svc := range oldSvcs {
+		if newSvcs[serviceName] == svc {
+			continue
+		}
+		if _, ok := newSvcs[serviceName]; !ok {
+			removed = append(removed, serviceName)
+		}
+		if storSvc, ok := svc.(*storageService); ok {
+			drained = append(drained, storSvc)
+		}
+	}
+
+	servicesByServerRWL.Lock()
+	sc.config = config
+	sc.storageServices = newSvcs
+	servicesByServerRWL.Unlock()
+
+	for _, svc := range drained {
+		go svc.drain(ctx.WithValue(context.StorageServiceKey, svc))
+	}
+
+	ctx.WithFields(log.Fields{
+		"added":   added,
+		"updated": updated,
+		"removed": removed,
+	}).Info("reloaded server services")
+
+	return nil
+}
+
+func getServiceContainer(ctx types.Context) *serviceContainer {
+	serverName, ok := context.Server(ctx)
+	if !ok {
+		panic("ctx is missing ServerName")
+	}
+
+	servicesByServerRWL.RLock()
+	defer servicesByServerRWL.RUnlock()
+	return servicesByServer[serverName]
+}
+
+// getServiceSettings returns the settings on which a service depends: the
+// service's own settings, the top-level settings of the service's driver,
+// and the libStorage settings other than the server's. A change to any other
+// setting, such as the server's auth or audit settings or the settings of
+// another driver, does not change the service.
+func getServiceSettings(
+	config gofig.Config,
+	serviceName string,
+	svcSettings interface{}) []interface{} {
+
+	var (
+		settings   = config.AllSettings()
+		driverName = strings.ToLower(
+			getDriverName(config.Scope(getServiceScope(serviceName))))
+		lsSettings = map[string]interface{}{}
+	)
+
+	if ls, ok := settings["libstorage"].(map[string]interface{}); ok {
+		for k, v := range ls {
+			if k != "server" {
+				lsSettings[k] = v
+			}
+		}
+	}
+
+	return []interface{}{svcSettings, settings[driverName], lsSettings}
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	gofigCore "github.com/akutz/gofig"
+	gofig "github.com/akutz/gofig/types"
+	"github.com/stretchr/testify/assert"
+)
+
+var reloadConfigYAML = `
+libstorage:
+  server:
+    services:
+      vfs-00:
+        driver: vfs
+      ebs-00:
+        driver: ebs
+`
+
+func newReloadConfig(t *testing.T, yaml string) gofig.Config {
+	config := gofigCore.New()
+	for _, y := range []string{reloadConfigYAML, yaml} {
+		if err := config.ReadConfig(bytes.NewReader([]byte(y))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return config
+}
+
+func TestGetServiceSettings(t *testing.T) {
+	settingsChanged := func(yaml1, yaml2, serviceName string) bool {
+		c1 := newReloadConfig(t, yaml1)
+		c2 := newReloadConfig(t, yaml2)
+		svcs1, err := getServiceConfigs(c1)
+		assert.NoError(t, err)
+		svcs2, err := getServiceConfigs(c2)
+		assert.NoError(t, err)
+		return !reflect.DeepEqual(
+			getServiceSettings(c1, serviceName, svcs1[serviceName]),
+			getServiceSettings(c2, serviceName, svcs2[serviceName]))
+	}
+
+	// the server's settings do not change any service
+	assert.False(t, settingsChanged(
+		"libstorage:\n  server:\n    audit:\n      enabled: false\n",
+		"libstorage:\n  server:\n    audit:\n      enabled: true\n",
+		"vfs-00"))
+
+	// the settings of a driver only change the services that use it
+	assert.False(t, settingsChanged(
+		"ebs:\n  region: us-east-1\n",
+		"ebs:\n  region: us-west-1\n",
+		"vfs-00"))
+	assert.True(t, settingsChanged(
+		"ebs:\n  region: us-east-1\n",
+		"ebs:\n  region: us-west-1\n",
+		"ebs-00"))
+
+	// the libStorage settings other than the server's change every service
+	assert.True(t, settingsChanged(
+		"libstorage:\n  integration:\n    volume:\n      operations:\n"+
+			"        mount:\n          preempt: false\n",
+		"libstorage:\n  integration:\n    volume:\n      operations:\n"+
+			"        mount:\n          preempt: true\n",
+		"vfs-00"))
+}
@@ -1,6 +1,8 @@
 package services
 
 import (
+	"sync"
+
 	gofig "github.com/akutz/gofig/types"
 	"github.com/akutz/goof"
 
@@ -10,10 +12,21 @@ import (
 )
 
 type storageService struct {
+	sync.Mutex
 	name          string
 	driver        types.StorageDriver
 	config        gofig.Config
 	taskExecQueue chan *task
+
+	// settings are the settings from which the service was created. they
+	// are compared to the reloaded settings to determine whether or not the
+	// service has changed.
+	settings []interface{}
+
+	// pending is the number of tasks that have been submitted to the service
+	// but have not been executed.
+	pending  sync.WaitGroup
+	draining bool
 }
 
 func (s *storageService) Init(ctx types.Context, config gofig.Config) error {
@@ -27,22 +40,17 @@ func (s *storageService) Init(ctx types.Context, config gofig.Config) error {
 	go func() {
 		for t := range s.taskExecQueue {
 			execTask(t)
+			s.pending.Done()
 		}
 	}()
 	return nil
 }
 
 func (s *storageService) initStorageDriver(ctx types.Context) error {
-	driverName := s.config.GetString("driver")
+	driverName := getDriverName(s.config)
 	if driverName == "" {
-		driverName = s.config.GetString("libstorage.driver")
-		if driverName == "" {
-			driverName = s.config.GetString("libstorage.storage.driver")
-			if driverName == "" {
-				return goof.WithField(
-					"service", s.name, "error getting driver name")
-			}
-		}
+		return goof.WithField(
+			"service", s.name, "error getting driver name")
 	}
 
 	ctx.WithField("driverName", driverName).Debug("got driver name")
@@ -75,10 +83,47 @@ func (s *storageService) TaskExecute(
 	schema []byte) *types.Task {
 
 	t := newStorageServiceTask(ctx, run, s, schema)
+
+	s.Lock()
+	defer s.Unlock()
+
+	if s.draining {
+		t.complete(nil, goof.WithField(
+			"service", s.name, "service removed by reload"))
+		return &t.Task
+	}
+
+	s.pending.Add(1)
 	go func() { s.taskExecQueue <- t }()
 	return &t.Task
 }
 
+// drain stops the service from accepting new tasks, waits for the tasks that
+// were already submitted to the service to complete, and then stops the
+// service's task queue.
+func (s *storageService) drain(ctx types.Context) {
+	s.Lock()
+	s.draining = true
+	s.Unlock()
+
+	ctx.Info("draining service")
+	s.pending.Wait()
+	close(s.taskExecQueue)
+	ctx.Info("drained service")
+}
+
+// getDriverName returns the name of the driver configured for a service from
+// the service's scoped configuration.
+func getDriverName(config gofig.Config) string {
+	for _, k := range []string{
+		"driver", "libstorage.driver", "libstorage.storage.driver"} {
+		if driverName := config.GetString(k); driverName != "" {
+			return driverName
+		}
+	}
+	return ""
+}
+
 func (s *storageService) Name() string {
 	return s.name
 }
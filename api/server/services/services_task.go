@@ -12,13 +12,18 @@ import (
 	"github.com/akutz/goof"
 
 	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/registry"
 	"github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/api/utils"
 	"github.com/codedellemc/libstorage/api/utils/schema"
 )
 
 type task struct {
 	types.Task
+	sync.Mutex
 	ctx                           types.Context
+	cancel                        func()
+	svc                           *globalTaskService
 	runFunc                       types.TaskRunFunc
 	storRunFunc                   types.StorageTaskRunFunc
 	storService                   types.StorageService
@@ -53,40 +58,143 @@ func newStorageServiceTask(
 	t := newTask(ctx, schema)
 	t.storRunFunc = run
 	t.storService = svc
+	t.Service = svc.Name()
 	return t
 }
 
-func execTask(t *task) {
-	defer func() {
-		t.CompleteTime = time.Now().Unix()
-		if t.Error != nil {
-			t.ctx.Error(t.Error)
-			t.State = types.TaskStateError
-		} else {
-			t.State = types.TaskStateSuccess
-		}
-		close(t.done)
-		t.ctx.Debug("task completed")
-	}()
+// save persists the task's current state to the task service's store and
+// publishes the task's state to the task service's event subscribers.
+func (t *task) save() {
+	if err := t.svc.store.Save(t.ctx, &t.Task); err != nil {
+		t.ctx.WithError(err).Error("error persisting task")
+	}
+	tc := t.Task
+	t.svc.events.publish(t.ctx, &types.Event{
+		Type:    types.EventTypeTask,
+		Service: tc.Service,
+		Task:    &tc,
+	})
+}
 
+// isComplete returns a flag indicating whether or not the task is in a
+// terminal state.
+func (t *task) isComplete() bool {
+	switch t.State {
+	case types.TaskStateSuccess,
+		types.TaskStateError,
+		types.TaskStateCancelled,
+		types.TaskStateInterrupted:
+		return true
+	}
+	return false
+}
+
+// start marks the task as running. A false value is returned if the task was
+// cancelled before it could start.
+func (t *task) start() bool {
+	t.Lock()
+	defer t.Unlock()
+	if t.State == types.TaskStateCancelled {
+		return false
+	}
 	t.State = types.TaskStateRunning
 	t.StartTime = time.Now().Unix()
+	t.save()
+	return true
+}
+
+// complete records the task's result. The result of a task that was
+// cancelled is discarded. A paged result is recorded as the page's result,
+// the token of the next page, and the errors of the services that failed.
+func (t *task) complete(result interface{}, err error) {
+	t.Lock()
+	defer t.Unlock()
+
+	if t.State == types.TaskStateCancelled {
+		t.ctx.Debug("discarding result of cancelled task")
+		return
+	}
+
+	t.CompleteTime = time.Now().Unix()
+	if pr, ok := result.(*types.PagedResult); ok {
+		result = pr.Result
+		t.Next = pr.Next
+		if len(pr.ServiceErrors) > 0 {
+			t.ServiceErrors = pr.ServiceErrors
+		}
+	}
+	t.Result = result
+	t.Error = err
+	if t.Error != nil {
+		t.ctx.Error(t.Error)
+		t.State = types.TaskStateError
+	} else {
+		t.State = types.TaskStateSuccess
+	}
+	t.save()
+	close(t.done)
+	if t.cancel != nil {
+		t.cancel()
+	}
+	t.ctx.Debug("task completed")
+}
+
+// abort cancels the task's context and marks the task as cancelled. An
+// error is returned if the task has already completed.
+func (t *task) abort() error {
+	t.Lock()
+	defer t.Unlock()
+
+	if t.isComplete() {
+		return utils.NewTaskCompletedError(t.ID, t.State)
+	}
+
+	if t.cancel != nil {
+		t.cancel()
+	}
+
+	t.CompleteTime = time.Now().Unix()
+	t.State = types.TaskStateCancelled
+	t.Error = goof.WithField("taskID", t.ID, "task cancelled")
+	t.save()
+	close(t.done)
+	t.ctx.Info("task cancelled")
+	return nil
+}
+
+func execTask(t *task) {
+	if !t.start() {
+		t.ctx.Debug("skipping execution of cancelled task")
+		return
+	}
+
+	var (
+		result interface{}
+		err    error
+	)
+
+	defer func() { t.complete(result, err) }()
 
 	t.ctx.Info("executing task")
 
 	if t.storRunFunc != nil && t.storService != nil {
-		t.Result, t.Error = t.storRunFunc(t.ctx, t.storService)
+		result, err = t.storRunFunc(t.ctx, t.storService)
 	} else if t.runFunc != nil {
-		t.Result, t.Error = t.runFunc(t.ctx)
+		result, err = t.runFunc(t.ctx)
 	} else {
-		t.Error = goof.New("invalid task")
+		err = goof.New("invalid task")
 	}
 
-	if t.Error != nil {
+	if err != nil {
 		return
 	}
 
-	if t.Result == nil {
+	resultToValidate := result
+	if pr, ok := result.(*types.PagedResult); ok {
+		resultToValidate = pr.Result
+	}
+
+	if resultToValidate == nil {
 		t.ctx.Debug("skipping response schema validation; result == nil")
 		return
 	}
@@ -102,14 +210,11 @@ func execTask(t *task) {
 	}
 
 	var buf []byte
-	if buf, t.Error = json.Marshal(t.Result); t.Error != nil {
+	if buf, err = json.Marshal(resultToValidate); err != nil {
 		return
 	}
 
-	t.Error = schema.Validate(t.ctx, t.resultSchema, buf)
-	if t.Error != nil {
-		return
-	}
+	err = schema.Validate(t.ctx, t.resultSchema, buf)
 }
 
 type globalTaskService struct {
@@ -117,6 +222,8 @@ type globalTaskService struct {
 	name                          string
 	config                        gofig.Config
 	tasks                         map[int]*task
+	store                         types.TaskStore
+	events                        *eventBus
 	resultSchemaValidationEnabled bool
 }
 
@@ -130,6 +237,66 @@ func (s *globalTaskService) Init(ctx types.Context, config gofig.Config) error {
 	ctx.WithField("enabled", s.resultSchemaValidationEnabled).Debug(
 		"configured result schema validation")
 
+	storeType := config.GetString(types.ConfigServerTasksStoreType)
+	if storeType == "" {
+		storeType = memTaskStoreName
+	}
+	store, err := registry.NewTaskStore(storeType)
+	if err != nil {
+		return err
+	}
+	if err := store.Init(ctx, config); err != nil {
+		return err
+	}
+	s.store = store
+	ctx.WithField("store", store.Name()).Debug("configured task store")
+
+	if store.Name() != memTaskStoreName && s.logTimeout() <= 0 {
+		ctx.WithFields(log.Fields{
+			"store":      store.Name(),
+			"logTimeout": s.logTimeout(),
+		}).Warn("task log timeout is zero, persisted tasks are not kept")
+	}
+
+	return s.loadTasks(ctx)
+}
+
+// loadTasks loads the tasks persisted by a previous instance of the task
+// service. Tasks that had not completed when the previous instance stopped
+// are marked as interrupted since they can no longer complete. The loaded
+// tasks are removed once the log timeout has elapsed since they completed.
+func (s *globalTaskService) loadTasks(ctx types.Context) error {
+	tasks, err := s.store.Tasks(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+
+	for _, tt := range tasks {
+		t := &task{
+			Task: *tt,
+			svc:  s,
+			ctx:  ctx.WithValue(context.TaskKey, fmt.Sprintf("%d", tt.ID)),
+			done: make(chan int),
+		}
+		close(t.done)
+
+		if !t.isComplete() {
+			t.ctx.WithField("state", t.State).Warn("task interrupted")
+			t.State = types.TaskStateInterrupted
+			t.CompleteTime = now
+			if t.Error == nil {
+				t.Error = goof.New("task interrupted")
+			}
+			t.save()
+		}
+
+		s.tasks[t.ID] = t
+		s.taskRemoveAfter(t)
+	}
+
+	ctx.WithField("tasksLen", len(s.tasks)).Debug("loaded tasks")
 	return nil
 }
 
@@ -163,23 +330,35 @@ func (s *globalTaskService) TaskTrack(ctx types.Context) *types.Task {
 func (s *globalTaskService) taskTrack(ctx types.Context) *task {
 
 	now := time.Now().Unix()
-	s.RLock()
-	taskID := len(s.tasks)
-	s.RUnlock()
+	taskID, err := s.store.NextID(ctx)
+	if err != nil {
+		ctx.WithError(err).Error("error persisting task ID")
+	}
 
 	t := &task{
 		Task: types.Task{
 			ID:        taskID,
 			QueueTime: now,
+			State:     types.TaskStateQueued,
 		},
 		resultSchemaValidationEnabled: s.resultSchemaValidationEnabled,
-		ctx: ctx.WithValue(context.TaskKey, fmt.Sprintf("%d", taskID)),
 	}
+	if serviceName, ok := context.ServiceName(ctx); ok {
+		t.Service = serviceName
+	}
+	if user, ok := context.User(ctx); ok {
+		t.User = user
+	}
+	t.svc = s
+	t.ctx, t.cancel = context.WithCancel(
+		ctx.WithValue(context.TaskKey, fmt.Sprintf("%d", taskID)))
 
 	s.Lock()
 	s.tasks[taskID] = t
 	s.Unlock()
 
+	t.save()
+
 	return t
 }
 
@@ -194,6 +373,20 @@ func (s *globalTaskService) TaskExecute(
 	return &t.Task
 }
 
+// TaskCancel cancels the task with the specified ID.
+func (s *globalTaskService) TaskCancel(taskID int) (*types.Task, error) {
+	s.RLock()
+	t, ok := s.tasks[taskID]
+	s.RUnlock()
+	if !ok {
+		return nil, utils.NewNotFoundError(fmt.Sprintf("%d", taskID))
+	}
+	if err := t.abort(); err != nil {
+		return nil, err
+	}
+	return &t.Task, nil
+}
+
 // TaskInspect returns the task with the specified ID.
 func (s *globalTaskService) TaskInspect(taskID int) *types.Task {
 	s.RLock()
@@ -235,18 +428,33 @@ func (s *globalTaskService) TaskWaitC(taskID int) <-chan int {
 	return c
 }
 
+// logTimeout returns the duration specified by
+// `libstorage.server.tasks.logTimeout`, or one minute if the value is invalid.
+func (s *globalTaskService) logTimeout() time.Duration {
+	logTimeoutDur, err := time.ParseDuration(
+		s.config.GetString(types.ConfigServerTasksLogTimeout))
+	if err != nil {
+		logTimeoutDur = time.Duration(time.Second * 60)
+	}
+	return logTimeoutDur
+}
+
 // taskRemoveAfter tells the task service to remove the task after the duration
-// specified by `libstorage.server.tasks.logTimeout`.
+// specified by `libstorage.server.tasks.logTimeout`. The duration is measured
+// from the time the task completed, so a task reloaded from the store is
+// removed once the duration has elapsed since it completed.
 func (s *globalTaskService) taskRemoveAfter(t *task) {
 	go func() {
-		logTimeoutDur, err := time.ParseDuration(
-			s.config.GetString(types.ConfigServerTasksLogTimeout))
-		if err != nil {
-			logTimeoutDur = time.Duration(time.Second * 60)
-		}
+		logTimeoutDur := s.logTimeout()
 
 		// wait to remove the task
-		time.Sleep(logTimeoutDur)
+		wait := logTimeoutDur
+		if t.CompleteTime > 0 {
+			wait -= time.Since(time.Unix(t.CompleteTime, 0))
+		}
+		if wait > 0 {
+			time.Sleep(wait)
+		}
 
 		// sync access to the task map for querying its size before and after
 		// executing the delete operation on it
@@ -260,6 +468,9 @@ func (s *globalTaskService) taskRemoveAfter(t *task) {
 
 		// delete the task
 		delete(s.tasks, t.ID)
+		if err := s.store.Remove(t.ctx, t.ID); err != nil {
+			t.ctx.WithError(err).Error("error removing persisted task")
+		}
 
 		t.ctx.WithField("tasksLen", len(s.tasks)).Debug("removed task")
 	}()
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/api/utils/metrics"
+)
+
+func init() {
+	metrics.MustRegister(&taskCollector{})
+}
+
+var (
+	taskStatesDesc = prometheus.NewDesc(
+		"libstorage_tasks",
+		"The number of tracked tasks by state.",
+		[]string{"server", "state"}, nil)
+
+	taskQueueDepthDesc = prometheus.NewDesc(
+		"libstorage_task_queue_depth",
+		"The number of tasks that are queued or running.",
+		[]string{"server"}, nil)
+)
+
+// taskStates are the states reported by the task collector. A state is
+// reported even when no task is in it so that its series does not vanish.
+var taskStates = []types.TaskState{
+	types.TaskStateQueued,
+	types.TaskStateRunning,
+	types.TaskStateSuccess,
+	types.TaskStateError,
+	types.TaskStateCancelled,
+	types.TaskStateInterrupted,
+}
+
+// taskCollector collects the state of the task service of each server.
+type taskCollector struct{}
+
+func (c *taskCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- taskStatesDesc
+	ch <- taskQueueDepthDesc
+}
+
+func (c *taskCollector) Collect(ch chan<- prometheus.Metric) {
+	servicesByServerRWL.RLock()
+	defer servicesByServerRWL.RUnlock()
+
+	for serverName, sc := range servicesByServer {
+		counts := sc.taskService.stateCounts()
+		for _, state := range taskStates {
+			ch <- prometheus.MustNewConstMetric(
+				taskStatesDesc,
+				prometheus.GaugeValue,
+				float64(counts[state]),
+				serverName, string(state))
+		}
+		ch <- prometheus.MustNewConstMetric(
+			taskQueueDepthDesc,
+			prometheus.GaugeValue,
+			float64(counts[types.TaskStateQueued]+
+				counts[types.TaskStateRunning]),
+			serverName)
+	}
+}
+
+// stateCounts returns the number of tracked tasks in each state.
+func (s *globalTaskService) stateCounts() map[types.TaskState]int {
+	s.RLock()
+	defer s.RUnlock()
+
+	counts := map[types.TaskState]int{}
+	for _, t := range s.tasks {
+		t.Lock()
+		counts[t.State]++
+		t.Unlock()
+	}
+	return counts
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"sync"
+
+	gofig "github.com/akutz/gofig/types"
+
+	"github.com/codedellemc/libstorage/api/registry"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+const (
+	memTaskStoreName  = "memory"
+	fileTaskStoreName = "file"
+)
+
+func init() {
+	registry.RegisterTaskStore(memTaskStoreName, newMemTaskStore)
+	registry.RegisterTaskStore(fileTaskStoreName, newFileTaskStore)
+}
+
+// memTaskStore is the default task store. It does not persist tasks beyond
+// the lifetime of the process, so the task service's own map is the only
+// record of a task; the store is only responsible for ensuring task IDs are
+// never reused.
+type memTaskStore struct {
+	sync.Mutex
+	nextID int
+}
+
+func newMemTaskStore() types.TaskStore {
+	return &memTaskStore{}
+}
+
+func (s *memTaskStore) Name() string {
+	return memTaskStoreName
+}
+
+func (s *memTaskStore) Init(ctx types.Context, config gofig.Config) error {
+	return nil
+}
+
+func (s *memTaskStore) NextID(ctx types.Context) (int, error) {
+	s.Lock()
+	defer s.Unlock()
+	id := s.nextID
+	s.nextID++
+	return id, nil
+}
+
+func (s *memTaskStore) Save(ctx types.Context, task *types.Task) error {
+	return nil
+}
+
+func (s *memTaskStore) Remove(ctx types.Context, taskID int) error {
+	return nil
+}
+
+func (s *memTaskStore) Tasks(ctx types.Context) ([]*types.Task, error) {
+	return nil, nil
+}
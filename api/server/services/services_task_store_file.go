@@ -0,0 +1,213 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	log "github.com/Sirupsen/logrus"
+	gofig "github.com/akutz/gofig/types"
+	"github.com/akutz/goof"
+	"github.com/akutz/gotil"
+
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+// fileTaskStore persists each task as a JSON file in a directory. The last
+// issued task ID is stored alongside the tasks so IDs are not reused after
+// the server restarts.
+type fileTaskStore struct {
+	sync.Mutex
+	dir    string
+	nextID int
+}
+
+// fileTask is the persisted form of a task. A task's error is stored as its
+// message since an error's concrete type cannot be restored. A task is read
+// back with types.Task's UnmarshalJSON function.
+type fileTask struct {
+	*types.Task
+	Error string `json:"error,omitempty"`
+}
+
+func newFileTaskStore() types.TaskStore {
+	return &fileTaskStore{}
+}
+
+func (s *fileTaskStore) Name() string {
+	return fileTaskStoreName
+}
+
+func (s *fileTaskStore) Init(ctx types.Context, config gofig.Config) error {
+	s.dir = config.GetString(types.ConfigServerTasksStorePath)
+	if s.dir == "" {
+		s.dir = types.Lib.Join("tasks")
+	}
+
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return goof.WithFieldE("dir", s.dir, "error creating task store", err)
+	}
+
+	if gotil.FileExists(s.seqPath()) {
+		buf, err := ioutil.ReadFile(s.seqPath())
+		if err != nil {
+			return goof.WithFieldE(
+				"path", s.seqPath(), "error reading task seq", err)
+		}
+		seq := strings.TrimSpace(string(buf))
+		if s.nextID, err = strconv.Atoi(seq); err != nil {
+			return goof.WithFieldE(
+				"path", s.seqPath(), "invalid task seq", err)
+		}
+	}
+
+	// guard against a seq file that is older than the tasks themselves
+	paths, err := s.getTaskJSONs()
+	if err != nil {
+		return err
+	}
+	for _, p := range paths {
+		id, err := strconv.Atoi(strings.TrimSuffix(filepath.Base(p), ".json"))
+		if err != nil {
+			continue
+		}
+		if id >= s.nextID {
+			s.nextID = id + 1
+		}
+	}
+
+	ctx.WithFields(log.Fields{
+		"dir":    s.dir,
+		"nextID": s.nextID,
+	}).Info("initialized file task store")
+
+	return nil
+}
+
+func (s *fileTaskStore) NextID(ctx types.Context) (int, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	id := s.nextID
+	s.nextID++
+
+	if err := writeFileAtomic(
+		s.seqPath(), []byte(strconv.Itoa(s.nextID))); err != nil {
+		return id, goof.WithFieldE(
+			"path", s.seqPath(), "error writing task seq", err)
+	}
+
+	return id, nil
+}
+
+func (s *fileTaskStore) Save(ctx types.Context, task *types.Task) error {
+	ft := &fileTask{Task: task}
+	if task.Error != nil {
+		ft.Error = task.Error.Error()
+	}
+
+	buf, err := json.Marshal(ft)
+	if err != nil {
+		return goof.WithFieldE("taskID", task.ID, "error encoding task", err)
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	if err := writeFileAtomic(s.getTaskPath(task.ID), buf); err != nil {
+		return goof.WithFieldE("taskID", task.ID, "error saving task", err)
+	}
+
+	return nil
+}
+
+func (s *fileTaskStore) Remove(ctx types.Context, taskID int) error {
+	s.Lock()
+	defer s.Unlock()
+
+	err := os.Remove(s.getTaskPath(taskID))
+	if err != nil && !os.IsNotExist(err) {
+		return goof.WithFieldE("taskID", taskID, "error removing task", err)
+	}
+
+	return nil
+}
+
+func (s *fileTaskStore) Tasks(ctx types.Context) ([]*types.Task, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	paths, err := s.getTaskJSONs()
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := []*types.Task{}
+	for _, p := range paths {
+		t, err := readTask(p)
+		if err != nil {
+			ctx.WithField("path", p).WithError(err).Warn(
+				"error reading task")
+			continue
+		}
+		tasks = append(tasks, t)
+	}
+
+	return tasks, nil
+}
+
+func (s *fileTaskStore) seqPath() string {
+	return filepath.Join(s.dir, "seq")
+}
+
+func (s *fileTaskStore) getTaskPath(taskID int) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%d.json", taskID))
+}
+
+func (s *fileTaskStore) getTaskJSONs() ([]string, error) {
+	return filepath.Glob(filepath.Join(s.dir, "*.json"))
+}
+
+func readTask(path string) (*types.Task, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	t := &types.Task{}
+	if err := json.NewDecoder(f).Decode(t); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// writeFileAtomic writes the data to a temporary file and then renames the
+// temporary file to the specified path so that a reader never observes a
+// partially written file.
+func writeFileAtomic(path string, data []byte) error {
+	f, err := ioutil.TempFile(filepath.Dir(path), ".tmp-")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return nil
+}
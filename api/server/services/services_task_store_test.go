@@ -0,0 +1,166 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	gofigCore "github.com/akutz/gofig"
+	gofig "github.com/akutz/gofig/types"
+	"github.com/akutz/goof"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+var fileTaskStoreConfigFormat = `
+libstorage:
+  server:
+    tasks:
+      store:
+        type: file
+        path: %s
+`
+
+func newFileTaskStoreConfig(t *testing.T, dir string) gofig.Config {
+	config := gofigCore.New()
+	buf := []byte(fmt.Sprintf(fileTaskStoreConfigFormat, dir))
+	if err := config.ReadConfig(bytes.NewReader(buf)); err != nil {
+		t.Fatal(err)
+	}
+	return config
+}
+
+func newTestTaskService(
+	t *testing.T, config gofig.Config) *globalTaskService {
+
+	s := &globalTaskService{name: "test-task-service"}
+	if err := s.Init(context.Background(), config); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestMemTaskStoreUniqueIDs(t *testing.T) {
+	ctx := context.Background()
+	s := newMemTaskStore()
+	assert.NoError(t, s.Init(ctx, gofigCore.New()))
+
+	id1, err := s.NextID(ctx)
+	assert.NoError(t, err)
+	id2, err := s.NextID(ctx)
+	assert.NoError(t, err)
+	assert.NotEqual(t, id1, id2)
+}
+
+func TestFileTaskStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	config := newFileTaskStoreConfig(t, dir)
+	ctx := context.Background()
+
+	s1 := newTestTaskService(t, config)
+	assert.Equal(t, fileTaskStoreName, s1.store.Name())
+
+	t1 := s1.taskTrack(ctx)
+	t1.done = make(chan int)
+	t1.runFunc = func(ctx types.Context) (interface{}, error) {
+		return "hello", nil
+	}
+	execTask(t1)
+
+	t2 := s1.taskTrack(ctx)
+	t2.done = make(chan int)
+	t2.runFunc = func(ctx types.Context) (interface{}, error) {
+		return nil, goof.New("failed")
+	}
+	execTask(t2)
+
+	// simulate a task that was running when the server stopped
+	t3 := s1.taskTrack(ctx)
+	t3.State = types.TaskStateRunning
+	t3.save()
+
+	// remove a task so its ID is no longer present in the store
+	t4 := s1.taskTrack(ctx)
+	assert.NoError(t, s1.store.Remove(ctx, t4.ID))
+
+	s2 := newTestTaskService(t, config)
+	assert.Len(t, s2.tasks, 3)
+
+	rt1 := s2.TaskInspect(t1.ID)
+	if assert.NotNil(t, rt1) {
+		assert.EqualValues(t, types.TaskStateSuccess, rt1.State)
+		assert.Equal(t, "hello", rt1.Result)
+		assert.NoError(t, rt1.Error)
+	}
+
+	rt2 := s2.TaskInspect(t2.ID)
+	if assert.NotNil(t, rt2) {
+		assert.EqualValues(t, types.TaskStateError, rt2.State)
+		assert.EqualError(t, rt2.Error, "failed")
+	}
+
+	rt3 := s2.TaskInspect(t3.ID)
+	if assert.NotNil(t, rt3) {
+		assert.EqualValues(t, types.TaskStateInterrupted, rt3.State)
+		assert.Error(t, rt3.Error)
+		assert.NotZero(t, rt3.CompleteTime)
+	}
+
+	// waiting on a reloaded task must not block
+	s2.TaskWait(t3.ID)
+
+	t5 := s2.taskTrack(ctx)
+	assert.True(t, t5.ID > t4.ID)
+}
+
+func TestFileTaskStoreRemovesLoadedTasks(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	config := newFileTaskStoreConfig(t, dir)
+	config.Set(types.ConfigServerTasksLogTimeout, "2s")
+	ctx := context.Background()
+
+	s1 := newTestTaskService(t, config)
+
+	// a task that completed longer ago than the log timeout
+	t1 := s1.taskTrack(ctx)
+	t1.State = types.TaskStateSuccess
+	t1.CompleteTime = time.Now().Add(-time.Hour).Unix()
+	t1.save()
+
+	// a task that has only just completed
+	t2 := s1.taskTrack(ctx)
+	t2.State = types.TaskStateSuccess
+	t2.CompleteTime = time.Now().Unix()
+	t2.save()
+
+	s2 := newTestTaskService(t, config)
+	assert.NotNil(t, s2.TaskInspect(t2.ID))
+
+	for i := 0; i < 50; i++ {
+		if s2.TaskInspect(t1.ID) == nil && s2.TaskInspect(t2.ID) == nil {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	assert.Nil(t, s2.TaskInspect(t1.ID))
+	assert.Nil(t, s2.TaskInspect(t2.ID))
+
+	tasks, err := s2.store.Tasks(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 0)
+}
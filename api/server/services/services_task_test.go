@@ -0,0 +1,84 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	gofigCore "github.com/akutz/gofig"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+func TestTaskCancel(t *testing.T) {
+	s := newTestTaskService(t, gofigCore.New())
+	ctx := context.Background()
+
+	started := make(chan int)
+	cancelled := make(chan int)
+
+	tk := s.taskTrack(ctx)
+	tk.done = make(chan int)
+	tk.runFunc = func(ctx types.Context) (interface{}, error) {
+		close(started)
+		<-ctx.Done()
+		close(cancelled)
+		return "too late", nil
+	}
+	go execTask(tk)
+	<-started
+
+	ct, err := s.TaskCancel(tk.ID)
+	assert.NoError(t, err)
+	if assert.NotNil(t, ct) {
+		assert.EqualValues(t, types.TaskStateCancelled, ct.State)
+		assert.Error(t, ct.Error)
+	}
+
+	select {
+	case <-cancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("task context was not cancelled")
+	}
+
+	select {
+	case <-tk.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("cancelled task not done")
+	}
+
+	// the result of the run func must not overwrite the cancellation
+	time.Sleep(100 * time.Millisecond)
+	it := s.TaskInspect(tk.ID)
+	assert.EqualValues(t, types.TaskStateCancelled, it.State)
+	assert.Nil(t, it.Result)
+
+	// a completed task cannot be cancelled
+	_, err = s.TaskCancel(tk.ID)
+	assert.IsType(t, &types.ErrTaskCompleted{}, err)
+
+	// an unknown task cannot be cancelled
+	_, err = s.TaskCancel(-1)
+	assert.IsType(t, &types.ErrNotFound{}, err)
+}
+
+func TestTaskCancelQueued(t *testing.T) {
+	s := newTestTaskService(t, gofigCore.New())
+	ctx := context.Background()
+
+	ran := false
+	tk := s.taskTrack(ctx)
+	tk.done = make(chan int)
+	tk.runFunc = func(ctx types.Context) (interface{}, error) {
+		ran = true
+		return nil, nil
+	}
+
+	_, err := s.TaskCancel(tk.ID)
+	assert.NoError(t, err)
+
+	execTask(tk)
+	assert.False(t, ran)
+	assert.EqualValues(t, types.TaskStateCancelled, tk.State)
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/akutz/goof"
+
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+// TaskWaitAllServices blocks until the tasks of the storage services, keyed
+// by service name, are completed or until the list timeouts of the services
+// expire. The tasks of the services that time out are cancelled.
+//
+// The errors of the services whose tasks failed, were cancelled, or timed out
+// are returned keyed by service name. The tasks of the services that are not
+// in the returned map completed successfully.
+func TaskWaitAllServices(
+	ctx types.Context, tasks map[string]*types.Task) types.ServiceErrorMap {
+
+	var (
+		errs  = types.ServiceErrorMap{}
+		errsL = &sync.Mutex{}
+		wg    = &sync.WaitGroup{}
+	)
+
+	for name, task := range tasks {
+		wg.Add(1)
+		go func(name string, task *types.Task) {
+			defer wg.Done()
+			if err := taskWaitService(ctx, name, task); err != nil {
+				errsL.Lock()
+				errs[name] = err
+				errsL.Unlock()
+			}
+		}(name, task)
+	}
+
+	wg.Wait()
+	return errs
+}
+
+// TaskServicesError returns an error when the tasks of all of the storage
+// services failed, so that a request that no service responded to fails
+// rather than returning an empty result. The error is that of the task of the
+// first service by name.
+func TaskServicesError(
+	tasks map[string]*types.Task, errs types.ServiceErrorMap) error {
+
+	if len(tasks) == 0 || len(errs) < len(tasks) {
+		return nil
+	}
+
+	names := make([]string, 0, len(errs))
+	for k := range errs {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	if err := tasks[names[0]].Error; err != nil {
+		return err
+	}
+	if errs[names[0]].TimedOut {
+		return types.ErrTimedOut
+	}
+	return goof.New(errs[names[0]].Message)
+}
+
+// taskWaitService waits for the task of the named storage service and returns
+// the service's error if the task did not complete successfully.
+func taskWaitService(
+	ctx types.Context, name string, task *types.Task) *types.ServiceError {
+
+	lf := log.Fields{"service": name, "taskID": task.ID}
+
+	var expired <-chan time.Time
+	if timeout := listTimeout(ctx, name); timeout > 0 {
+		lf["timeout"] = timeout
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	select {
+	case <-TaskWaitC(ctx, task.ID):
+	case <-expired:
+		// the task may complete before it is cancelled, in which case its
+		// result is used
+		if _, err := TaskCancel(ctx, task.ID); err == nil {
+			ctx.WithFields(lf).Warn("service list timed out")
+			return &types.ServiceError{
+				Message:  types.ErrTimedOut.Error(),
+				TimedOut: true,
+			}
+		}
+		<-TaskWaitC(ctx, task.ID)
+	case <-ctx.Done():
+		if _, err := TaskCancel(ctx, task.ID); err == nil {
+			ctx.WithFields(lf).Warn("service list cancelled")
+			return &types.ServiceError{Message: ctx.Err().Error()}
+		}
+		<-TaskWaitC(ctx, task.ID)
+	}
+
+	if task.Error != nil {
+		ctx.WithFields(lf).WithError(task.Error).Error("service list failed")
+		return &types.ServiceError{Message: task.Error.Error()}
+	}
+
+	return nil
+}
+
+// listTimeout returns the named storage service's list timeout. A service
+// may override the global timeout with its own. A timeout of zero disables
+// the timeout.
+func listTimeout(ctx types.Context, name string) time.Duration {
+	const defaultTimeout = time.Duration(time.Second * 30)
+
+	svc := GetStorageService(ctx, name)
+	if svc == nil {
+		return defaultTimeout
+	}
+
+	timeoutDur, err := time.ParseDuration(
+		svc.Config().GetString(types.ConfigListTimeout))
+	if err != nil {
+		return defaultTimeout
+	}
+	return timeoutDur
+}
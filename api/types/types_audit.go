@@ -0,0 +1,88 @@
+package types
+
+import "strings"
+
+// AuditRecord is a record of a mutating API operation.
+type AuditRecord struct {
+	// Time is the time stamp when the operation was received.
+	Time int64 `json:"time" yaml:"time"`
+
+	// TransactionID is the ID of the transaction sent with the request.
+	TransactionID string `json:"txID,omitempty" yaml:"txID,omitempty"`
+
+	// InstanceID is the ID of the instance that sent the request.
+	InstanceID string `json:"instanceID,omitempty" yaml:"instanceID,omitempty"`
+
+	// User is the name of the user that sent the request.
+	User string `json:"user,omitempty" yaml:",omitempty"`
+
+	// Service is the name of the service on which the operation was
+	// performed.
+	Service string `json:"service,omitempty" yaml:",omitempty"`
+
+	// Operation is the name of the operation's route.
+	Operation string `json:"operation" yaml:"operation"`
+
+	// Method is the HTTP method of the request.
+	Method string `json:"method" yaml:"method"`
+
+	// Path is the path of the request.
+	Path string `json:"path" yaml:"path"`
+
+	// VolumeID is the ID of the volume on which the operation was performed.
+	VolumeID string `json:"volumeID,omitempty" yaml:"volumeID,omitempty"`
+
+	// SnapshotID is the ID of the snapshot on which the operation was
+	// performed.
+	SnapshotID string `json:"snapshotID,omitempty" yaml:"snapshotID,omitempty"`
+
+	// TaskID is the ID of the task on which the operation was performed.
+	TaskID string `json:"taskID,omitempty" yaml:"taskID,omitempty"`
+
+	// Name is the name of the volume or snapshot created by the operation.
+	Name string `json:"name,omitempty" yaml:",omitempty"`
+
+	// Status is the HTTP status code of the response.
+	Status int `json:"status" yaml:"status"`
+
+	// Error is the error message if the operation failed.
+	Error string `json:"error,omitempty" yaml:",omitempty"`
+
+	// Duration is the amount of time, in milliseconds, it took to process the
+	// request.
+	Duration int64 `json:"duration" yaml:"duration"`
+}
+
+// AuditOpts are options when querying audit records.
+type AuditOpts struct {
+	// Service matches records for the specified service.
+	Service string
+
+	// Since matches records created at or after the specified time stamp.
+	Since int64
+
+	// Until matches records created at or before the specified time stamp.
+	Until int64
+
+	// Limit is the maximum number of records returned. The most recent
+	// records are returned when the limit is exceeded.
+	Limit int
+}
+
+// Match returns a flag indicating whether or not the record matches the
+// options.
+func (o *AuditOpts) Match(r *AuditRecord) bool {
+	if o == nil {
+		return true
+	}
+	if o.Service != "" && !strings.EqualFold(o.Service, r.Service) {
+		return false
+	}
+	if o.Since > 0 && r.Time < o.Since {
+		return false
+	}
+	if o.Until > 0 && r.Time > o.Until {
+		return false
+	}
+	return true
+}
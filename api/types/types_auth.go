@@ -0,0 +1,105 @@
+package types
+
+import "strings"
+
+// AuthRole is a role granted to an authenticated identity for a service.
+// Each role includes the permissions of the roles that precede it.
+type AuthRole int
+
+const (
+	// AuthRoleNone indicates no access.
+	AuthRoleNone AuthRole = iota
+
+	// AuthRoleRead grants access to the operations that do not modify
+	// resources.
+	AuthRoleRead
+
+	// AuthRoleAttach grants the read role as well as access to the attach and
+	// detach operations.
+	AuthRoleAttach
+
+	// AuthRoleAdmin grants access to all operations.
+	AuthRoleAdmin
+)
+
+// AuthGrantAllServices is the service name used to grant a role for all
+// services.
+const AuthGrantAllServices = "*"
+
+// ParseAuthRole parses an AuthRole from a string. An invalid or empty role
+// is parsed as AuthRoleNone.
+func ParseAuthRole(s string) AuthRole {
+	switch strings.ToLower(s) {
+	case "read", "readonly", "read-only":
+		return AuthRoleRead
+	case "attach":
+		return AuthRoleAttach
+	case "admin":
+		return AuthRoleAdmin
+	default:
+		return AuthRoleNone
+	}
+}
+
+// String returns the role's string representation.
+func (r AuthRole) String() string {
+	switch r {
+	case AuthRoleRead:
+		return "read"
+	case AuthRoleAttach:
+		return "attach"
+	case AuthRoleAdmin:
+		return "admin"
+	default:
+		return "none"
+	}
+}
+
+// AuthGrants is a map of the roles granted to an identity, keyed by service
+// name. The key AuthGrantAllServices grants a role for all services.
+type AuthGrants map[string]AuthRole
+
+// Role returns the role granted for the specified service. When a role is
+// granted both for the service and for all services, the greater of the two
+// roles is returned. An empty service name returns the role granted for all
+// services.
+func (g AuthGrants) Role(service string) AuthRole {
+	var role AuthRole
+	for k, v := range g {
+		if k == AuthGrantAllServices ||
+			(service != "" && strings.EqualFold(k, service)) {
+			if v > role {
+				role = v
+			}
+		}
+	}
+	return role
+}
+
+// MaxRole returns the greatest role granted for any service.
+func (g AuthGrants) MaxRole() AuthRole {
+	var role AuthRole
+	for _, v := range g {
+		if v > role {
+			role = v
+		}
+	}
+	return role
+}
+
+// AuthIdentity is an authenticated identity.
+type AuthIdentity struct {
+	// User is the name of the authenticated user.
+	User string
+
+	// Grants are the roles granted to the user.
+	Grants AuthGrants
+}
+
+// AdminTokenValidator validates the tokens that grant access to a server's
+// admin routes.
+type AdminTokenValidator interface {
+	// ValidAdminToken returns a flag indicating whether the token grants
+	// access to the server's admin routes.
+	ValidAdminToken(token string) bool
+}
@@ -0,0 +1,33 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAuthRole(t *testing.T) {
+	assert.Equal(t, AuthRoleRead, ParseAuthRole("read"))
+	assert.Equal(t, AuthRoleRead, ParseAuthRole("read-only"))
+	assert.Equal(t, AuthRoleAttach, ParseAuthRole("Attach"))
+	assert.Equal(t, AuthRoleAdmin, ParseAuthRole("admin"))
+	assert.Equal(t, AuthRoleNone, ParseAuthRole("root"))
+	assert.Equal(t, "attach", AuthRoleAttach.String())
+}
+
+func TestAuthGrantsRole(t *testing.T) {
+	g := AuthGrants{
+		AuthGrantAllServices: AuthRoleRead,
+		"ebs-00":             AuthRoleAdmin,
+	}
+	assert.Equal(t, AuthRoleAdmin, g.Role("ebs-00"))
+	assert.Equal(t, AuthRoleAdmin, g.Role("EBS-00"))
+	assert.Equal(t, AuthRoleRead, g.Role("ebs-01"))
+	assert.Equal(t, AuthRoleRead, g.Role(""))
+	assert.Equal(t, AuthRoleAdmin, g.MaxRole())
+
+	g = AuthGrants{"ebs-00": AuthRoleAttach}
+	assert.Equal(t, AuthRoleNone, g.Role("ebs-01"))
+	assert.Equal(t, AuthRoleNone, g.Role(""))
+	assert.Equal(t, AuthRoleAttach, g.MaxRole())
+}
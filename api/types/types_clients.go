@@ -88,6 +88,10 @@ type APIClient interface {
 	// LogResponses enables or disables the logging of client HTTP responses.
 	LogResponses(enabled bool)
 
+	// SetAuthToken sets the bearer token sent with each request. An empty
+	// token disables authentication.
+	SetAuthToken(token string)
+
 	// Root returns a list of root resources.
 	Root(ctx Context) ([]string, error)
 
@@ -103,6 +107,10 @@ type APIClient interface {
 	// ServiceInspect returns information about a service.
 	ServiceInspect(ctx Context, name string) (*ServiceInfo, error)
 
+	// ServicesReload reloads the server's services from its configuration
+	// and returns a map of the reloaded Services.
+	ServicesReload(ctx Context) (map[string]*ServiceInfo, error)
+
 	// Volumes returns a list of all Volumes for all Services.
 	Volumes(
 		ctx Context,
@@ -143,6 +151,18 @@ type APIClient interface {
 		ctx Context,
 		service, volumeID string) error
 
+	// VolumeResize resizes a single volume.
+	VolumeResize(
+		ctx Context,
+		service, volumeID string,
+		request *VolumeResizeRequest) (*Volume, error)
+
+	// VolumeUpdate updates a single volume.
+	VolumeUpdate(
+		ctx Context,
+		service, volumeID string,
+		request *VolumeUpdateRequest) (*Volume, error)
+
 	// VolumeAttach attaches a single volume.
 	VolumeAttach(
 		ctx Context,
@@ -198,6 +218,25 @@ type APIClient interface {
 		service, snapshotID string,
 		request *SnapshotCopyRequest) (*Snapshot, error)
 
+	// Tasks returns a map of the tasks that match the provided options.
+	Tasks(ctx Context, opts *TasksOpts) (map[string]*Task, error)
+
+	// TaskInspect returns the task with the specified ID.
+	TaskInspect(ctx Context, taskID int) (*Task, error)
+
+	// TaskCancel cancels the task with the specified ID.
+	TaskCancel(ctx Context, taskID int) (*Task, error)
+
+	// Events returns a channel on which the server's events are received.
+	// If any services are specified then only the events related to those
+	// services are received. The channel is closed when the context is
+	// cancelled or the connection to the server is lost.
+	Events(ctx Context, services ...string) (<-chan *Event, error)
+
+	// Audit returns the server's most recent audit records that match the
+	// provided options, oldest first.
+	Audit(ctx Context, opts *AuditOpts) ([]*AuditRecord, error)
+
 	// Executors returns information about the executors.
 	Executors(
 		ctx Context) (map[string]*ExecutorInfo, error)
@@ -81,9 +81,15 @@ const (
 	// ConfigExecutorNoDownload is a config key.
 	ConfigExecutorNoDownload = ConfigRoot + ".executor.disableDownload"
 
+	// ConfigExecutorLockTimeout is a config key.
+	ConfigExecutorLockTimeout = ConfigRoot + ".executor.lockTimeout"
+
 	// ConfigClientCacheInstanceID is a config key.
 	ConfigClientCacheInstanceID = ConfigClient + ".cache.instanceID"
 
+	// ConfigClientGrowFS is a config key.
+	ConfigClientGrowFS = ConfigClient + ".growFS"
+
 	// ConfigTLS is a config key.
 	ConfigTLS = ConfigRoot + ".tls"
 
@@ -123,4 +129,109 @@ const (
 
 	// ConfigServerTasksLogTimeout is a config key.
 	ConfigServerTasksLogTimeout = ConfigServerTasks + ".logTimeout"
+
+	// ConfigServerTasksStore is a config key.
+	ConfigServerTasksStore = ConfigServerTasks + ".store"
+
+	// ConfigServerTasksStoreType is a config key.
+	ConfigServerTasksStoreType = ConfigServerTasksStore + ".type"
+
+	// ConfigServerTasksStorePath is a config key.
+	ConfigServerTasksStorePath = ConfigServerTasksStore + ".path"
+
+	// ConfigServerAuth is a config key.
+	ConfigServerAuth = ConfigServer + ".auth"
+
+	// ConfigServerAuthEnabled is a config key.
+	ConfigServerAuthEnabled = ConfigServerAuth + ".enabled"
+
+	// ConfigServerAuthTokens is a config key.
+	ConfigServerAuthTokens = ConfigServerAuth + ".tokens"
+
+	// ConfigServerAuthJWT is a config key.
+	ConfigServerAuthJWT = ConfigServerAuth + ".jwt"
+
+	// ConfigServerAuthJWTKey is a config key.
+	ConfigServerAuthJWTKey = ConfigServerAuthJWT + ".key"
+
+	// ConfigServerAuthJWTKeyFile is a config key.
+	ConfigServerAuthJWTKeyFile = ConfigServerAuthJWT + ".keyFile"
+
+	// ConfigServerAdmin is a config key.
+	ConfigServerAdmin = ConfigServer + ".admin"
+
+	// ConfigServerAdminTokens is a config key.
+	ConfigServerAdminTokens = ConfigServerAdmin + ".tokens"
+
+	// ConfigServerAdminTokenFile is a config key.
+	ConfigServerAdminTokenFile = ConfigServerAdmin + ".tokenFile"
+
+	// ConfigServerAudit is a config key.
+	ConfigServerAudit = ConfigServer + ".audit"
+
+	// ConfigServerAuditEnabled is a config key.
+	ConfigServerAuditEnabled = ConfigServerAudit + ".enabled"
+
+	// ConfigServerAuditSink is a config key.
+	ConfigServerAuditSink = ConfigServerAudit + ".sink"
+
+	// ConfigServerAuditBufferSize is a config key.
+	ConfigServerAuditBufferSize = ConfigServerAudit + ".bufferSize"
+
+	// ConfigServerAuditFilePath is a config key.
+	ConfigServerAuditFilePath = ConfigServerAudit + ".file.path"
+
+	// ConfigServerAuditFileMaxSize is a config key.
+	ConfigServerAuditFileMaxSize = ConfigServerAudit + ".file.maxSize"
+
+	// ConfigServerAuditFileMaxBackups is a config key.
+	ConfigServerAuditFileMaxBackups = ConfigServerAudit + ".file.maxBackups"
+
+	// ConfigServerAuditSyslogNetwork is a config key.
+	ConfigServerAuditSyslogNetwork = ConfigServerAudit + ".syslog.network"
+
+	// ConfigServerAuditSyslogAddress is a config key.
+	ConfigServerAuditSyslogAddress = ConfigServerAudit + ".syslog.address"
+
+	// ConfigServerAuditSyslogTag is a config key.
+	ConfigServerAuditSyslogTag = ConfigServerAudit + ".syslog.tag"
+
+	// ConfigClientAuthToken is a config key.
+	ConfigClientAuthToken = ConfigClient + ".auth.token"
+
+	// ConfigPolicy is a config key.
+	ConfigPolicy = ConfigRoot + ".policy"
+
+	// ConfigPolicyMaxVolumes is a config key.
+	ConfigPolicyMaxVolumes = ConfigPolicy + ".maxVolumes"
+
+	// ConfigPolicyMaxCapacity is a config key.
+	ConfigPolicyMaxCapacity = ConfigPolicy + ".maxCapacity"
+
+	// ConfigPolicyTypes is a config key.
+	ConfigPolicyTypes = ConfigPolicy + ".types"
+
+	// ConfigPolicyMaxIOPS is a config key.
+	ConfigPolicyMaxIOPS = ConfigPolicy + ".maxIOPS"
+
+	// ConfigPolicyRequiredFields is a config key.
+	ConfigPolicyRequiredFields = ConfigPolicy + ".requiredFields"
+
+	// ConfigListTimeout is a config key.
+	ConfigListTimeout = ConfigRoot + ".listTimeout"
+
+	// ConfigCSI is a config key.
+	ConfigCSI = ConfigRoot + ".csi"
+
+	// ConfigCSIName is a config key.
+	ConfigCSIName = ConfigCSI + ".name"
+
+	// ConfigCSIService is a config key.
+	ConfigCSIService = ConfigCSI + ".service"
+
+	// ConfigCSIControllerEndpoint is a config key.
+	ConfigCSIControllerEndpoint = ConfigCSI + ".controller.endpoint"
+
+	// ConfigCSINodeEndpoint is a config key.
+	ConfigCSINodeEndpoint = ConfigCSI + ".node.endpoint"
 )
@@ -19,6 +19,9 @@ const (
 	//ConfigIgVolOpsMountPath is a config key.
 	ConfigIgVolOpsMountPath = ConfigIgVolOpsMount + ".path"
 
+	//ConfigIgVolOpsMountStateFile is a config key.
+	ConfigIgVolOpsMountStateFile = ConfigIgVolOpsMount + ".stateFile"
+
 	//ConfigIgVolOpsMountRootPath is a config key.
 	ConfigIgVolOpsMountRootPath = ConfigIgVolOpsMount + ".rootPath"
 
@@ -72,4 +75,16 @@ const (
 
 	// ConfigIgVolOpsRemoveDisable is a config key.
 	ConfigIgVolOpsRemoveDisable = ConfigIgVolOpsRemove + ".disable"
+
+	// ConfigIgPlugin is a config key.
+	ConfigIgPlugin = ConfigIg + ".plugin"
+
+	// ConfigIgPluginName is a config key.
+	ConfigIgPluginName = ConfigIgPlugin + ".name"
+
+	// ConfigIgPluginPath is a config key.
+	ConfigIgPluginPath = ConfigIgPlugin + ".path"
+
+	// ConfigIgPluginScope is a config key.
+	ConfigIgPluginScope = ConfigIgPlugin + ".scope"
 )
@@ -37,6 +37,10 @@ const (
 	// LSXCmdSupported is the command to execute to find out if an executor
 	// is valid for a given platform on the current host.
 	LSXCmdSupported = "supported"
+
+	// LSXCmdMounts is the command to execute to inspect or repair the mount
+	// state persisted by the integration driver manager.
+	LSXCmdMounts = "mounts"
 )
 
 const (
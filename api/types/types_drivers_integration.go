@@ -9,8 +9,44 @@ type VolumeMountOpts struct {
 	NewFSType   string
 	Preempt     bool
 	Opts        Store
+
+	// ConsumerID identifies the consumer, such as a container, on whose
+	// behalf the volume is mounted. A volume is not unmounted until each of
+	// its consumers has unmounted it.
+	ConsumerID string
 }
 
+// ConsumerIDOptKey is the key of the option that identifies the consumer
+// on whose behalf a volume is unmounted.
+const ConsumerIDOptKey = "consumerID"
+
+// VolumeMountState is the persisted mount state of a volume.
+type VolumeMountState struct {
+	// VolumeID is the ID of the volume.
+	VolumeID string `json:"volumeID,omitempty"`
+
+	// MountPoint is the path at which the volume is mounted.
+	MountPoint string `json:"mountPoint,omitempty"`
+
+	// Consumers is the number of times each consumer has mounted the
+	// volume, keyed by consumer ID. Mounts without a consumer ID are
+	// counted with the empty key.
+	Consumers map[string]int `json:"consumers"`
+}
+
+// Count returns the number of the volume's outstanding mounts.
+func (s *VolumeMountState) Count() int {
+	var count int
+	for _, c := range s.Consumers {
+		count = count + c
+	}
+	return count
+}
+
+// VolumeMountStates is the persisted mount state of volumes, keyed by volume
+// name.
+type VolumeMountStates map[string]*VolumeMountState
+
 // VolumeMapping is a volume's name and the path to which it is mounted.
 type VolumeMapping interface {
 	// VolumeName returns the volume's name.
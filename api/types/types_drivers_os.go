@@ -14,9 +14,29 @@ type DeviceMountOpts struct {
 type DeviceFormatOpts struct {
 	NewFSType   string
 	OverwriteFS bool
-	Opts        Store
+
+	// Encrypted requests that the OS driver encrypts the device before it
+	// creates the file system. An OS driver may also be configured to encrypt
+	// all of the devices it formats.
+	Encrypted bool
+
+	Opts Store
 }
 
+const (
+	// VolumeFieldEncryption is the key of the volume field that records the
+	// type of the encryption that an OS driver applied to the volume's device,
+	// such as "luks". An OS driver that encrypts or opens an encrypted device
+	// sets this key in the Opts store of the format or mount operation so
+	// that the caller is able to record it in the volume's fields.
+	VolumeFieldEncryption = "encryption"
+
+	// VolumeFieldEncryptionKeyProvider is the key of the volume field that
+	// records the name of the provider of the key with which the volume's
+	// device is encrypted.
+	VolumeFieldEncryptionKeyProvider = "encryptionKeyProvider"
+)
+
 // OSDriverManager is the management wrapper for an OSDriver.
 type OSDriverManager interface {
 	OSDriver
@@ -60,3 +80,16 @@ type OSDriver interface {
 		deviceName string,
 		opts *DeviceFormatOpts) error
 }
+
+// OSDriverWithResize is an OSDriver that is able to grow a mounted file
+// system to fill its underlying device after the device has been resized.
+type OSDriverWithResize interface {
+	OSDriver
+
+	// Resize grows the file system on a device mounted at the specified path
+	// to the size of the device.
+	Resize(
+		ctx Context,
+		deviceName, mountPoint string,
+		opts Store) error
+}
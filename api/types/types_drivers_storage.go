@@ -212,7 +212,31 @@ func (v VolumeAttachmentsTypes) bitSet(b VolumeAttachmentsTypes) bool {
 // VolumesOpts are options when inspecting a volume.
 type VolumesOpts struct {
 	Attachments VolumeAttachmentsTypes
-	Opts        Store
+
+	// Page is the page of volumes requested by the caller. A driver that
+	// cannot page its volumes natively ignores the page and returns all of
+	// the volumes, and the server pages them instead. The page is nil when
+	// all of the volumes are requested.
+	Page *VolumesPage
+
+	Opts Store
+}
+
+// VolumesPage is a page of volumes requested from a storage driver.
+type VolumesPage struct {
+	// Limit is the maximum number of volumes to return.
+	Limit int
+
+	// Token is the driver's token of the page to return, as set to Next by
+	// the driver for the previous page. The first page has an empty token.
+	Token string
+
+	// Next is set by a driver that paged the volumes to the token of the
+	// next page. It is empty when there are no more volumes.
+	Next string
+
+	// Paged is set by a driver that paged the volumes natively.
+	Paged bool
 }
 
 // VolumeInspectOpts are options when inspecting a volume.
@@ -245,6 +269,27 @@ type VolumeDetachOpts struct {
 	Opts  Store
 }
 
+// VolumeResizeOpts are options for resizing a volume.
+type VolumeResizeOpts struct {
+	Size int64
+	Opts Store
+}
+
+// VolumeUpdateOpts are options for updating a volume.
+type VolumeUpdateOpts struct {
+	// Name is the volume's new name. The name is unchanged if nil.
+	Name *string
+
+	// Fields are added to the volume's fields, replacing any existing
+	// fields with the same keys.
+	Fields map[string]string
+
+	// RemoveFields are the keys of the fields to remove from the volume.
+	RemoveFields []string
+
+	Opts Store
+}
+
 // StorageDriverManager is the management wrapper for a StorageDriver.
 type StorageDriverManager interface {
 	StorageDriver
@@ -321,6 +366,20 @@ type StorageDriver interface {
 		volumeID string,
 		opts Store) error
 
+	// VolumeResize grows a volume to the size specified by the options. An
+	// error is returned if the requested size is smaller than the volume's
+	// current size.
+	VolumeResize(
+		ctx Context,
+		volumeID string,
+		opts *VolumeResizeOpts) (*Volume, error)
+
+	// VolumeUpdate renames a volume and/or sets or removes its fields.
+	VolumeUpdate(
+		ctx Context,
+		volumeID string,
+		opts *VolumeUpdateOpts) (*Volume, error)
+
 	// VolumeAttach attaches a volume and provides a token clients can use
 	// to validate that device has appeared locally.
 	VolumeAttach(
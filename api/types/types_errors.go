@@ -44,6 +44,44 @@ type ErrDriverTypeErr struct{ goof.Goof }
 // the objects for which the process did complete.
 type ErrBatchProcess struct{ goof.Goof }
 
+// ErrPartialResult occurs when some of the services fail to respond to a
+// request that lists the resources of multiple services. The resources of
+// the services that did respond are returned along with the error.
+type ErrPartialResult struct {
+	goof.Goof
+
+	// Errors are the errors of the services that failed to respond.
+	Errors ServiceErrorMap
+}
+
 // ErrBadFilter occurs when a bad filter is supplied via the filter query
 // string.
 type ErrBadFilter struct{ goof.Goof }
+
+// ErrUnauthorized occurs when a request is made without valid
+// authentication credentials.
+type ErrUnauthorized struct{ goof.Goof }
+
+// ErrForbidden occurs when an authenticated identity has not been granted
+// the role required to perform an operation.
+type ErrForbidden struct{ goof.Goof }
+
+// ErrPolicyViolation occurs when a request violates a service's quota or
+// policy.
+type ErrPolicyViolation struct{ goof.Goof }
+
+// ErrBadPage occurs when a request for a page of a list has an invalid
+// limit, sort, or next token.
+type ErrBadPage struct{ goof.Goof }
+
+// ErrBadInstanceID occurs when a request targets an instance ID that is not
+// valid for the requested service.
+type ErrBadInstanceID struct{ goof.Goof }
+
+// ErrTaskCompleted occurs when an operation that requires an incomplete task,
+// such as cancellation, is performed on a task that has already completed.
+type ErrTaskCompleted struct{ goof.Goof }
+
+// ErrLockTimeout occurs when a lock could not be acquired before the
+// caller's deadline or the configured timeout.
+type ErrLockTimeout struct{ goof.Goof }
@@ -0,0 +1,70 @@
+package types
+
+// EventType is the type of an event.
+type EventType string
+
+const (
+	// EventTypeTask is the type of event emitted when a task's state changes.
+	EventTypeTask EventType = "task"
+
+	// EventTypeVolumeCreated is the type of event emitted when a volume is
+	// created.
+	EventTypeVolumeCreated EventType = "volume.created"
+
+	// EventTypeVolumeRemoved is the type of event emitted when a volume is
+	// removed.
+	EventTypeVolumeRemoved EventType = "volume.removed"
+
+	// EventTypeVolumeAttached is the type of event emitted when a volume is
+	// attached.
+	EventTypeVolumeAttached EventType = "volume.attached"
+
+	// EventTypeVolumeDetached is the type of event emitted when a volume is
+	// detached.
+	EventTypeVolumeDetached EventType = "volume.detached"
+
+	// EventTypeVolumeResized is the type of event emitted when a volume is
+	// resized.
+	EventTypeVolumeResized EventType = "volume.resized"
+
+	// EventTypeVolumeUpdated is the type of event emitted when a volume's name
+	// or fields are updated.
+	EventTypeVolumeUpdated EventType = "volume.updated"
+
+	// EventTypeSnapshotCreated is the type of event emitted when a snapshot
+	// is created.
+	EventTypeSnapshotCreated EventType = "snapshot.created"
+
+	// EventTypeSnapshotRemoved is the type of event emitted when a snapshot
+	// is removed.
+	EventTypeSnapshotRemoved EventType = "snapshot.removed"
+)
+
+// Event is a notification that a task or a storage resource has changed.
+type Event struct {
+	// Type is the type of the event.
+	Type EventType `json:"type" yaml:"type"`
+
+	// Time is the time stamp when the event occurred.
+	Time int64 `json:"time" yaml:"time"`
+
+	// Service is the name of the service to which the event is related.
+	Service string `json:"service,omitempty" yaml:",omitempty"`
+
+	// Task is the task whose state changed.
+	Task *Task `json:"task,omitempty" yaml:",omitempty"`
+
+	// VolumeID is the ID of the volume to which the event is related.
+	VolumeID string `json:"volumeID,omitempty" yaml:"volumeID,omitempty"`
+
+	// Volume is the volume to which the event is related. This field is not
+	// set when a volume is removed.
+	Volume *Volume `json:"volume,omitempty" yaml:",omitempty"`
+
+	// SnapshotID is the ID of the snapshot to which the event is related.
+	SnapshotID string `json:"snapshotID,omitempty" yaml:"snapshotID,omitempty"`
+
+	// Snapshot is the snapshot to which the event is related. This field is
+	// not set when a snapshot is removed.
+	Snapshot *Snapshot `json:"snapshot,omitempty" yaml:",omitempty"`
+}
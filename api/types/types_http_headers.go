@@ -18,4 +18,21 @@ const (
 	// for the first time. This header is provided with every response sent
 	// from the server.
 	ServerNameHeader = "Libstorage-Servername"
+
+	// AuthorizationHeader is the HTTP header that contains the bearer token
+	// used to authenticate a request.
+	AuthorizationHeader = "Authorization"
+
+	// AdminTokenHeader is the HTTP header that contains the token used to
+	// access the server's admin routes.
+	AdminTokenHeader = "Libstorage-Admintoken"
+
+	// NextPageHeader is the HTTP header that contains the token of the next
+	// page of a list of volumes or snapshots.
+	NextPageHeader = "Libstorage-Next"
+
+	// ServiceErrorsHeader is the HTTP header that contains the comma-separated
+	// names of the services that failed to respond to a request that lists
+	// the resources of multiple services.
+	ServiceErrorsHeader = "Libstorage-Serviceerrors"
 )
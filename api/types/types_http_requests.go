@@ -13,12 +13,14 @@ type VolumeCreateRequest struct {
 	IOPS             *int64                 `json:"iops,omitempty"`
 	Size             *int64                 `json:"size,omitempty"`
 	Type             *string                `json:"type,omitempty"`
+	Fields           map[string]string      `json:"fields,omitempty"`
 	Opts             map[string]interface{} `json:"opts,omitempty"`
 }
 
 // VolumeCopyRequest is the JSON body for copying a volume.
 type VolumeCopyRequest struct {
 	VolumeName string                 `json:"volumeName"`
+	Fields     map[string]string      `json:"fields,omitempty"`
 	Opts       map[string]interface{} `json:"opts,omitempty"`
 }
 
@@ -28,17 +30,37 @@ type VolumeSnapshotRequest struct {
 	Opts         map[string]interface{} `json:"opts,omitempty"`
 }
 
+// VolumeUpdateRequest is the JSON body for updating a volume.
+type VolumeUpdateRequest struct {
+	Name         *string                `json:"name,omitempty"`
+	Fields       map[string]string      `json:"fields,omitempty"`
+	RemoveFields []string               `json:"removeFields,omitempty"`
+	Opts         map[string]interface{} `json:"opts,omitempty"`
+}
+
+// VolumeResizeRequest is the JSON body for resizing a volume.
+type VolumeResizeRequest struct {
+	Size int64                  `json:"size"`
+	Opts map[string]interface{} `json:"opts,omitempty"`
+}
+
 // VolumeAttachRequest is the JSON body for attaching a volume to an instance.
+// The volume is attached to the caller's instance unless InstanceID specifies
+// another instance.
 type VolumeAttachRequest struct {
 	Force          bool                   `json:"force,omitempty"`
+	InstanceID     *InstanceID            `json:"instanceID,omitempty"`
 	NextDeviceName *string                `json:"nextDeviceName,omitempty"`
 	Opts           map[string]interface{} `json:"opts,omitempty"`
 }
 
 // VolumeDetachRequest is the JSON body for detaching a volume from an instance.
+// The volume is detached from the caller's instance unless InstanceID
+// specifies another instance.
 type VolumeDetachRequest struct {
-	Force bool                   `json:"force,omitempty"`
-	Opts  map[string]interface{} `json:"opts,omitempty"`
+	Force      bool                   `json:"force,omitempty"`
+	InstanceID *InstanceID            `json:"instanceID,omitempty"`
+	Opts       map[string]interface{} `json:"opts,omitempty"`
 }
 
 // SnapshotCopyRequest is the JSON body for copying a snapshot.
@@ -1,6 +1,12 @@
 package types
 
-import "strconv"
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/akutz/goof"
+)
 
 // StorageType is the type of storage a driver provides.
 type StorageType string
@@ -29,6 +35,33 @@ type ServiceVolumeMap map[string]VolumeMap
 // services.
 type ServiceSnapshotMap map[string]SnapshotMap
 
+// ServiceError is the error of a service that failed to respond to a request
+// that lists the resources of multiple services.
+type ServiceError struct {
+	// Message is the error's message.
+	Message string `json:"message" yaml:"message"`
+
+	// TimedOut indicates that the service did not respond before its list
+	// timeout expired.
+	TimedOut bool `json:"timedOut,omitempty" yaml:"timedOut,omitempty"`
+}
+
+// ServiceErrorMap is the errors of the services that failed to respond to a
+// request that lists the resources of multiple services, keyed by service
+// name.
+type ServiceErrorMap map[string]*ServiceError
+
+// ServiceResult is the response for listing the resources of multiple
+// services when the request includes the serviceErrors query parameter.
+type ServiceResult struct {
+	// Result is the resources of the services that responded, such as a
+	// ServiceVolumeMap.
+	Result interface{} `json:"result" yaml:"result"`
+
+	// Errors are the errors of the services that failed to respond.
+	Errors ServiceErrorMap `json:"errors,omitempty" yaml:"errors,omitempty"`
+}
+
 // ServicesMap is the response when getting one to many ServiceInfos.
 type ServicesMap map[string]*ServiceInfo
 
@@ -362,6 +395,14 @@ const (
 
 	// TaskStateError is the state for a task that has completed with an error.
 	TaskStateError = "error"
+
+	// TaskStateInterrupted is the state for a task that was running when the
+	// server that executed it was stopped.
+	TaskStateInterrupted = "interrupted"
+
+	// TaskStateCancelled is the state for a task that was cancelled before it
+	// completed.
+	TaskStateCancelled = "cancelled"
 )
 
 // Task is a representation of an asynchronous, long-running task.
@@ -372,6 +413,9 @@ type Task struct {
 	// User is the name of the user that created the task.
 	User string `json:"user,omitempty" yaml:",omitempty"`
 
+	// Service is the name of the service for which the task was created.
+	Service string `json:"service,omitempty" yaml:",omitempty"`
+
 	// CompleteTime is the time stamp when the task was completed
 	// (whether success or failure).
 	CompleteTime int64 `json:"completeTime,omitempty" yaml:"completeTime,omitempty"`
@@ -388,6 +432,96 @@ type Task struct {
 	// Result holds the result of the task.
 	Result interface{} `json:"result,omitempty" yaml:",omitempty"`
 
+	// Next is the token of the next page when the task's result is a page of
+	// a list of volumes or snapshots.
+	Next string `json:"next,omitempty" yaml:",omitempty"`
+
+	// ServiceErrors are the errors of the services that failed to respond
+	// when the task's result is a list of the resources of multiple services.
+	ServiceErrors ServiceErrorMap `json:"serviceErrors,omitempty" yaml:"serviceErrors,omitempty"`
+
 	// Error contains the error if the task was unsuccessful.
 	Error error `json:"error,omitempty" yaml:",omitempty"`
 }
+
+// UnmarshalJSON unmarshals the task from JSON. Because the concrete type of
+// the task's error is unknown, the error is restored as a new error with the
+// original error's message.
+func (t *Task) UnmarshalJSON(data []byte) error {
+	type task Task
+	ut := &struct {
+		*task
+		Error json.RawMessage `json:"error,omitempty"`
+	}{task: (*task)(t)}
+
+	if err := json.Unmarshal(data, ut); err != nil {
+		return err
+	}
+
+	t.Error = nil
+	if len(ut.Error) == 0 || string(ut.Error) == "null" {
+		return nil
+	}
+
+	var msg string
+	if err := json.Unmarshal(ut.Error, &msg); err == nil {
+		t.Error = goof.New(msg)
+		return nil
+	}
+
+	var obj map[string]interface{}
+	if err := json.Unmarshal(ut.Error, &obj); err != nil {
+		return err
+	}
+	for _, k := range []string{"message", "msg"} {
+		if v, ok := obj[k].(string); ok {
+			t.Error = goof.New(v)
+			return nil
+		}
+	}
+	t.Error = goof.New(string(ut.Error))
+	return nil
+}
+
+// TasksOpts are the options used to filter a list of tasks. Zero values are
+// ignored.
+type TasksOpts struct {
+	// State matches tasks in the specified state.
+	State TaskState
+
+	// User matches tasks created by the specified user.
+	User string
+
+	// Service matches tasks created for the specified service.
+	Service string
+
+	// Since matches tasks queued at or after the specified time stamp.
+	Since int64
+
+	// Until matches tasks queued at or before the specified time stamp.
+	Until int64
+}
+
+// Match returns a flag indicating whether or not the task matches the
+// options.
+func (o *TasksOpts) Match(t *Task) bool {
+	if o == nil {
+		return true
+	}
+	if o.State != "" && o.State != t.State {
+		return false
+	}
+	if o.User != "" && o.User != t.User {
+		return false
+	}
+	if o.Service != "" && !strings.EqualFold(o.Service, t.Service) {
+		return false
+	}
+	if o.Since > 0 && t.QueueTime < o.Since {
+		return false
+	}
+	if o.Until > 0 && t.QueueTime > o.Until {
+		return false
+	}
+	return true
+}
@@ -1,6 +1,7 @@
 package types
 
 import (
+	"encoding/json"
 	"fmt"
 	"testing"
 
@@ -85,3 +86,75 @@ func TestInstanceWithOnlyInstanceIDMarshalToYAML(t *testing.T) {
 
 	fmt.Println(string(out))
 }
+
+func TestTaskUnmarshalJSON(t *testing.T) {
+
+	task := &Task{}
+	err := json.Unmarshal([]byte(`{
+		"id": 3,
+		"state": "cancelled",
+		"error": { "message": "task cancelled", "taskID": 3 }
+	}`), task)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.ID != 3 || task.State != TaskStateCancelled {
+		t.Fatalf("unexpected task: %+v", task)
+	}
+	if task.Error == nil || task.Error.Error() != "task cancelled" {
+		t.Fatalf("unexpected task error: %v", task.Error)
+	}
+
+	task = &Task{}
+	if err := json.Unmarshal(
+		[]byte(`{"id": 4, "error": "failed"}`), task); err != nil {
+		t.Fatal(err)
+	}
+	if task.Error == nil || task.Error.Error() != "failed" {
+		t.Fatalf("unexpected task error: %v", task.Error)
+	}
+
+	task = &Task{}
+	if err := json.Unmarshal(
+		[]byte(`{"id": 5, "state": "success"}`), task); err != nil {
+		t.Fatal(err)
+	}
+	if task.Error != nil {
+		t.Fatalf("unexpected task error: %v", task.Error)
+	}
+}
+
+func TestTasksOptsMatch(t *testing.T) {
+
+	task := &Task{
+		ID:        1,
+		User:      "akutz",
+		Service:   "vfs",
+		State:     TaskStateRunning,
+		QueueTime: 100,
+	}
+
+	tests := []struct {
+		opts  *TasksOpts
+		match bool
+	}{
+		{nil, true},
+		{&TasksOpts{}, true},
+		{&TasksOpts{State: TaskStateRunning}, true},
+		{&TasksOpts{State: TaskStateSuccess}, false},
+		{&TasksOpts{User: "akutz"}, true},
+		{&TasksOpts{User: "bob"}, false},
+		{&TasksOpts{Service: "VFS"}, true},
+		{&TasksOpts{Service: "ebs"}, false},
+		{&TasksOpts{Since: 100, Until: 100}, true},
+		{&TasksOpts{Since: 101}, false},
+		{&TasksOpts{Until: 99}, false},
+	}
+
+	for i, tt := range tests {
+		if m := tt.opts.Match(task); m != tt.match {
+			t.Errorf("test %d: opts=%+v, expected=%v, actual=%v",
+				i, tt.opts, tt.match, m)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package types
+
+// Page is a page of a list of volumes or snapshots.
+type Page struct {
+	// Limit is the maximum number of resources returned for each service.
+	// All of the resources are returned when the limit is zero.
+	Limit int
+
+	// Sort is the field by which the resources are ordered when they are
+	// paged, such as "name". The field is prefixed with a hyphen, such as
+	// "-size", to order the resources in descending order. The resources are
+	// ordered by their IDs when the field is empty.
+	Sort string
+
+	// Token is the token of the page, as returned in Next with the previous
+	// page. The first page has an empty token.
+	Token string
+
+	// Next is the token of the next page. It is empty when there are no more
+	// resources.
+	Next string
+}
+
+// PagedResult is the result of a task that returns a page of a list of
+// volumes or snapshots. The task's result is set to the page's Result and
+// its Next and ServiceErrors fields to the page's Next and ServiceErrors
+// fields.
+type PagedResult struct {
+	Result        interface{}
+	Next          string
+	ServiceErrors ServiceErrorMap
+}
@@ -1,5 +1,7 @@
 package types
 
+import gofig "github.com/akutz/gofig/types"
+
 // Service is the base type for services.
 type Service interface {
 	Driver
@@ -28,6 +30,9 @@ type StorageTaskRunFunc func(
 type StorageService interface {
 	Service
 
+	// Config returns the service's configuration.
+	Config() gofig.Config
+
 	// Driver returns the service's StorageDriver.
 	Driver() StorageDriver
 
@@ -58,6 +63,9 @@ type TaskTrackingService interface {
 	// TaskInspect returns the task with the specified ID.
 	TaskInspect(taskID int) *Task
 
+	// TaskCancel cancels the task with the specified ID.
+	TaskCancel(taskID int) (*Task, error)
+
 	// TaskWait blocks until the specified task completes.
 	TaskWait(taskID int) <-chan int
 
@@ -83,3 +91,27 @@ type TaskExecutionService interface {
 		run TaskRunFunc,
 		schema []byte) *Task
 }
+
+// NewTaskStore is a function that constructs a new TaskStore.
+type NewTaskStore func() TaskStore
+
+// TaskStore is used by the task service to persist tasks.
+type TaskStore interface {
+	// Name returns the name of the task store.
+	Name() string
+
+	// Init initializes the task store.
+	Init(ctx Context, config gofig.Config) error
+
+	// NextID returns the next task ID. Task IDs are never reused.
+	NextID(ctx Context) (int, error)
+
+	// Save creates or updates the task.
+	Save(ctx Context, task *Task) error
+
+	// Remove removes the task with the specified ID.
+	Remove(ctx Context, taskID int) error
+
+	// Tasks returns all of the tasks in the store.
+	Tasks(ctx Context) ([]*Task, error)
+}
@@ -97,11 +97,24 @@ func compileFilterSet(
 
 func compileFilter(s string, pos int) (*types.Filter, int, error) {
 
+	if pos >= len(s) {
+		return nil, pos, errUnexpectedEOF
+	}
+
 	switch s[pos] {
 	case '(':
 		f, newPos, err := compileFilter(s, pos+1)
+		if err != nil {
+			return f, newPos, err
+		}
+		if newPos >= len(s) {
+			return nil, newPos, errUnexpectedEOF
+		}
+		if s[newPos] != ')' {
+			return nil, newPos, errParse
+		}
 		newPos++
-		return f, newPos, err
+		return f, newPos, nil
 
 	case '&':
 		f := &types.Filter{Op: filterAnd}
@@ -137,6 +150,9 @@ func compileFilter(s string, pos int) (*types.Filter, int, error) {
 			case s[newPos] == '=':
 				f = &types.Filter{Op: filterEqualityMatch}
 
+			case newPos+1 == len(s) && isCompareByte(s[newPos]):
+				return nil, 0, errParse
+
 			case s[newPos] == '>' && s[newPos+1] == '=':
 				f = &types.Filter{Op: filterGreaterOrEqual}
 				newPos++
@@ -161,7 +177,7 @@ func compileFilter(s string, pos int) (*types.Filter, int, error) {
 			return f, newPos, errUnexpectedEOF
 		}
 
-		if f == nil {
+		if f == nil || cbuf.Len() == 0 {
 			return nil, 0, errParse
 		}
 
@@ -199,3 +215,7 @@ func compileFilter(s string, pos int) (*types.Filter, int, error) {
 		return f, newPos, nil
 	}
 }
+
+func isCompareByte(b byte) bool {
+	return b == '>' || b == '<' || b == '~'
+}
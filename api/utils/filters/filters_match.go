@@ -0,0 +1,198 @@
+package filters
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+// fieldsPrefix is the prefix used by a filter's left operand to indicate the
+// value should be read from an object's Fields map.
+const fieldsPrefix = "fields."
+
+// attrFunc returns the value of an object's attribute and a flag indicating
+// whether or not the attribute is present.
+type attrFunc func(key string) (string, bool)
+
+// MatchVolume returns a flag indicating whether or not the volume satisfies
+// the provided filter. A nil filter matches all volumes.
+//
+// The filter's left operands are case-insensitive and may be any of the
+// volume's JSON attribute names, ex. "name", "size", "status",
+// "attachmentState", or "availabilityZone". Values in the volume's Fields map
+// are addressed using the "fields." prefix, ex. "fields.owner".
+func MatchVolume(f *types.Filter, v *types.Volume) bool {
+	if f == nil {
+		return true
+	}
+	if v == nil {
+		return false
+	}
+	return match(f, func(key string) (string, bool) {
+		switch key {
+		case "id":
+			return v.ID, v.ID != ""
+		case "name":
+			return v.Name, v.Name != ""
+		case "type":
+			return v.Type, v.Type != ""
+		case "status":
+			return v.Status, v.Status != ""
+		case "availabilityzone":
+			return v.AvailabilityZone, v.AvailabilityZone != ""
+		case "networkname":
+			return v.NetworkName, v.NetworkName != ""
+		case "size":
+			return strconv.FormatInt(v.Size, 10), true
+		case "iops":
+			return strconv.FormatInt(v.IOPS, 10), true
+		case "encrypted":
+			return strconv.FormatBool(v.Encrypted), true
+		case "attachmentstate":
+			if v.AttachmentState == 0 {
+				return "", false
+			}
+			return v.AttachmentState.String(), true
+		}
+		return getField(v.Fields, key)
+	})
+}
+
+// MatchSnapshot returns a flag indicating whether or not the snapshot
+// satisfies the provided filter. A nil filter matches all snapshots.
+//
+// The filter's left operands are case-insensitive and may be any of the
+// snapshot's JSON attribute names, ex. "name", "volumeID", "volumeSize", or
+// "status". Values in the snapshot's Fields map are addressed using the
+// "fields." prefix, ex. "fields.owner".
+func MatchSnapshot(f *types.Filter, s *types.Snapshot) bool {
+	if f == nil {
+		return true
+	}
+	if s == nil {
+		return false
+	}
+	return match(f, func(key string) (string, bool) {
+		switch key {
+		case "id":
+			return s.ID, s.ID != ""
+		case "name":
+			return s.Name, s.Name != ""
+		case "description":
+			return s.Description, s.Description != ""
+		case "status":
+			return s.Status, s.Status != ""
+		case "volumeid":
+			return s.VolumeID, s.VolumeID != ""
+		case "volumesize":
+			return strconv.FormatInt(s.VolumeSize, 10), true
+		case "starttime":
+			return strconv.FormatInt(s.StartTime, 10), true
+		case "encrypted":
+			return strconv.FormatBool(s.Encrypted), true
+		}
+		return getField(s.Fields, key)
+	})
+}
+
+func getField(fields map[string]string, key string) (string, bool) {
+	if !strings.HasPrefix(key, fieldsPrefix) || len(fields) == 0 {
+		return "", false
+	}
+	key = key[len(fieldsPrefix):]
+	for k, v := range fields {
+		if strings.EqualFold(k, key) {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+func match(f *types.Filter, attr attrFunc) bool {
+
+	switch f.Op {
+	case filterAnd:
+		for _, c := range f.Children {
+			if !match(c, attr) {
+				return false
+			}
+		}
+		return true
+
+	case filterOr:
+		for _, c := range f.Children {
+			if match(c, attr) {
+				return true
+			}
+		}
+		return false
+
+	case filterNot:
+		if len(f.Children) == 0 || f.Children[0] == nil {
+			return false
+		}
+		return !match(f.Children[0], attr)
+	}
+
+	lv, ok := attr(strings.ToLower(f.Left))
+	if !ok {
+		return false
+	}
+
+	var (
+		left  = strings.ToLower(lv)
+		right = strings.ToLower(f.Right)
+	)
+
+	switch f.Op {
+	case filterPresent:
+		return true
+	case filterEqualityMatch:
+		if c, ok := compareInts(left, right); ok {
+			return c == 0
+		}
+		return left == right
+	case filterSubstrings:
+		return strings.Contains(left, right)
+	case filterSubstringsPrefix:
+		return strings.HasSuffix(left, right)
+	case filterSubstringsPostfix:
+		return strings.HasPrefix(left, right)
+	case filterGreaterOrEqual:
+		if c, ok := compareInts(left, right); ok {
+			return c >= 0
+		}
+		return left >= right
+	case filterLessOrEqual:
+		if c, ok := compareInts(left, right); ok {
+			return c <= 0
+		}
+		return left <= right
+	case filterApproxMatch:
+		return strings.Join(strings.Fields(left), " ") ==
+			strings.Join(strings.Fields(right), " ")
+	}
+
+	return false
+}
+
+// compareInts compares two strings as integers. The second return value is
+// false if either of the strings cannot be parsed as an integer.
+func compareInts(left, right string) (int, bool) {
+	l, err := strconv.ParseInt(left, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	r, err := strconv.ParseInt(right, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	switch {
+	case l < r:
+		return -1, true
+	case l > r:
+		return 1, true
+	}
+	return 0, true
+}
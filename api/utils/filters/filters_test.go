@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/codedellemc/libstorage/api/types"
 )
 
 func TestCompilePresent(t *testing.T) {
@@ -145,3 +147,104 @@ func TestCompileInequalityMatch(t *testing.T) {
 	assert.EqualValues(t, "department", f.Children[1].Left)
 	assert.EqualValues(t, "finance", f.Children[1].Right)
 }
+
+func TestCompileMalformed(t *testing.T) {
+	for _, s := range []string{
+		`(name=)`,
+		`(&(name=)(size>=1))`,
+		`(size>`,
+		`(size<`,
+		`(size~`,
+	} {
+		f, err := CompileFilter(s)
+		assert.Equal(t, errParse, err, s)
+		assert.Nil(t, f, s)
+	}
+
+	for _, s := range []string{
+		`(`,
+		`((`,
+		`(!`,
+		`(&`,
+		`(name=irvine`,
+		`(((((&)`,
+	} {
+		_, err := CompileFilter(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestMatchVolume(t *testing.T) {
+	v := &types.Volume{
+		ID:               "vol-000",
+		Name:             "Volume 0",
+		Size:             128,
+		Status:           "available",
+		AttachmentState:  types.VolumeAvailable,
+		AvailabilityZone: "us-east-1a",
+		Fields: map[string]string{
+			"owner": "teamA",
+		},
+	}
+
+	match := func(fsz string) bool {
+		f, err := CompileFilter(fsz)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return MatchVolume(f, v)
+	}
+
+	assert.True(t, MatchVolume(nil, v))
+	assert.True(t, match(`(name=volume 0)`))
+	assert.False(t, match(`(name=volume 1)`))
+	assert.True(t, match(`(name=vol*)`))
+	assert.True(t, match(`(name=*0)`))
+	assert.True(t, match(`(id=*-00*)`))
+	assert.True(t, match(`(name~=volume  0)`))
+	assert.True(t, match(`(size>=100)`))
+	assert.False(t, match(`(size>=1000)`))
+	assert.True(t, match(`(size<=128)`))
+	assert.True(t, match(`(size=128)`))
+	assert.True(t, match(`(attachmentState=available)`))
+	assert.True(t, match(`(availabilityZone=us-east-1*)`))
+	assert.True(t, match(`(fields.owner=*)`))
+	assert.False(t, match(`(fields.group=*)`))
+	assert.True(t, match(`(fields.Owner=teama)`))
+	assert.True(t, match(
+		`(&(size>=100)(fields.owner=teamA)(!(status=in-use)))`))
+	assert.False(t, match(
+		`(&(size>=100)(fields.owner=teamB)(!(status=in-use)))`))
+	assert.True(t, match(
+		`(|(fields.owner=teamB)(availabilityZone=us-east-1a))`))
+	assert.False(t, match(`(!(size>=100))`))
+}
+
+func TestMatchSnapshot(t *testing.T) {
+	s := &types.Snapshot{
+		ID:         "snap-000",
+		Name:       "Snapshot 0",
+		VolumeID:   "vol-000",
+		VolumeSize: 64,
+		Status:     "completed",
+		Fields: map[string]string{
+			"owner": "teamA",
+		},
+	}
+
+	match := func(fsz string) bool {
+		f, err := CompileFilter(fsz)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return MatchSnapshot(f, s)
+	}
+
+	assert.True(t, MatchSnapshot(nil, s))
+	assert.True(t, match(`(volumeID=vol-000)`))
+	assert.False(t, match(`(volumeID=vol-001)`))
+	assert.True(t, match(`(volumeSize<=64)`))
+	assert.False(t, match(`(volumeSize>=65)`))
+	assert.True(t, match(`(&(status=completed)(fields.owner=teamA))`))
+	assert.False(t, match(`(description=*)`))
+}
@@ -0,0 +1,125 @@
+/*
+Package metrics collects the libStorage server's Prometheus metrics.
+
+The metrics are registered with a registry that belongs to this package
+rather than Prometheus's default registry so that a program that embeds
+libStorage does not expose them unintentionally.
+*/
+package metrics
+
+import (
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "libstorage"
+
+var (
+	registry = prometheus.NewRegistry()
+
+	httpRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "The number of HTTP requests by route and status.",
+		},
+		[]string{"route", "method", "code"})
+
+	httpDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "The latency of HTTP requests by route.",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"route", "method"})
+
+	driverCalls = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "driver",
+			Name:      "calls_total",
+			Help:      "The number of storage driver calls.",
+		},
+		[]string{"driver", "method"})
+
+	driverErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "driver",
+			Name:      "errors_total",
+			Help:      "The number of storage driver calls that failed.",
+		},
+		[]string{"driver", "method"})
+
+	driverDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "driver",
+			Name:      "call_duration_seconds",
+			Help:      "The latency of storage driver calls.",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"driver", "method"})
+
+	executorDownloads = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "executor",
+			Name:      "downloads_total",
+			Help:      "The number of executor binaries downloaded.",
+		},
+		[]string{"executor"})
+)
+
+func init() {
+	MustRegister(
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(os.Getpid(), namespace),
+		httpRequests,
+		httpDuration,
+		driverCalls,
+		driverErrors,
+		driverDuration,
+		executorDownloads)
+}
+
+// MustRegister registers the provided collectors and panics if any of them
+// cannot be registered.
+func MustRegister(cs ...prometheus.Collector) {
+	registry.MustRegister(cs...)
+}
+
+// Handler returns an HTTP handler that writes the metrics in the Prometheus
+// exposition format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// ObserveRequest records an HTTP request handled by the specified route.
+func ObserveRequest(route, method string, status int, d time.Duration) {
+	httpRequests.WithLabelValues(
+		route, method, strconv.Itoa(status)).Inc()
+	httpDuration.WithLabelValues(route, method).Observe(d.Seconds())
+}
+
+// ObserveDriverCall records a call to a storage driver's method.
+func ObserveDriverCall(driver, method string, d time.Duration, err error) {
+	driverCalls.WithLabelValues(driver, method).Inc()
+	driverDuration.WithLabelValues(driver, method).Observe(d.Seconds())
+	if err != nil {
+		driverErrors.WithLabelValues(driver, method).Inc()
+	}
+}
+
+// IncExecutorDownloads records a download of the specified executor.
+func IncExecutorDownloads(executor string) {
+	executorDownloads.WithLabelValues(executor).Inc()
+}
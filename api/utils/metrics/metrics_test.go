@@ -0,0 +1,51 @@
+package metrics
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler(t *testing.T) {
+	ObserveRequest("volumes", http.MethodGet, http.StatusOK, time.Second)
+	ObserveDriverCall("vfs", "Volumes", time.Millisecond, nil)
+	ObserveDriverCall("vfs", "VolumeRemove", time.Millisecond,
+		errors.New("resource not found"))
+	IncExecutorDownloads("lsx-linux")
+
+	req, err := http.NewRequest(http.MethodGet, "/metrics", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	Handler().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	buf, err := ioutil.ReadAll(w.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := string(buf)
+
+	assert.Contains(t, body,
+		`libstorage_http_requests_total{code="200",method="GET",`+
+			`route="volumes"} 1`)
+	assert.Contains(t, body,
+		`libstorage_http_request_duration_seconds_count{method="GET",`+
+			`route="volumes"} 1`)
+	assert.Contains(t, body,
+		`libstorage_driver_calls_total{driver="vfs",method="Volumes"} 1`)
+	assert.Contains(t, body,
+		`libstorage_driver_errors_total{driver="vfs",`+
+			`method="VolumeRemove"} 1`)
+	assert.NotContains(t, body,
+		`libstorage_driver_errors_total{driver="vfs",method="Volumes"}`)
+	assert.Contains(t, body,
+		`libstorage_executor_downloads_total{executor="lsx-linux"} 1`)
+	assert.Contains(t, body, "go_goroutines")
+}
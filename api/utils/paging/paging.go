@@ -0,0 +1,363 @@
+/*
+Package paging pages the volumes and snapshots returned by the routes that
+list them.
+
+A page is requested with the limit, sort, and next query parameters. The
+limit is the maximum number of resources returned for each service, the sort
+is the field by which the resources are ordered when they are paged, and next
+is the opaque token returned with the previous page in the Libstorage-Next
+header.
+
+A service's volumes are paged by its driver when the driver pages them
+natively and no sort is requested. Otherwise the server retrieves all of the
+service's resources, orders them by the sort field and then by their IDs, and
+returns the resources that follow the last resource of the previous page.
+*/
+package paging
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/api/utils"
+)
+
+// Opts are the options of a request for a page of volumes or snapshots.
+type Opts struct {
+	// Limit is the maximum number of resources returned for each service.
+	Limit int
+
+	sort  string
+	field *field
+	desc  bool
+	token *token
+}
+
+// Cursor is the position of a service's next page.
+type Cursor struct {
+	// Native is the driver's token of the next page.
+	Native string `json:"n,omitempty"`
+
+	// After is the key of the last resource of the previous page.
+	After *key `json:"a,omitempty"`
+}
+
+type token struct {
+	Sort    string             `json:"s,omitempty"`
+	Cursors map[string]*Cursor `json:"c"`
+}
+
+// key is the position of a resource in a sorted list. A resource is ordered
+// by the string or numeric value of the sort field and then by its ID.
+type key struct {
+	S  string `json:"s,omitempty"`
+	N  int64  `json:"n,omitempty"`
+	ID string `json:"i"`
+}
+
+type field struct {
+	volume   func(v *types.Volume) key
+	snapshot func(s *types.Snapshot) key
+}
+
+var fields = map[string]*field{
+	"id": {
+		volume:   func(v *types.Volume) key { return key{} },
+		snapshot: func(s *types.Snapshot) key { return key{} },
+	},
+	"name": {
+		volume:   func(v *types.Volume) key { return key{S: v.Name} },
+		snapshot: func(s *types.Snapshot) key { return key{S: s.Name} },
+	},
+	"status": {
+		volume:   func(v *types.Volume) key { return key{S: v.Status} },
+		snapshot: func(s *types.Snapshot) key { return key{S: s.Status} },
+	},
+	"size": {
+		volume:   func(v *types.Volume) key { return key{N: v.Size} },
+		snapshot: func(s *types.Snapshot) key { return key{N: s.VolumeSize} },
+	},
+	"type": {
+		volume: func(v *types.Volume) key { return key{S: v.Type} },
+	},
+	"availabilityzone": {
+		volume: func(v *types.Volume) key { return key{S: v.AvailabilityZone} },
+	},
+	"iops": {
+		volume: func(v *types.Volume) key { return key{N: v.IOPS} },
+	},
+	"volumeid": {
+		snapshot: func(s *types.Snapshot) key { return key{S: s.VolumeID} },
+	},
+	"volumesize": {
+		snapshot: func(s *types.Snapshot) key { return key{N: s.VolumeSize} },
+	},
+	"starttime": {
+		snapshot: func(s *types.Snapshot) key { return key{N: s.StartTime} },
+	},
+}
+
+// ParseOpts parses the options of a request for a page from the store's
+// limit, sort, and next keys. A nil value is returned if none of the keys are
+// set.
+func ParseOpts(store types.Store) (*Opts, error) {
+	if !store.IsSet("limit") && !store.IsSet("sort") && !store.IsSet("next") {
+		return nil, nil
+	}
+
+	o := &Opts{}
+
+	if store.IsSet("limit") {
+		limit, err := strconv.Atoi(store.GetString("limit"))
+		if err != nil || limit < 0 {
+			return nil, utils.NewBadPageError(
+				"limit", store.GetString("limit"),
+				"limit must be a positive integer")
+		}
+		o.Limit = limit
+	}
+
+	if store.IsSet("next") {
+		next := store.GetString("next")
+		t, err := decodeToken(next)
+		if err != nil {
+			return nil, utils.NewBadPageError("next", next, "invalid token")
+		}
+		o.token = t
+		o.sort = t.Sort
+	}
+
+	if store.IsSet("sort") {
+		s := store.GetString("sort")
+		if o.token != nil && !strings.EqualFold(s, o.token.Sort) {
+			return nil, utils.NewBadPageError(
+				"sort", s, "sort does not match the token's sort")
+		}
+		o.sort = s
+	}
+
+	name := strings.ToLower(strings.TrimPrefix(o.sort, "-"))
+	if name == "" {
+		name = "id"
+	}
+	f, ok := fields[name]
+	if !ok {
+		return nil, utils.NewBadPageError("sort", o.sort, "invalid field")
+	}
+	o.field = f
+	o.desc = strings.HasPrefix(o.sort, "-")
+
+	return o, nil
+}
+
+// Done returns a flag indicating whether the service's resources have all
+// been returned with the previous pages.
+func (o *Opts) Done(service string) bool {
+	if o == nil || o.token == nil {
+		return false
+	}
+	_, ok := o.token.Cursors[service]
+	return !ok
+}
+
+// Cursor returns the position of the service's current page, such as to
+// request the page again after the service failed to return it. A nil value
+// is returned if the service's resources have all been returned.
+func (o *Opts) Cursor(service string) *Cursor {
+	if o == nil || o.Done(service) {
+		return nil
+	}
+	return o.cursor(service)
+}
+
+// VolumesPage returns the page to request from the service's driver. A nil
+// value is returned if the service's volumes are paged by the server.
+func (o *Opts) VolumesPage(service string) *types.VolumesPage {
+	if o == nil {
+		return nil
+	}
+	c := o.cursor(service)
+	if c.Native != "" {
+		return &types.VolumesPage{Limit: o.Limit, Token: c.Native}
+	}
+	if o.Limit == 0 || o.sort != "" || c.After != nil {
+		return nil
+	}
+	return &types.VolumesPage{Limit: o.Limit}
+}
+
+// Volumes returns the page of a service's volumes and the position of the
+// service's next page. The volumes are returned as they are if the driver
+// paged them natively. A nil cursor is returned if there are no more volumes.
+func (o *Opts) Volumes(
+	service string,
+	vols []*types.Volume,
+	page *types.VolumesPage) ([]*types.Volume, *Cursor) {
+
+	if o == nil {
+		return vols, nil
+	}
+	if page != nil && page.Paged {
+		if page.Next == "" {
+			return vols, nil
+		}
+		return vols, &Cursor{Native: page.Next}
+	}
+	keys := make([]key, len(vols))
+	for i, v := range vols {
+		keys[i] = o.field.volume(v)
+		keys[i].ID = v.ID
+	}
+	indices, c := o.page(service, keys)
+
+	paged := make([]*types.Volume, len(indices))
+	for i, j := range indices {
+		paged[i] = vols[j]
+	}
+	return paged, c
+}
+
+// Snapshots returns the page of a service's snapshots and the position of
+// the service's next page. A nil cursor is returned if there are no more
+// snapshots.
+func (o *Opts) Snapshots(
+	service string,
+	snaps []*types.Snapshot) ([]*types.Snapshot, *Cursor) {
+
+	if o == nil {
+		return snaps, nil
+	}
+	keys := make([]key, len(snaps))
+	for i, s := range snaps {
+		keys[i] = o.field.snapshot(s)
+		keys[i].ID = s.ID
+	}
+	indices, c := o.page(service, keys)
+
+	paged := make([]*types.Snapshot, len(indices))
+	for i, j := range indices {
+		paged[i] = snaps[j]
+	}
+	return paged, c
+}
+
+// ValidateVolumes returns an error if the sort field is not a volume field.
+func (o *Opts) ValidateVolumes() error {
+	if o != nil && o.field.volume == nil {
+		return utils.NewBadPageError("sort", o.sort, "invalid volume field")
+	}
+	return nil
+}
+
+// ValidateSnapshots returns an error if the sort field is not a snapshot
+// field.
+func (o *Opts) ValidateSnapshots() error {
+	if o != nil && o.field.snapshot == nil {
+		return utils.NewBadPageError("sort", o.sort, "invalid snapshot field")
+	}
+	return nil
+}
+
+// Next returns the token of the next page given the positions of the
+// services' next pages. An empty string is returned if there are no more
+// resources.
+func (o *Opts) Next(cursors map[string]*Cursor) string {
+	if o == nil {
+		return ""
+	}
+	t := &token{Sort: o.sort, Cursors: map[string]*Cursor{}}
+	for k, v := range cursors {
+		if v != nil {
+			t.Cursors[k] = v
+		}
+	}
+	if len(t.Cursors) == 0 {
+		return ""
+	}
+	buf, err := json.Marshal(t)
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+func (o *Opts) cursor(service string) *Cursor {
+	if o.token != nil {
+		if c, ok := o.token.Cursors[service]; ok && c != nil {
+			return c
+		}
+	}
+	return &Cursor{}
+}
+
+// page returns the indices of the keys in the page in order, and the
+// position of the next page.
+func (o *Opts) page(service string, keys []key) ([]int, *Cursor) {
+	indices := make([]int, len(keys))
+	for i := range indices {
+		indices[i] = i
+	}
+	sort.Sort(&sorter{o: o, keys: keys, indices: indices})
+
+	if after := o.cursor(service).After; after != nil {
+		start := sort.Search(len(indices), func(i int) bool {
+			return o.less(*after, keys[indices[i]])
+		})
+		indices = indices[start:]
+	}
+
+	if o.Limit == 0 || len(indices) <= o.Limit {
+		return indices, nil
+	}
+	indices = indices[:o.Limit]
+	last := keys[indices[len(indices)-1]]
+	return indices, &Cursor{After: &last}
+}
+
+func (o *Opts) less(a, b key) bool {
+	if o.desc {
+		a, b = b, a
+	}
+	if a.N != b.N {
+		return a.N < b.N
+	}
+	if a.S != b.S {
+		return a.S < b.S
+	}
+	return a.ID < b.ID
+}
+
+// sorter sorts the indices of keys.
+type sorter struct {
+	o       *Opts
+	keys    []key
+	indices []int
+}
+
+func (s *sorter) Len() int {
+	return len(s.indices)
+}
+
+func (s *sorter) Swap(i, j int) {
+	s.indices[i], s.indices[j] = s.indices[j], s.indices[i]
+}
+
+func (s *sorter) Less(i, j int) bool {
+	return s.o.less(s.keys[s.indices[i]], s.keys[s.indices[j]])
+}
+
+func decodeToken(s string) (*token, error) {
+	buf, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	t := &token{}
+	if err := json.Unmarshal(buf, t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
@@ -0,0 +1,191 @@
+package paging
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/api/utils"
+)
+
+func newVolumes() []*types.Volume {
+	var vols []*types.Volume
+	for i := 0; i < 5; i++ {
+		vols = append(vols, &types.Volume{
+			ID:   fmt.Sprintf("vol-%03d", i),
+			Name: fmt.Sprintf("Volume %d", i%2),
+			Size: int64(10 * (5 - i)),
+		})
+	}
+	return vols
+}
+
+func ids(vols []*types.Volume) []string {
+	var s []string
+	for _, v := range vols {
+		s = append(s, v.ID)
+	}
+	return s
+}
+
+func parse(t *testing.T, kv ...interface{}) *Opts {
+	store := utils.NewStore()
+	for i := 0; i < len(kv); i += 2 {
+		store.Set(kv[i].(string), kv[i+1])
+	}
+	o, err := ParseOpts(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return o
+}
+
+func TestParseOptsNone(t *testing.T) {
+	o, err := ParseOpts(utils.NewStore())
+	assert.NoError(t, err)
+	assert.Nil(t, o)
+
+	vols := newVolumes()
+	paged, c := o.Volumes("vfs", vols, nil)
+	assert.Equal(t, vols, paged)
+	assert.Nil(t, c)
+}
+
+func TestParseOptsInvalid(t *testing.T) {
+	for _, kv := range [][]interface{}{
+		{"limit", int64(-1)},
+		{"limit", true},
+		{"sort", "unknown"},
+		{"next", "not a token"},
+	} {
+		store := utils.NewStore()
+		store.Set(kv[0].(string), kv[1])
+		_, err := ParseOpts(store)
+		if assert.Error(t, err, "%v", kv) {
+			assert.IsType(t, &types.ErrBadPage{}, err)
+		}
+	}
+
+	o := parse(t, "sort", "volumeID")
+	assert.Error(t, o.ValidateVolumes())
+	assert.NoError(t, o.ValidateSnapshots())
+}
+
+func TestVolumesPages(t *testing.T) {
+	vols := newVolumes()
+
+	o := parse(t, "limit", int64(2), "sort", "-size")
+	paged, c := o.Volumes("vfs", vols, o.VolumesPage("vfs"))
+	assert.Equal(t, []string{"vol-000", "vol-001"}, ids(paged))
+	next := o.Next(map[string]*Cursor{"vfs": c})
+	assert.NotEmpty(t, next)
+
+	o = parse(t, "limit", int64(2), "next", next)
+	paged, c = o.Volumes("vfs", vols, o.VolumesPage("vfs"))
+	assert.Equal(t, []string{"vol-002", "vol-003"}, ids(paged))
+	next = o.Next(map[string]*Cursor{"vfs": c})
+
+	// a volume of the previous page that is removed does not shift the
+	// next page
+	o = parse(t, "limit", int64(2), "next", next)
+	paged, c = o.Volumes("vfs", vols[4:], o.VolumesPage("vfs"))
+	assert.Equal(t, []string{"vol-004"}, ids(paged))
+	assert.Nil(t, c)
+	assert.Empty(t, o.Next(map[string]*Cursor{"vfs": c}))
+}
+
+func TestVolumesPagesSortByNameThenID(t *testing.T) {
+	o := parse(t, "limit", int64(3), "sort", "name")
+	paged, _ := o.Volumes("vfs", newVolumes(), nil)
+	assert.Equal(t, []string{"vol-000", "vol-002", "vol-004"}, ids(paged))
+}
+
+func TestVolumesNativePages(t *testing.T) {
+	vols := newVolumes()
+
+	o := parse(t, "limit", int64(2))
+	page := o.VolumesPage("ebs")
+	if assert.NotNil(t, page) {
+		assert.Equal(t, 2, page.Limit)
+		assert.Empty(t, page.Token)
+	}
+
+	// the driver pages natively
+	page.Next = "driverToken"
+	page.Paged = true
+	paged, c := o.Volumes("ebs", vols[:2], page)
+	assert.Len(t, paged, 2)
+	next := o.Next(map[string]*Cursor{"ebs": c, "vfs": nil})
+
+	o = parse(t, "limit", int64(2), "next", next)
+	assert.True(t, o.Done("vfs"))
+	assert.False(t, o.Done("ebs"))
+	page = o.VolumesPage("ebs")
+	if assert.NotNil(t, page) {
+		assert.Equal(t, "driverToken", page.Token)
+	}
+
+	// a sorted page is never paged by the driver
+	o = parse(t, "limit", int64(2), "sort", "name")
+	assert.Nil(t, o.VolumesPage("ebs"))
+}
+
+func TestSnapshotsPages(t *testing.T) {
+	snaps := []*types.Snapshot{
+		{ID: "snap-2", StartTime: 1},
+		{ID: "snap-1", StartTime: 3},
+		{ID: "snap-0", StartTime: 2},
+	}
+
+	o := parse(t, "limit", int64(2), "sort", "startTime")
+	paged, c := o.Snapshots("vfs", snaps)
+	if assert.Len(t, paged, 2) {
+		assert.Equal(t, "snap-2", paged[0].ID)
+		assert.Equal(t, "snap-0", paged[1].ID)
+	}
+	next := o.Next(map[string]*Cursor{"vfs": c})
+
+	// the token's sort must match the requested sort
+	store := utils.NewStore()
+	store.Set("next", next)
+	store.Set("sort", "name")
+	_, err := ParseOpts(store)
+	assert.Error(t, err)
+
+	o = parse(t, "limit", int64(2), "next", next)
+	paged, c = o.Snapshots("vfs", snaps)
+	if assert.Len(t, paged, 1) {
+		assert.Equal(t, "snap-1", paged[0].ID)
+	}
+	assert.Nil(t, c)
+}
+
+func TestCursor(t *testing.T) {
+	var o *Opts
+	assert.Nil(t, o.Cursor("vfs"))
+
+	// a service that fails to return the first page returns it again
+	o = parse(t, "limit", int64(2))
+	c := o.Cursor("vfs")
+	if assert.NotNil(t, c) {
+		assert.Nil(t, c.After)
+	}
+	next := o.Next(map[string]*Cursor{"vfs": c})
+	assert.NotEmpty(t, next)
+	o = parse(t, "limit", int64(2), "next", next)
+	assert.False(t, o.Done("vfs"))
+
+	paged, c := o.Volumes("vfs", newVolumes(), nil)
+	assert.Equal(t, []string{"vol-000", "vol-001"}, ids(paged))
+	next = o.Next(map[string]*Cursor{"vfs": c, "ebs": o.Cursor("ebs")})
+
+	// a service whose resources have all been returned remains done
+	o = parse(t, "next", next)
+	assert.True(t, o.Done("ebs"))
+	assert.Nil(t, o.Cursor("ebs"))
+	if c := o.Cursor("vfs"); assert.NotNil(t, c) {
+		assert.NotNil(t, c.After)
+	}
+}
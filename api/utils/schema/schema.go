@@ -67,6 +67,14 @@ var (
 	// request.
 	VolumeSnapshotRequestSchema = buildSchemaVar("volumeSnapshotRequest")
 
+	// VolumeResizeRequestSchema is the JSON schema for a Volume resize
+	// request.
+	VolumeResizeRequestSchema = buildSchemaVar("volumeResizeRequest")
+
+	// VolumeUpdateRequestSchema is the JSON schema for a Volume update
+	// request.
+	VolumeUpdateRequestSchema = buildSchemaVar("volumeUpdateRequest")
+
 	// VolumeAttachRequestSchema is the JSON schema for a Volume attach
 	// request.
 	VolumeAttachRequestSchema = buildSchemaVar("volumeAttachRequest")
@@ -114,6 +114,10 @@ const (
                     "type": "boolean",
                     "description": "A flag indicating whether or not the instance ID has been formatted by an instance inspection."
                 },
+                "fields": {
+                    "type": "object",
+                    "description": "Additional information about the instance ID."
+                },
                 "metadata": {
                     "type": "object",
                     "description": "Extra information about the instance ID."
@@ -208,6 +212,15 @@ const (
                     "type": "string",
                     "description": "The name of the user that created the task."
                 },
+                "service": {
+                    "type": "string",
+                    "description": "The name of the service for which the task was created."
+                },
+                "state": {
+                    "type": "string",
+                    "enum": [ "queued", "running", "success", "error", "interrupted", "cancelled" ],
+                    "description": "The current state of the task."
+                },
                 "completeTime": {
                     "type": "number",
                     "description": "The time stamp (epoch) when the task was completed."
@@ -224,6 +237,11 @@ const (
                     "type": "object",
                     "description": "The result of the operation."
                 },
+                "next": {
+                    "type": "string",
+                    "description": "The token of the next page when the result is a page of a list of volumes or snapshots."
+                },
+                "serviceErrors": { "$ref": "#/definitions/serviceErrorMap" },
                 "error": {
                     "type": "object",
                     "description": "If the operation returned an error, this is it."
@@ -368,6 +386,32 @@ const (
         },
 
 
+        "serviceError": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "description": "The error's message."
+                },
+                "timedOut": {
+                    "type": "boolean",
+                    "description": "A flag indicating that the service did not respond before its list timeout expired."
+                }
+            },
+            "required": [ "message" ],
+            "additionalProperties": false
+        },
+
+
+        "serviceErrorMap": {
+            "type": "object",
+            "patternProperties": {
+                "^.+$": { "$ref": "#/definitions/serviceError" }
+            },
+            "additionalProperties": false
+        },
+
+
         "serviceTaskMap": {
             "type": "object",
             "patternProperties": {
@@ -448,6 +492,7 @@ const (
                 "type": {
                     "type": "string"
                 },
+                "fields": { "$ref": "#/definitions/fields" },
                 "opts": { "$ref" : "#/definitions/opts" }
             },
             "required": [ "name" ],
@@ -461,6 +506,7 @@ const (
                 "volumeName": {
                     "type": "string"
                 },
+                "fields": { "$ref": "#/definitions/fields" },
                 "opts": { "$ref" : "#/definitions/opts" }
             },
             "required": [ "volumeName" ],
@@ -481,6 +527,39 @@ const (
         },
 
 
+        "volumeResizeRequest": {
+            "type": "object",
+            "properties": {
+                "size": {
+                    "type": "number",
+                    "description": "The new size of the volume (GB)."
+                },
+                "opts": { "$ref" : "#/definitions/opts" }
+            },
+            "required": [ "size" ],
+            "additionalProperties": false
+        },
+
+
+        "volumeUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "description": "The new name of the volume."
+                },
+                "fields": { "$ref": "#/definitions/fields" },
+                "removeFields": {
+                    "type": "array",
+                    "description": "The names of the fields to remove.",
+                    "items": { "type": "string" }
+                },
+                "opts": { "$ref" : "#/definitions/opts" }
+            },
+            "additionalProperties": false
+        },
+
+
         "volumeAttachRequest": {
             "type": "object",
             "properties": {
@@ -490,6 +569,7 @@ const (
                 "force": {
                     "type": "boolean"
                 },
+                "instanceID": { "$ref": "#/definitions/instanceID" },
                 "opts": { "$ref" : "#/definitions/opts" }
             },
             "additionalProperties": false
@@ -513,6 +593,7 @@ const (
                 "force": {
                     "type": "boolean"
                 },
+                "instanceID": { "$ref": "#/definitions/instanceID" },
                 "opts": { "$ref" : "#/definitions/opts" }
             },
             "additionalProperties": false
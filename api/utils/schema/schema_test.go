@@ -246,6 +246,7 @@ func TestVolumeCreateRequestObject(t *testing.T) {
 		IOPS:             &iops,
 		Size:             &size,
 		Type:             &volType,
+		Fields:           map[string]string{"owner": "root@example.com"},
 		Opts: map[string]interface{}{
 			"priority": 2,
 			"owner":    "root@example.com",
@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"sort"
+
 	"github.com/akutz/goof"
 
 	"github.com/codedellemc/libstorage/api/types"
@@ -17,10 +19,11 @@ func NewUnsupportedForClientTypeError(
 		}, "unsupported op for client type")}
 }
 
-// NewBadAdminTokenError returns a new ErrBadAdminToken error.
-func NewBadAdminTokenError(token string) error {
+// NewBadAdminTokenError returns a new ErrBadAdminToken error. The presented
+// token is never included in the error.
+func NewBadAdminTokenError(reason string) error {
 	return &types.ErrBadAdminToken{
-		Goof: goof.WithField("token", token, "invalid admin token"),
+		Goof: goof.WithField("reason", reason, "invalid admin token"),
 	}
 }
 
@@ -64,3 +67,82 @@ func NewBadFilterErr(filter string, err error) error {
 	return &types.ErrBadFilter{Goof: goof.WithFieldE(
 		"filter", filter, "bad filter", err)}
 }
+
+// NewBadPageError returns a new ErrBadPage error.
+func NewBadPageError(param, value, reason string) error {
+	return &types.ErrBadPage{Goof: goof.WithFields(goof.Fields{
+		param:    value,
+		"reason": reason,
+	}, "bad page")}
+}
+
+// NewPartialResultError returns a new ErrPartialResult error.
+func NewPartialResultError(errs types.ServiceErrorMap) error {
+	services := []string{}
+	for k := range errs {
+		services = append(services, k)
+	}
+	sort.Strings(services)
+	return &types.ErrPartialResult{
+		Goof: goof.WithField(
+			"services", services, "services failed to respond"),
+		Errors: errs,
+	}
+}
+
+// NewUnauthorizedError returns a new ErrUnauthorized error.
+func NewUnauthorizedError(reason string) error {
+	return &types.ErrUnauthorized{
+		Goof: goof.WithField("reason", reason, "unauthorized"),
+	}
+}
+
+// NewForbiddenError returns a new ErrForbidden error.
+func NewForbiddenError(
+	user, service string, required, granted types.AuthRole) error {
+	return &types.ErrForbidden{Goof: goof.WithFields(goof.Fields{
+		"user":         user,
+		"service":      service,
+		"requiredRole": required.String(),
+		"grantedRole":  granted.String(),
+	}, "forbidden")}
+}
+
+// NewPolicyViolationError returns a new ErrPolicyViolation error.
+func NewPolicyViolationError(
+	service, msg string, fields goof.Fields) error {
+	if fields == nil {
+		fields = goof.Fields{}
+	}
+	fields["service"] = service
+	return &types.ErrPolicyViolation{Goof: goof.WithFields(fields, msg)}
+}
+
+// NewBadInstanceIDError returns a new ErrBadInstanceID error.
+func NewBadInstanceIDError(service, reason string) error {
+	return &types.ErrBadInstanceID{Goof: goof.WithFields(goof.Fields{
+		"service": service,
+		"reason":  reason,
+	}, "bad instance ID")}
+}
+
+// NewTaskCompletedError returns a new ErrTaskCompleted error.
+func NewTaskCompletedError(taskID int, state types.TaskState) error {
+	return &types.ErrTaskCompleted{Goof: goof.WithFields(goof.Fields{
+		"taskID": taskID,
+		"state":  state,
+	}, "task already completed")}
+}
+
+// NewLockTimeoutError returns a new ErrLockTimeout error. The PID and time
+// of the lock's current holder are included if they are known.
+func NewLockTimeoutError(path string, holderPID int, holderTime int64) error {
+	fields := goof.Fields{"path": path}
+	if holderPID > 0 {
+		fields["holderPID"] = holderPID
+		fields["holderTime"] = holderTime
+	}
+	return &types.ErrLockTimeout{
+		Goof: goof.WithFields(fields, "timed out waiting for lock"),
+	}
+}
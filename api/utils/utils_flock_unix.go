@@ -0,0 +1,40 @@
+// +build !windows
+
+package utils
+
+import (
+	"os"
+	"syscall"
+)
+
+// TryLockFile attempts to acquire an exclusive advisory lock on the file at
+// the specified path without blocking. The file is created if it does not
+// exist. The open file is returned if the lock is acquired, and nil is
+// returned if another process holds the lock. The lock is released by the
+// operating system if the process that holds it exits, so the file is not
+// removed when the lock is released.
+func TryLockFile(path string) (*os.File, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+	err = syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+	if err == syscall.EWOULDBLOCK {
+		f.Close()
+		return nil, nil
+	}
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+// UnlockFile releases a lock acquired with TryLockFile and closes the file.
+func UnlockFile(f *os.File) error {
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_UN); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
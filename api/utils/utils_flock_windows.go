@@ -0,0 +1,30 @@
+// +build windows
+
+package utils
+
+import "os"
+
+// TryLockFile attempts to acquire an exclusive lock on the file at the
+// specified path by exclusively creating the file. The open file is returned
+// if the lock is acquired, and nil is returned if the file exists. The file
+// is removed when the lock is released, so a lock held by a process that
+// exits without releasing it must be removed by the caller.
+func TryLockFile(path string) (*os.File, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_RDWR, 0644)
+	if err == nil {
+		return f, nil
+	}
+	if os.IsExist(err) {
+		return nil, nil
+	}
+	return nil, err
+}
+
+// UnlockFile releases a lock acquired with TryLockFile by closing and
+// removing the file.
+func UnlockFile(f *os.File) error {
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Remove(f.Name())
+}
@@ -57,9 +57,11 @@ func volumes(clientID C.h, attachments C.short) C.result {
 		return result
 	}
 
+	// the volumes of the services that responded are returned even when
+	// some of the services failed to respond
 	svcToVolMap, err := c.API().Volumes(
 		nil, types.VolumeAttachmentsTypes(attachments))
-	if err != nil {
+	if _, ok := err.(*types.ErrPartialResult); err != nil && !ok {
 		result.err = C.CString(err.Error())
 		return result
 	}
@@ -0,0 +1,11 @@
+// +build darwin
+
+package main
+
+import (
+	"github.com/codedellemc/libstorage/cli/lsc"
+)
+
+func main() {
+	lsc.Run()
+}
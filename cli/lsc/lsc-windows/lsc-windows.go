@@ -0,0 +1,11 @@
+// +build windows
+
+package main
+
+import (
+	"github.com/codedellemc/libstorage/cli/lsc"
+)
+
+func main() {
+	lsc.Run()
+}
@@ -0,0 +1,240 @@
+// +build gofig pflag
+
+package lsc
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	gofigCore "github.com/akutz/gofig"
+	gofig "github.com/akutz/gofig/types"
+
+	"github.com/akutz/gotil"
+	flag "github.com/spf13/pflag"
+
+	"github.com/codedellemc/libstorage/api"
+	apiclient "github.com/codedellemc/libstorage/api/client"
+	"github.com/codedellemc/libstorage/api/context"
+	apitypes "github.com/codedellemc/libstorage/api/types"
+	apiconfig "github.com/codedellemc/libstorage/api/utils/config"
+	"github.com/codedellemc/libstorage/client"
+
+	// load the configuration
+	_ "github.com/codedellemc/libstorage/imports/config"
+)
+
+var (
+	cliFlags       *flag.FlagSet
+	flagHost       *string
+	flagConfig     *string
+	flagLogLvl     *string
+	flagHelp       *bool
+	flagVersion    *bool
+	flagOutput     *string
+	flagFilter     *string
+	flagLimit      *int
+	flagSort       *string
+	flagNext       *string
+	flagAsync      *bool
+	flagClientType *string
+	flagToken      *string
+)
+
+func init() {
+	cliFlags = flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+	flagConfig = cliFlags.StringP("config", "c", "", "path")
+	flagHost = cliFlags.StringP("host", "h", "", "<proto>://<addr>")
+	flagLogLvl = cliFlags.StringP("log", "l", "", "error|warn|info|debug")
+	flagHelp = cliFlags.BoolP("help", "?", false, "print usage")
+	flagVersion = cliFlags.Bool("version", false, "print version info")
+	flagOutput = cliFlags.StringP("output", "o", "table", "table|json|yaml")
+	flagFilter = cliFlags.String("filter", "", "LDAP-style filter")
+	flagLimit = cliFlags.Int(
+		"limit", 0, "max resources listed per service or audit records")
+	flagSort = cliFlags.String("sort", "", "field by which lists are paged")
+	flagNext = cliFlags.String("next", "", "token of a list's next page")
+	flagAsync = cliFlags.Bool(
+		"async", false, "return the task rather than wait for it")
+	flagClientType = cliFlags.String(
+		"clientType", "", "integration|controller")
+	flagToken = cliFlags.String("token", "", "bearer token")
+	flag.CommandLine.AddFlagSet(cliFlags)
+	flag.CommandLine.AddFlagSet(cmdFlags)
+}
+
+// Run runs the client CLI.
+func Run() {
+	flag.Usage = printUsage
+	flag.Parse()
+
+	if flagVersion != nil && *flagVersion {
+		_, _, thisExeAbsPath := gotil.GetThisPathParts()
+		fmt.Fprintf(os.Stdout, "Binary: %s\n", thisExeAbsPath)
+		fmt.Fprint(os.Stdout, api.Version.String())
+		os.Exit(0)
+	}
+
+	if flagHelp != nil && *flagHelp {
+		flag.Usage()
+	}
+
+	cmd, args := findCommand(flag.Args())
+	if cmd == nil {
+		flag.Usage()
+	}
+	if !cmd.validArgs(args) {
+		fmt.Fprintf(os.Stderr,
+			"usage: %s %s %s\n", os.Args[0], cmd.name, cmd.args)
+		os.Exit(1)
+	}
+
+	printer, err := newPrinter(*flagOutput, os.Stdout)
+	if err != nil {
+		exitWithError(err)
+	}
+
+	config, err := loadConfig()
+	if err != nil {
+		exitWithError(err)
+	}
+
+	c, err := client.New(nil, config)
+	if err != nil {
+		exitWithError(err)
+	}
+
+	ctx := context.Background()
+	if *flagFilter != "" {
+		ctx = apiclient.WithFilter(ctx, *flagFilter)
+	}
+
+	var page *apitypes.Page
+	if *flagLimit > 0 || *flagSort != "" || *flagNext != "" {
+		page = &apitypes.Page{
+			Limit: *flagLimit,
+			Sort:  *flagSort,
+			Token: *flagNext,
+		}
+		ctx = apiclient.WithPage(ctx, page)
+	}
+
+	var task *apitypes.Task
+	if *flagAsync && cmd.async {
+		ctx, task = apiclient.WithAsync(ctx)
+	}
+
+	result, err := cmd.run(ctx, c.API(), args, printer)
+
+	// the resources of the services that responded are printed before the
+	// errors of the services that did not
+	partial, isPartial := err.(*apitypes.ErrPartialResult)
+	if err != nil && !isPartial {
+		exitWithError(err)
+	}
+
+	// an asynchronous request's reply is the queued task
+	if task != nil && task.ID > 0 {
+		result = task
+	}
+
+	if result != nil {
+		if err := printer.print(result); err != nil {
+			exitWithError(err)
+		}
+	}
+
+	// the token of the next page is printed to stderr so that the output
+	// of a list may still be parsed
+	if page != nil && page.Next != "" {
+		fmt.Fprintf(os.Stderr, "next: %s\n", page.Next)
+	}
+
+	if isPartial {
+		exitWithServiceErrors(partial.Errors)
+	}
+}
+
+// loadConfig loads the configuration from the specified file or from the
+// default locations and updates it with the global flags.
+func loadConfig() (gofig.Config, error) {
+	var config gofig.Config
+	if *flagConfig != "" {
+		config = gofigCore.New()
+		if err := config.ReadConfigFile(*flagConfig); err != nil {
+			return nil, err
+		}
+	} else {
+		var err error
+		if config, err = apiconfig.NewConfig(); err != nil {
+			return nil, err
+		}
+	}
+
+	if *flagHost != "" {
+		config.Set(apitypes.ConfigHost, *flagHost)
+	}
+	if *flagClientType != "" {
+		config.Set(apitypes.ConfigClientType, *flagClientType)
+	}
+	if *flagToken != "" {
+		config.Set(apitypes.ConfigClientAuthToken, *flagToken)
+	}
+	if *flagLogLvl != "" {
+		config.Set(apitypes.ConfigLogLevel, *flagLogLvl)
+	}
+
+	apiconfig.UpdateLogLevel(config)
+	return config, nil
+}
+
+func exitWithError(err error) {
+	fmt.Fprintf(os.Stderr, "%s: error: %v\n", os.Args[0], err)
+	os.Exit(1)
+}
+
+func exitWithServiceErrors(errs apitypes.ServiceErrorMap) {
+	services := []string{}
+	for k := range errs {
+		services = append(services, k)
+	}
+	sort.Strings(services)
+	for _, k := range services {
+		fmt.Fprintf(os.Stderr, "%s: error: %s: %s\n",
+			os.Args[0], k, errs[k].Message)
+	}
+	os.Exit(1)
+}
+
+func printUsage() {
+	fmt.Fprintf(os.Stderr, "usage: %s [-options] <command> [<args>]\n\n",
+		os.Args[0])
+
+	fmt.Fprintln(os.Stderr, "  Commands")
+	fmt.Fprintln(os.Stderr)
+	for _, cmd := range commands {
+		usage := strings.TrimSpace(cmd.name + " " + cmd.args)
+		fmt.Fprintf(os.Stderr, "    %-44s %s\n", usage, cmd.desc)
+	}
+	fmt.Fprintln(os.Stderr)
+
+	fmt.Fprintln(os.Stderr, "  Global Options")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, cliFlags.FlagUsages())
+	fmt.Fprintln(os.Stderr, "  Command Options")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, cmdFlags.FlagUsages())
+	fmt.Fprintln(os.Stderr, asyncUsage)
+
+	os.Exit(1)
+}
+
+const asyncUsage = `  Asynchronous Operations
+
+    The --async flag causes the commands that create, copy, remove, resize,
+    update, attach, detach, or snapshot a resource to print the server's
+    task as soon as it is queued. The task is then waited on with the
+    command "task wait <taskID>", which prints the completed task and exits
+    with a non-zero status if the task did not succeed.
+`
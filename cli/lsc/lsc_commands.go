@@ -0,0 +1,691 @@
+// +build gofig pflag
+
+package lsc
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/akutz/goof"
+	flag "github.com/spf13/pflag"
+
+	apitypes "github.com/codedellemc/libstorage/api/types"
+)
+
+// taskPollInterval is the interval at which a task is inspected while it is
+// waited on.
+const taskPollInterval = 250 * time.Millisecond
+
+type runFunc func(
+	ctx apitypes.Context,
+	c apitypes.APIClient,
+	args []string,
+	p *printer) (interface{}, error)
+
+type command struct {
+	name    string
+	args    string
+	desc    string
+	minArgs int
+	maxArgs int
+
+	// async indicates the command's route executes a task that may be
+	// returned as soon as it is queued.
+	async bool
+
+	run runFunc
+}
+
+// validArgs returns a flag indicating whether the number of arguments is
+// valid for the command. A negative maxArgs means there is no maximum.
+func (c *command) validArgs(args []string) bool {
+	if len(args) < c.minArgs {
+		return false
+	}
+	return c.maxArgs < 0 || len(args) <= c.maxArgs
+}
+
+var (
+	cmdFlags = flag.NewFlagSet("commands", flag.ExitOnError)
+
+	flagAttachments = cmdFlags.String(
+		"attachments", "", "the attachments to return with volumes")
+	flagSize = cmdFlags.Int64("size", 0, "the volume size in GB")
+	flagType = cmdFlags.String("type", "", "the volume type")
+	flagIOPS = cmdFlags.Int64("iops", 0, "the volume IOPS")
+	flagAZ   = cmdFlags.String(
+		"availabilityZone", "", "the volume availability zone")
+	flagEncrypted     = cmdFlags.Bool("encrypted", false, "encrypt the volume")
+	flagEncryptionKey = cmdFlags.String(
+		"encryptionKey", "", "the volume encryption key")
+	flagSnapshot = cmdFlags.String(
+		"snapshot", "", "the ID of the snapshot from which to create a volume")
+	flagName   = cmdFlags.String("name", "", "the volume's new name")
+	flagFields = cmdFlags.StringSlice(
+		"field", nil, "<key>=<value> field to set on a volume")
+	flagRemoveFields = cmdFlags.StringSlice(
+		"removeField", nil, "the key of a field to remove from a volume")
+	flagForce      = cmdFlags.Bool("force", false, "force the operation")
+	flagNextDevice = cmdFlags.String(
+		"nextDevice", "", "the next available device name")
+	flagInstance = cmdFlags.String(
+		"instance", "", "the ID of the instance to attach to or detach from")
+	flagDestination = cmdFlags.String(
+		"destination", "", "the destination ID of a snapshot copy")
+	flagOpts = cmdFlags.StringSlice(
+		"opt", nil, "<key>=<value> option passed to the storage driver")
+	flagState   = cmdFlags.String("state", "", "the task state")
+	flagUser    = cmdFlags.String("user", "", "the task user")
+	flagService = cmdFlags.String("service", "", "the task or record service")
+	flagSince   = cmdFlags.Int64(
+		"since", 0, "the epoch at or after which tasks or records occurred")
+	flagUntil = cmdFlags.Int64(
+		"until", 0, "the epoch at or before which tasks or records occurred")
+	flagTimeout = cmdFlags.Duration(
+		"timeout", 0, "the maximum amount of time to wait for a task")
+)
+
+// commands are the CLI's commands. A command's name is one or two words.
+var commands = []*command{
+	{"root", "", "list the root resources",
+		0, 0, false, root},
+
+	{"service ls", "", "list services",
+		0, 0, false, serviceList},
+	{"service inspect", "<service>", "inspect a service",
+		1, 1, false, serviceInspect},
+	{"service reload", "", "reload the server's services",
+		0, 0, false, serviceReload},
+
+	{"instance ls", "", "list the instances of all services",
+		0, 0, false, instanceList},
+	{"instance inspect", "<service>", "inspect the instance of a service",
+		1, 1, false, instanceInspect},
+
+	{"volume ls", "[<service>]", "list volumes",
+		0, 1, false, volumeList},
+	{"volume inspect", "<service> <volumeID>", "inspect a volume",
+		2, 2, false, volumeInspect},
+	{"volume create", "<service> <name>", "create a volume",
+		2, 2, true, volumeCreate},
+	{"volume copy", "<service> <volumeID> <name>", "copy a volume",
+		3, 3, true, volumeCopy},
+	{"volume rm", "<service> <volumeID>", "remove a volume",
+		2, 2, true, volumeRemove},
+	{"volume resize", "<service> <volumeID> <size>", "resize a volume",
+		3, 3, true, volumeResize},
+	{"volume update", "<service> <volumeID>",
+		"rename a volume or update its fields",
+		2, 2, true, volumeUpdate},
+	{"volume attach", "<service> <volumeID>", "attach a volume",
+		2, 2, true, volumeAttach},
+	{"volume detach", "[<service> [<volumeID>]]",
+		"detach a volume, or all of a service's volumes, or all volumes",
+		0, 2, true, volumeDetach},
+	{"volume snapshot", "<service> <volumeID> <name>", "snapshot a volume",
+		3, 3, true, volumeSnapshot},
+
+	{"snapshot ls", "[<service>]", "list snapshots",
+		0, 1, false, snapshotList},
+	{"snapshot inspect", "<service> <snapshotID>", "inspect a snapshot",
+		2, 2, false, snapshotInspect},
+	{"snapshot copy", "<service> <snapshotID> <name>", "copy a snapshot",
+		3, 3, true, snapshotCopy},
+	{"snapshot rm", "<service> <snapshotID>", "remove a snapshot",
+		2, 2, true, snapshotRemove},
+
+	{"task ls", "", "list tasks",
+		0, 0, false, taskList},
+	{"task inspect", "<taskID>", "inspect a task",
+		1, 1, false, taskInspect},
+	{"task cancel", "<taskID>", "cancel a queued task",
+		1, 1, false, taskCancel},
+	{"task wait", "<taskID>", "wait for a task to complete",
+		1, 1, false, taskWait},
+
+	{"events", "[<service>...]", "print the server's events as they occur",
+		0, -1, false, events},
+	{"audit", "", "list the server's audit records",
+		0, 0, false, audit},
+
+	{"executor ls", "", "list executors",
+		0, 0, false, executorList},
+	{"executor inspect", "<executor>", "inspect an executor",
+		1, 1, false, executorInspect},
+}
+
+// findCommand returns the command named by the first one or two arguments
+// and the command's arguments.
+func findCommand(args []string) (*command, []string) {
+	for _, n := range []int{2, 1} {
+		if len(args) < n {
+			continue
+		}
+		name := strings.ToLower(strings.Join(args[:n], " "))
+		for _, cmd := range commands {
+			if cmd.name == name {
+				return cmd, args[n:]
+			}
+		}
+	}
+	return nil, nil
+}
+
+func root(
+	ctx apitypes.Context,
+	c apitypes.APIClient,
+	args []string,
+	p *printer) (interface{}, error) {
+
+	return c.Root(ctx)
+}
+
+func serviceList(
+	ctx apitypes.Context,
+	c apitypes.APIClient,
+	args []string,
+	p *printer) (interface{}, error) {
+
+	return c.Services(ctx)
+}
+
+func serviceInspect(
+	ctx apitypes.Context,
+	c apitypes.APIClient,
+	args []string,
+	p *printer) (interface{}, error) {
+
+	return c.ServiceInspect(ctx, args[0])
+}
+
+func serviceReload(
+	ctx apitypes.Context,
+	c apitypes.APIClient,
+	args []string,
+	p *printer) (interface{}, error) {
+
+	return c.ServicesReload(ctx)
+}
+
+func instanceList(
+	ctx apitypes.Context,
+	c apitypes.APIClient,
+	args []string,
+	p *printer) (interface{}, error) {
+
+	return c.Instances(ctx)
+}
+
+func instanceInspect(
+	ctx apitypes.Context,
+	c apitypes.APIClient,
+	args []string,
+	p *printer) (interface{}, error) {
+
+	i, err := c.InstanceInspect(ctx, args[0])
+	if err != nil {
+		return nil, err
+	}
+	return map[string]*apitypes.Instance{args[0]: i}, nil
+}
+
+func volumeList(
+	ctx apitypes.Context,
+	c apitypes.APIClient,
+	args []string,
+	p *printer) (interface{}, error) {
+
+	attachments := apitypes.ParseVolumeAttachmentTypes(*flagAttachments)
+	if len(args) == 0 {
+		return c.Volumes(ctx, attachments)
+	}
+	vols, err := c.VolumesByService(ctx, args[0], attachments)
+	if err != nil {
+		return nil, err
+	}
+	return apitypes.ServiceVolumeMap{args[0]: vols}, nil
+}
+
+func volumeInspect(
+	ctx apitypes.Context,
+	c apitypes.APIClient,
+	args []string,
+	p *printer) (interface{}, error) {
+
+	attachments := apitypes.ParseVolumeAttachmentTypes(*flagAttachments)
+	return c.VolumeInspect(ctx, args[0], args[1], attachments)
+}
+
+func volumeCreate(
+	ctx apitypes.Context,
+	c apitypes.APIClient,
+	args []string,
+	p *printer) (interface{}, error) {
+
+	fields, err := parseFields(*flagFields)
+	if err != nil {
+		return nil, err
+	}
+	opts, err := parseKeyValues(*flagOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &apitypes.VolumeCreateRequest{
+		Name:   args[1],
+		Fields: fields,
+		Opts:   opts,
+	}
+	if *flagSize > 0 {
+		req.Size = flagSize
+	}
+	if *flagIOPS > 0 {
+		req.IOPS = flagIOPS
+	}
+	if *flagType != "" {
+		req.Type = flagType
+	}
+	if *flagAZ != "" {
+		req.AvailabilityZone = flagAZ
+	}
+	if *flagEncrypted {
+		req.Encrypted = flagEncrypted
+	}
+	if *flagEncryptionKey != "" {
+		req.EncryptionKey = flagEncryptionKey
+	}
+
+	if *flagSnapshot != "" {
+		return c.VolumeCreateFromSnapshot(ctx, args[0], *flagSnapshot, req)
+	}
+	return c.VolumeCreate(ctx, args[0], req)
+}
+
+func volumeCopy(
+	ctx apitypes.Context,
+	c apitypes.APIClient,
+	args []string,
+	p *printer) (interface{}, error) {
+
+	fields, err := parseFields(*flagFields)
+	if err != nil {
+		return nil, err
+	}
+	opts, err := parseKeyValues(*flagOpts)
+	if err != nil {
+		return nil, err
+	}
+	return c.VolumeCopy(ctx, args[0], args[1], &apitypes.VolumeCopyRequest{
+		VolumeName: args[2],
+		Fields:     fields,
+		Opts:       opts,
+	})
+}
+
+func volumeRemove(
+	ctx apitypes.Context,
+	c apitypes.APIClient,
+	args []string,
+	p *printer) (interface{}, error) {
+
+	return nil, c.VolumeRemove(ctx, args[0], args[1])
+}
+
+func volumeResize(
+	ctx apitypes.Context,
+	c apitypes.APIClient,
+	args []string,
+	p *printer) (interface{}, error) {
+
+	size, err := strconv.ParseInt(args[2], 10, 64)
+	if err != nil {
+		return nil, goof.WithFieldE("size", args[2], "invalid size", err)
+	}
+	opts, err := parseKeyValues(*flagOpts)
+	if err != nil {
+		return nil, err
+	}
+	return c.VolumeResize(ctx, args[0], args[1], &apitypes.VolumeResizeRequest{
+		Size: size,
+		Opts: opts,
+	})
+}
+
+func volumeUpdate(
+	ctx apitypes.Context,
+	c apitypes.APIClient,
+	args []string,
+	p *printer) (interface{}, error) {
+
+	fields, err := parseFields(*flagFields)
+	if err != nil {
+		return nil, err
+	}
+	opts, err := parseKeyValues(*flagOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &apitypes.VolumeUpdateRequest{
+		Fields:       fields,
+		RemoveFields: *flagRemoveFields,
+		Opts:         opts,
+	}
+	if *flagName != "" {
+		req.Name = flagName
+	}
+	return c.VolumeUpdate(ctx, args[0], args[1], req)
+}
+
+func volumeAttach(
+	ctx apitypes.Context,
+	c apitypes.APIClient,
+	args []string,
+	p *printer) (interface{}, error) {
+
+	opts, err := parseKeyValues(*flagOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &apitypes.VolumeAttachRequest{
+		Force: *flagForce,
+		Opts:  opts,
+	}
+	if *flagNextDevice != "" {
+		req.NextDeviceName = flagNextDevice
+	}
+	req.InstanceID = targetInstance()
+
+	vol, _, err := c.VolumeAttach(ctx, args[0], args[1], req)
+	return vol, err
+}
+
+func volumeDetach(
+	ctx apitypes.Context,
+	c apitypes.APIClient,
+	args []string,
+	p *printer) (interface{}, error) {
+
+	opts, err := parseKeyValues(*flagOpts)
+	if err != nil {
+		return nil, err
+	}
+	req := &apitypes.VolumeDetachRequest{
+		Force:      *flagForce,
+		InstanceID: targetInstance(),
+		Opts:       opts,
+	}
+
+	switch len(args) {
+	case 0:
+		return c.VolumeDetachAll(ctx, req)
+	case 1:
+		vols, err := c.VolumeDetachAllForService(ctx, args[0], req)
+		if err != nil {
+			return nil, err
+		}
+		return apitypes.ServiceVolumeMap{args[0]: vols}, nil
+	default:
+		return c.VolumeDetach(ctx, args[0], args[1], req)
+	}
+}
+
+// targetInstance returns the instance ID specified with the --instance flag.
+// The server sets the instance ID's driver to the driver of the service.
+func targetInstance() *apitypes.InstanceID {
+	if *flagInstance == "" {
+		return nil
+	}
+	return &apitypes.InstanceID{ID: *flagInstance}
+}
+
+func volumeSnapshot(
+	ctx apitypes.Context,
+	c apitypes.APIClient,
+	args []string,
+	p *printer) (interface{}, error) {
+
+	opts, err := parseKeyValues(*flagOpts)
+	if err != nil {
+		return nil, err
+	}
+	return c.VolumeSnapshot(
+		ctx, args[0], args[1], &apitypes.VolumeSnapshotRequest{
+			SnapshotName: args[2],
+			Opts:         opts,
+		})
+}
+
+func snapshotList(
+	ctx apitypes.Context,
+	c apitypes.APIClient,
+	args []string,
+	p *printer) (interface{}, error) {
+
+	if len(args) == 0 {
+		return c.Snapshots(ctx)
+	}
+	snaps, err := c.SnapshotsByService(ctx, args[0])
+	if err != nil {
+		return nil, err
+	}
+	return apitypes.ServiceSnapshotMap{args[0]: snaps}, nil
+}
+
+func snapshotInspect(
+	ctx apitypes.Context,
+	c apitypes.APIClient,
+	args []string,
+	p *printer) (interface{}, error) {
+
+	return c.SnapshotInspect(ctx, args[0], args[1])
+}
+
+func snapshotCopy(
+	ctx apitypes.Context,
+	c apitypes.APIClient,
+	args []string,
+	p *printer) (interface{}, error) {
+
+	opts, err := parseKeyValues(*flagOpts)
+	if err != nil {
+		return nil, err
+	}
+	return c.SnapshotCopy(
+		ctx, args[0], args[1], &apitypes.SnapshotCopyRequest{
+			SnapshotName:  args[2],
+			DestinationID: *flagDestination,
+			Opts:          opts,
+		})
+}
+
+func snapshotRemove(
+	ctx apitypes.Context,
+	c apitypes.APIClient,
+	args []string,
+	p *printer) (interface{}, error) {
+
+	return nil, c.SnapshotRemove(ctx, args[0], args[1])
+}
+
+func taskList(
+	ctx apitypes.Context,
+	c apitypes.APIClient,
+	args []string,
+	p *printer) (interface{}, error) {
+
+	return c.Tasks(ctx, &apitypes.TasksOpts{
+		State:   apitypes.TaskState(*flagState),
+		User:    *flagUser,
+		Service: *flagService,
+		Since:   *flagSince,
+		Until:   *flagUntil,
+	})
+}
+
+func taskInspect(
+	ctx apitypes.Context,
+	c apitypes.APIClient,
+	args []string,
+	p *printer) (interface{}, error) {
+
+	id, err := parseTaskID(args[0])
+	if err != nil {
+		return nil, err
+	}
+	return c.TaskInspect(ctx, id)
+}
+
+func taskCancel(
+	ctx apitypes.Context,
+	c apitypes.APIClient,
+	args []string,
+	p *printer) (interface{}, error) {
+
+	id, err := parseTaskID(args[0])
+	if err != nil {
+		return nil, err
+	}
+	return c.TaskCancel(ctx, id)
+}
+
+// taskWait waits for a task to complete. The completed task is printed, and
+// an error is returned if the task did not succeed.
+func taskWait(
+	ctx apitypes.Context,
+	c apitypes.APIClient,
+	args []string,
+	p *printer) (interface{}, error) {
+
+	id, err := parseTaskID(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	var timeout <-chan time.Time
+	if *flagTimeout > 0 {
+		timeout = time.After(*flagTimeout)
+	}
+
+	for {
+		task, err := c.TaskInspect(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		switch task.State {
+		case apitypes.TaskStateSuccess:
+			return task, nil
+		case apitypes.TaskStateError,
+			apitypes.TaskStateInterrupted,
+			apitypes.TaskStateCancelled:
+			if err := p.print(task); err != nil {
+				return nil, err
+			}
+			return nil, goof.WithFields(goof.Fields{
+				"taskID": task.ID,
+				"state":  task.State,
+			}, "task did not succeed")
+		}
+
+		select {
+		case <-timeout:
+			return nil, goof.WithField(
+				"taskID", id, "timed out waiting for task")
+		case <-time.After(taskPollInterval):
+		}
+	}
+}
+
+// events prints the server's events as they are received until the
+// connection to the server is lost.
+func events(
+	ctx apitypes.Context,
+	c apitypes.APIClient,
+	args []string,
+	p *printer) (interface{}, error) {
+
+	evs, err := c.Events(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	p.stream = true
+	for ev := range evs {
+		if err := p.print(ev); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+func audit(
+	ctx apitypes.Context,
+	c apitypes.APIClient,
+	args []string,
+	p *printer) (interface{}, error) {
+
+	return c.Audit(ctx, &apitypes.AuditOpts{
+		Service: *flagService,
+		Since:   *flagSince,
+		Until:   *flagUntil,
+		Limit:   *flagLimit,
+	})
+}
+
+func executorList(
+	ctx apitypes.Context,
+	c apitypes.APIClient,
+	args []string,
+	p *printer) (interface{}, error) {
+
+	return c.Executors(ctx)
+}
+
+func executorInspect(
+	ctx apitypes.Context,
+	c apitypes.APIClient,
+	args []string,
+	p *printer) (interface{}, error) {
+
+	return c.ExecutorHead(ctx, args[0])
+}
+
+func parseTaskID(s string) (int, error) {
+	id, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, goof.WithFieldE("taskID", s, "invalid task ID", err)
+	}
+	return id, nil
+}
+
+// parseKeyValues parses a list of <key>=<value> pairs.
+func parseKeyValues(pairs []string) (map[string]interface{}, error) {
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+	m := map[string]interface{}{}
+	for _, kv := range pairs {
+		p := strings.SplitN(kv, "=", 2)
+		if len(p) != 2 || p[0] == "" {
+			return nil, goof.WithField("pair", kv, "invalid <key>=<value>")
+		}
+		m[p[0]] = p[1]
+	}
+	return m, nil
+}
+
+// parseFields parses <key>=<value> pairs into a volume's fields.
+func parseFields(pairs []string) (map[string]string, error) {
+	kvs, err := parseKeyValues(pairs)
+	if err != nil || kvs == nil {
+		return nil, err
+	}
+	fields := map[string]string{}
+	for k, v := range kvs {
+		fields[k] = v.(string)
+	}
+	return fields, nil
+}
@@ -0,0 +1,15 @@
+// +build !gofig !pflag
+
+package lsc
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+)
+
+// Run the client.
+func Run() {
+	fmt.Fprintf(os.Stderr, "lsc-%s was built without gofig\n", runtime.GOOS)
+	os.Exit(1)
+}
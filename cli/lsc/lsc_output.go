@@ -0,0 +1,315 @@
+// +build gofig pflag
+
+package lsc
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/akutz/goof"
+	"gopkg.in/yaml.v2"
+
+	apitypes "github.com/codedellemc/libstorage/api/types"
+)
+
+// printer prints the results of the commands as a table, JSON, or YAML.
+type printer struct {
+	format string
+	w      io.Writer
+
+	// stream indicates that a series of results are printed as they are
+	// received, such as events. Streamed JSON results are printed one per
+	// line, and a streamed table's header is printed only once.
+	stream  bool
+	started bool
+}
+
+func newPrinter(format string, w io.Writer) (*printer, error) {
+	format = strings.ToLower(format)
+	switch format {
+	case "table", "json", "yaml":
+		return &printer{format: format, w: w}, nil
+	}
+	return nil, goof.WithField("format", format, "invalid output format")
+}
+
+func (p *printer) print(v interface{}) error {
+	defer func() { p.started = true }()
+
+	switch p.format {
+	case "json":
+		if p.stream {
+			return json.NewEncoder(p.w).Encode(v)
+		}
+		buf, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(p.w, string(buf))
+		return err
+	case "yaml":
+		return p.printYAML(v)
+	}
+
+	t, ok := newTable(v)
+	if !ok {
+		return p.printYAML(v)
+	}
+
+	tw := tabwriter.NewWriter(p.w, 0, 8, 2, ' ', 0)
+	if !p.stream || !p.started {
+		fmt.Fprintln(tw, strings.Join(t.header, "\t"))
+	}
+	for _, row := range t.rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+func (p *printer) printYAML(v interface{}) error {
+	buf, err := yaml.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if p.stream && p.started {
+		fmt.Fprintln(p.w, "---")
+	}
+	_, err = p.w.Write(buf)
+	return err
+}
+
+type table struct {
+	header []string
+	rows   [][]string
+}
+
+func (t *table) add(row ...string) {
+	t.rows = append(t.rows, row)
+}
+
+var (
+	serviceHeader  = []string{"NAME", "DRIVER", "TYPE"}
+	instanceHeader = []string{"SERVICE", "ID", "DRIVER", "NAME", "REGION"}
+	volumeHeader   = []string{
+		"SERVICE", "ID", "NAME", "SIZE", "TYPE", "AZ", "STATUS", "ATTACHMENT"}
+	snapshotHeader = []string{
+		"SERVICE", "ID", "NAME", "VOLUME", "SIZE", "STATUS", "STARTED"}
+	taskHeader = []string{
+		"ID", "STATE", "SERVICE", "USER", "QUEUED", "COMPLETED", "ERROR"}
+	auditHeader = []string{
+		"TIME", "USER", "SERVICE", "OPERATION", "STATUS", "VOLUME",
+		"SNAPSHOT", "ERROR"}
+	eventHeader = []string{
+		"TIME", "TYPE", "SERVICE", "TASK", "VOLUME", "SNAPSHOT"}
+	executorHeader = []string{"NAME", "SIZE", "MD5", "MODIFIED"}
+)
+
+// newTable returns the table for a result. A result for which there is no
+// table is printed as YAML.
+func newTable(v interface{}) (*table, bool) {
+	switch tv := v.(type) {
+	case []string:
+		t := &table{header: []string{"RESOURCE"}}
+		for _, s := range tv {
+			t.add(s)
+		}
+		return t, true
+
+	case map[string]*apitypes.ServiceInfo:
+		t := &table{header: serviceHeader}
+		for _, k := range sortedKeys(tv) {
+			t.add(serviceRow(tv[k])...)
+		}
+		return t, true
+	case *apitypes.ServiceInfo:
+		t := &table{header: serviceHeader}
+		t.add(serviceRow(tv)...)
+		return t, true
+
+	case map[string]*apitypes.Instance:
+		t := &table{header: instanceHeader}
+		for _, k := range sortedKeys(tv) {
+			t.add(instanceRow(k, tv[k])...)
+		}
+		return t, true
+
+	case apitypes.ServiceVolumeMap:
+		t := &table{header: volumeHeader}
+		for _, svc := range sortedKeys(tv) {
+			for _, id := range sortedKeys(tv[svc]) {
+				t.add(volumeRow(svc, tv[svc][id])...)
+			}
+		}
+		return t, true
+	case *apitypes.Volume:
+		t := &table{header: volumeHeader[1:]}
+		t.add(volumeRow("", tv)[1:]...)
+		return t, true
+
+	case apitypes.ServiceSnapshotMap:
+		t := &table{header: snapshotHeader}
+		for _, svc := range sortedKeys(tv) {
+			for _, id := range sortedKeys(tv[svc]) {
+				t.add(snapshotRow(svc, tv[svc][id])...)
+			}
+		}
+		return t, true
+	case *apitypes.Snapshot:
+		t := &table{header: snapshotHeader[1:]}
+		t.add(snapshotRow("", tv)[1:]...)
+		return t, true
+
+	case map[string]*apitypes.Task:
+		t := &table{header: taskHeader}
+		ids := []int{}
+		for _, task := range tv {
+			ids = append(ids, task.ID)
+		}
+		sort.Ints(ids)
+		for _, id := range ids {
+			t.add(taskRow(tv[strconv.Itoa(id)])...)
+		}
+		return t, true
+	case *apitypes.Task:
+		t := &table{header: taskHeader}
+		t.add(taskRow(tv)...)
+		return t, true
+
+	case []*apitypes.AuditRecord:
+		t := &table{header: auditHeader}
+		for _, r := range tv {
+			t.add(formatTime(r.Time), r.User, r.Service, r.Operation,
+				strconv.Itoa(r.Status), r.VolumeID, r.SnapshotID, r.Error)
+		}
+		return t, true
+
+	case *apitypes.Event:
+		t := &table{header: eventHeader}
+		var taskID string
+		if tv.Task != nil {
+			taskID = strconv.Itoa(tv.Task.ID)
+		}
+		t.add(formatTime(tv.Time), string(tv.Type), tv.Service, taskID,
+			tv.VolumeID, tv.SnapshotID)
+		return t, true
+
+	case map[string]*apitypes.ExecutorInfo:
+		t := &table{header: executorHeader}
+		for _, k := range sortedKeys(tv) {
+			t.add(executorRow(tv[k])...)
+		}
+		return t, true
+	case *apitypes.ExecutorInfo:
+		t := &table{header: executorHeader}
+		t.add(executorRow(tv)...)
+		return t, true
+	}
+
+	return nil, false
+}
+
+func serviceRow(si *apitypes.ServiceInfo) []string {
+	var driver, storageType string
+	if si.Driver != nil {
+		driver = si.Driver.Name
+		storageType = string(si.Driver.Type)
+	}
+	return []string{si.Name, driver, storageType}
+}
+
+func instanceRow(service string, i *apitypes.Instance) []string {
+	var id, driver string
+	if i.InstanceID != nil {
+		id = i.InstanceID.ID
+		driver = i.InstanceID.Driver
+	}
+	return []string{service, id, driver, i.Name, i.Region}
+}
+
+func volumeRow(service string, v *apitypes.Volume) []string {
+	var attachment string
+	if v.AttachmentState > 0 {
+		attachment = v.AttachmentState.String()
+	}
+	return []string{
+		service, v.ID, v.Name, strconv.FormatInt(v.Size, 10), v.Type,
+		v.AvailabilityZone, v.Status, attachment,
+	}
+}
+
+func snapshotRow(service string, s *apitypes.Snapshot) []string {
+	return []string{
+		service, s.ID, s.Name, s.VolumeID,
+		strconv.FormatInt(s.VolumeSize, 10), s.Status,
+		formatTime(s.StartTime),
+	}
+}
+
+func taskRow(t *apitypes.Task) []string {
+	var errMsg string
+	if t.Error != nil {
+		errMsg = t.Error.Error()
+	}
+	return []string{
+		strconv.Itoa(t.ID), string(t.State), t.Service, t.User,
+		formatTime(t.QueueTime), formatTime(t.CompleteTime), errMsg,
+	}
+}
+
+func executorRow(e *apitypes.ExecutorInfo) []string {
+	return []string{
+		e.Name, strconv.FormatInt(e.Size, 10), e.MD5Checksum,
+		formatTime(e.LastModified),
+	}
+}
+
+// formatTime formats an epoch. A zero epoch is formatted as an empty string.
+func formatTime(epoch int64) string {
+	if epoch <= 0 {
+		return ""
+	}
+	return time.Unix(epoch, 0).Format(time.RFC3339)
+}
+
+// sortedKeys returns the sorted keys of a map with string keys.
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	switch tm := m.(type) {
+	case map[string]*apitypes.ServiceInfo:
+		for k := range tm {
+			keys = append(keys, k)
+		}
+	case map[string]*apitypes.Instance:
+		for k := range tm {
+			keys = append(keys, k)
+		}
+	case apitypes.ServiceVolumeMap:
+		for k := range tm {
+			keys = append(keys, k)
+		}
+	case apitypes.VolumeMap:
+		for k := range tm {
+			keys = append(keys, k)
+		}
+	case apitypes.ServiceSnapshotMap:
+		for k := range tm {
+			keys = append(keys, k)
+		}
+	case apitypes.SnapshotMap:
+		for k := range tm {
+			keys = append(keys, k)
+		}
+	case map[string]*apitypes.ExecutorInfo:
+		for k := range tm {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -56,6 +56,7 @@ func init() {
 // Run the server.
 func Run() {
 	server.CloseOnAbort()
+	server.ReloadOnSignal()
 
 	flag.Usage = printUsage
 	flag.Parse()
@@ -94,6 +95,15 @@ func Run() {
 			os.Exit(0)
 		}
 
+		configPath := *flagConfig
+		server.LoadConfig = func() (gofig.Config, error) {
+			config := gofigCore.New()
+			if err := config.ReadConfigFile(configPath); err != nil {
+				return nil, err
+			}
+			return config, nil
+		}
+
 		s, errs, err := server.Serve(nil, config)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "%s: error: %v\n", os.Args[0], err)
@@ -163,11 +173,25 @@ func Run() {
 		}
 		fmt.Fprintf(buf, "      %s:\n        driver: %s\n", sn, dn)
 	}
-	if err := config.ReadConfig(buf); err != nil {
+	svcsConfig := buf.Bytes()
+	if err := config.ReadConfig(bytes.NewReader(svcsConfig)); err != nil {
 		fmt.Fprintf(os.Stderr, "%s: error: %v\n", os.Args[0], err)
 		os.Exit(1)
 	}
 
+	// the services defined by the command line arguments are added to the
+	// configuration each time it is reloaded
+	server.LoadConfig = func() (gofig.Config, error) {
+		config, err := apiconfig.NewConfig()
+		if err != nil {
+			return nil, err
+		}
+		if err := config.ReadConfig(bytes.NewReader(svcsConfig)); err != nil {
+			return nil, err
+		}
+		return config, nil
+	}
+
 	server.CloseOnAbort()
 
 	_, errs, err := server.Serve(nil, config)
@@ -24,7 +24,7 @@ import (
 
 var (
 	cmdRx = regexp.MustCompile(
-		`(?i)^supported|instanceid|nextdevice|localdevices|wait$`)
+		`(?i)^supported|instanceid|nextdevice|localdevices|wait|mounts$`)
 )
 
 // Run runs the executor CLI.
@@ -113,6 +113,20 @@ func Run() {
 			opResult.Driver = driverName
 			result = opResult
 		}
+	} else if strings.EqualFold(cmd, apitypes.LSXCmdMounts) {
+		op = "mounts"
+		path := registry.MountStatePath(config)
+		if len(args) < 4 {
+			result, err = registry.LoadMountState(path)
+		} else if strings.EqualFold(args[3], "repair") && len(args) > 4 {
+			var consumerID string
+			if len(args) > 5 {
+				consumerID = args[5]
+			}
+			result, err = registry.RepairMountState(path, args[4], consumerID)
+		} else {
+			printUsageAndExit()
+		}
 	} else if strings.EqualFold(cmd, apitypes.LSXCmdWaitForDevice) {
 		if len(args) < 5 {
 			printUsageAndExit()
@@ -250,6 +264,8 @@ func printUsage() {
 	printUsageLeftPadded(w, lpad2, "nextDevice\n")
 	printUsageLeftPadded(w, lpad2, "localDevices <scanType>\n")
 	printUsageLeftPadded(w, lpad2, "wait <scanType> <attachToken> <timeout>\n")
+	printUsageLeftPadded(
+		w, lpad2, "mounts [repair <volumeName> [<consumerID>]]\n")
 	fmt.Fprintln(w)
 	executorVar := "executor:    "
 	printUsageLeftPadded(w, lpad1, executorVar)
@@ -271,12 +271,21 @@ func (d *driver) Mount(
 		opts.NewFSType = d.fsType()
 	}
 
+	// the OS driver records the encryption of the device in the store
+	osOpts := utils.NewStore()
+
+	// the volume's Encrypted flag reports encryption by the storage platform,
+	// so client-side encryption is only requested by the volume's fields
+	luks := vol.Fields[types.VolumeFieldEncryption] == "luks"
+
 	if err := client.OS().Format(
 		ctx,
 		ma.DeviceName,
 		&types.DeviceFormatOpts{
 			NewFSType:   opts.NewFSType,
 			OverwriteFS: opts.OverwriteFS,
+			Encrypted:   luks,
+			Opts:        osOpts,
 		}); err != nil {
 		return "", nil, err
 	}
@@ -294,10 +303,12 @@ func (d *driver) Mount(
 		ctx,
 		ma.DeviceName,
 		mountPath,
-		&types.DeviceMountOpts{}); err != nil {
+		&types.DeviceMountOpts{Opts: osOpts}); err != nil {
 		return "", nil, err
 	}
 
+	vol = d.recordEncryption(ctx, vol, osOpts)
+
 	mntPath := d.volumeMountPath(mountPath)
 
 	fields := log.Fields{
@@ -326,7 +337,7 @@ func (d *driver) Unmount(
 
 	vol, err := d.volumeInspectByIDOrName(
 		ctx, volumeID, volumeName,
-		types.VolAttReqWithDevMapOnlyVolsAttachedToInstance, opts)
+		types.VolAttReqWithDevMapForInstance, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -5,6 +5,7 @@ import (
 	"path"
 	"strings"
 
+	log "github.com/Sirupsen/logrus"
 	"github.com/akutz/goof"
 	"github.com/codedellemc/libstorage/api/context"
 	"github.com/codedellemc/libstorage/api/types"
@@ -89,3 +90,47 @@ func isErrNotFound(err error) bool {
 func (d *driver) volumeMountPath(target string) string {
 	return path.Join(target, d.volumeRootPath())
 }
+
+// recordEncryption records the encryption that the OS driver applied to the
+// volume's device in the volume's fields. A failure to record the fields
+// does not fail the mount since the device is already mounted.
+func (d *driver) recordEncryption(
+	ctx types.Context,
+	vol *types.Volume,
+	osOpts types.Store) *types.Volume {
+
+	fields := map[string]string{}
+	for _, k := range []string{
+		types.VolumeFieldEncryption,
+		types.VolumeFieldEncryptionKeyProvider,
+	} {
+		if v := osOpts.GetString(k); v != "" && vol.Fields[k] != v {
+			fields[k] = v
+		}
+	}
+	if len(fields) == 0 {
+		return vol
+	}
+
+	client := context.MustClient(ctx)
+	if _, err := client.Storage().VolumeUpdate(
+		ctx, vol.ID, &types.VolumeUpdateOpts{
+			Fields: fields,
+			Opts:   utils.NewStore(),
+		}); err != nil {
+		ctx.WithFields(log.Fields{
+			"volumeID": vol.ID,
+			"fields":   fields,
+		}).WithError(err).Warn("error recording volume encryption")
+		return vol
+	}
+
+	// the mounted volume keeps its attachments
+	if vol.Fields == nil {
+		vol.Fields = map[string]string{}
+	}
+	for k, v := range fields {
+		vol.Fields[k] = v
+	}
+	return vol
+}
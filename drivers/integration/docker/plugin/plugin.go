@@ -0,0 +1,332 @@
+/*
+Package plugin serves the Docker volume plugin protocol with a libStorage
+client's integration driver.
+
+Docker discovers the plugin by the unix socket that it listens on in the
+plugin directory, /run/docker/plugins by default, and forwards the volume
+requests for the plugin's volume driver to it. Each request is mapped onto
+the corresponding operation of the integration driver.
+*/
+package plugin
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path"
+	"sync"
+
+	log "github.com/Sirupsen/logrus"
+	gofig "github.com/akutz/gofig/types"
+	"github.com/akutz/goof"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/api/utils"
+)
+
+// ContentType is the content type of the plugin protocol's messages.
+const ContentType = "application/vnd.docker.plugins.v1.2+json"
+
+// Plugin is an HTTP handler that serves the Docker volume plugin protocol.
+type Plugin struct {
+	ctx    types.Context
+	client types.Client
+	scope  string
+	routes map[string]pluginFunc
+}
+
+type pluginFunc func(ctx types.Context, req *request) (*response, error)
+
+// request is the body of a request sent by Docker.
+type request struct {
+	Name string            `json:"Name,omitempty"`
+	ID   string            `json:"ID,omitempty"`
+	Opts map[string]string `json:"Opts,omitempty"`
+}
+
+// response is the body of a reply to Docker. An error is indicated by a
+// non-empty Err field.
+type response struct {
+	Implements   []string      `json:"Implements,omitempty"`
+	Mountpoint   string        `json:"Mountpoint,omitempty"`
+	Volume       *volume       `json:"Volume,omitempty"`
+	Volumes      []*volume     `json:"Volumes,omitempty"`
+	Capabilities *capabilities `json:"Capabilities,omitempty"`
+	Err          string        `json:"Err,omitempty"`
+}
+
+type volume struct {
+	Name       string                 `json:"Name"`
+	Mountpoint string                 `json:"Mountpoint,omitempty"`
+	Status     map[string]interface{} `json:"Status,omitempty"`
+}
+
+type capabilities struct {
+	Scope string `json:"Scope"`
+}
+
+// New returns a new plugin that serves requests with the client's
+// integration driver. The volume operations are performed with the provided
+// context, which should include the name of the service that owns the
+// volumes.
+func New(
+	ctx types.Context,
+	client types.Client,
+	config gofig.Config) (*Plugin, error) {
+
+	if client.Integration() == nil {
+		return nil, goof.New("client has no integration driver")
+	}
+
+	p := &Plugin{
+		ctx:    ctx,
+		client: client,
+		scope:  config.GetString(types.ConfigIgPluginScope),
+	}
+	if p.scope == "" {
+		p.scope = "global"
+	}
+
+	p.routes = map[string]pluginFunc{
+		"/Plugin.Activate":           p.activate,
+		"/VolumeDriver.Create":       p.create,
+		"/VolumeDriver.Remove":       p.remove,
+		"/VolumeDriver.Mount":        p.mount,
+		"/VolumeDriver.Unmount":      p.unmount,
+		"/VolumeDriver.Path":         p.path,
+		"/VolumeDriver.Get":          p.get,
+		"/VolumeDriver.List":         p.list,
+		"/VolumeDriver.Capabilities": p.capabilities,
+	}
+
+	return p, nil
+}
+
+// ServeHTTP handles a request sent by Docker.
+func (p *Plugin) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	f, ok := p.routes[req.URL.Path]
+	if !ok || req.Method != http.MethodPost {
+		http.NotFound(w, req)
+		return
+	}
+
+	ctx := p.ctx.WithValue(context.HTTPRequestKey, req)
+	fields := log.Fields{"route": req.URL.Path}
+
+	body := &request{}
+	if err := json.NewDecoder(req.Body).Decode(body); err != nil &&
+		err != io.EOF {
+		writeResponse(ctx, w, http.StatusBadRequest,
+			&response{Err: "invalid request: " + err.Error()})
+		return
+	}
+	fields["name"] = body.Name
+	ctx.WithFields(fields).Debug("plugin request")
+
+	res, err := f(ctx, body)
+	if err != nil {
+		ctx.WithFields(fields).WithError(err).Error("plugin request failed")
+		writeResponse(ctx, w, http.StatusInternalServerError,
+			&response{Err: err.Error()})
+		return
+	}
+
+	writeResponse(ctx, w, http.StatusOK, res)
+}
+
+func writeResponse(
+	ctx types.Context, w http.ResponseWriter, status int, res *response) {
+
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		ctx.WithError(err).Error("error writing plugin response")
+	}
+}
+
+func (p *Plugin) activate(
+	ctx types.Context, req *request) (*response, error) {
+	return &response{Implements: []string{"VolumeDriver"}}, nil
+}
+
+func (p *Plugin) capabilities(
+	ctx types.Context, req *request) (*response, error) {
+	return &response{Capabilities: &capabilities{Scope: p.scope}}, nil
+}
+
+func (p *Plugin) create(ctx types.Context, req *request) (*response, error) {
+	_, err := p.client.Integration().Create(
+		ctx, req.Name, &types.VolumeCreateOpts{
+			Opts: utils.NewStoreWithVars(req.Opts),
+		})
+	if err != nil {
+		return nil, err
+	}
+	return &response{}, nil
+}
+
+func (p *Plugin) remove(ctx types.Context, req *request) (*response, error) {
+	err := p.client.Integration().Remove(ctx, req.Name, utils.NewStore())
+	if err != nil {
+		return nil, err
+	}
+	return &response{}, nil
+}
+
+// mount mounts the volume on behalf of the container identified by the
+// request's ID so that the volume remains mounted until each container that
+// mounted it has unmounted it.
+func (p *Plugin) mount(ctx types.Context, req *request) (*response, error) {
+	mp, _, err := p.client.Integration().Mount(
+		ctx, "", req.Name, &types.VolumeMountOpts{
+			ConsumerID: req.ID,
+			Opts:       utils.NewStore(),
+		})
+	if err != nil {
+		return nil, err
+	}
+	return &response{Mountpoint: mp}, nil
+}
+
+func (p *Plugin) unmount(
+	ctx types.Context, req *request) (*response, error) {
+
+	opts := utils.NewStore()
+	opts.Set(types.ConsumerIDOptKey, req.ID)
+	if _, err := p.client.Integration().Unmount(
+		ctx, "", req.Name, opts); err != nil {
+		return nil, err
+	}
+	return &response{}, nil
+}
+
+func (p *Plugin) path(ctx types.Context, req *request) (*response, error) {
+	mp, err := p.client.Integration().Path(
+		ctx, "", req.Name, utils.NewStore())
+	if err != nil {
+		return nil, err
+	}
+	return &response{Mountpoint: mp}, nil
+}
+
+func (p *Plugin) get(ctx types.Context, req *request) (*response, error) {
+	vm, err := p.client.Integration().Inspect(
+		ctx, req.Name, newAttachmentsStore())
+	if err != nil {
+		return nil, err
+	}
+	return &response{Volume: &volume{
+		Name:       vm.VolumeName(),
+		Mountpoint: vm.MountPoint(),
+		Status:     vm.Status(),
+	}}, nil
+}
+
+func (p *Plugin) list(ctx types.Context, req *request) (*response, error) {
+	vms, err := p.client.Integration().List(ctx, newAttachmentsStore())
+	if err != nil {
+		return nil, err
+	}
+	vols := []*volume{}
+	for _, vm := range vms {
+		vols = append(vols, &volume{
+			Name:       vm.VolumeName(),
+			Mountpoint: vm.MountPoint(),
+		})
+	}
+	return &response{Volumes: vols}, nil
+}
+
+// newAttachmentsStore returns options that request the attachments of all of
+// the volumes, mapped to this instance's devices, so that the mount points of
+// the attached volumes are known without hiding the unattached volumes.
+func newAttachmentsStore() types.Store {
+	return utils.NewStoreWithData(map[string]interface{}{
+		"attachments": types.VolAttReqWithDevMapForInstance,
+	})
+}
+
+// Server is a plugin that is listening on a unix socket.
+type Server struct {
+	sync.Mutex
+	ctx      types.Context
+	sockPath string
+	l        net.Listener
+	closed   bool
+}
+
+// Serve starts serving the plugin on the unix socket named for the plugin in
+// the configured plugin directory. The returned channel receives an error
+// if the server stops unexpectedly and is closed when the server stops.
+func Serve(
+	ctx types.Context,
+	client types.Client,
+	config gofig.Config) (*Server, <-chan error, error) {
+
+	if service := config.GetString(types.ConfigService); service != "" {
+		ctx = ctx.WithValue(context.ServiceKey, service)
+	}
+
+	p, err := New(ctx, client, config)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		name     = config.GetString(types.ConfigIgPluginName)
+		dir      = config.GetString(types.ConfigIgPluginPath)
+		sockPath = path.Join(dir, name+".sock")
+	)
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, nil, goof.WithFieldE(
+			"path", dir, "error creating plugin dir", err)
+	}
+
+	// a socket file that remains from a previous process prevents listening
+	os.RemoveAll(sockPath)
+
+	l, err := net.Listen("unix", sockPath)
+	if err != nil {
+		return nil, nil, goof.WithFieldE(
+			"path", sockPath, "error listening on plugin socket", err)
+	}
+
+	s := &Server{ctx: ctx, sockPath: sockPath, l: l}
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		err := http.Serve(l, p)
+		s.Lock()
+		defer s.Unlock()
+		if !s.closed {
+			errs <- err
+		}
+	}()
+
+	ctx.WithField("path", sockPath).Info("serving docker volume plugin")
+	return s, errs, nil
+}
+
+// Addr returns the path of the plugin's unix socket.
+func (s *Server) Addr() string {
+	return s.sockPath
+}
+
+// Close stops the server and removes its socket file.
+func (s *Server) Close() error {
+	s.Lock()
+	defer s.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	err := s.l.Close()
+	os.RemoveAll(s.sockPath)
+	s.ctx.WithField("path", s.sockPath).Info("closed docker volume plugin")
+	return err
+}
@@ -3,7 +3,6 @@
 package linux
 
 import (
-	"bytes"
 	"fmt"
 	"os"
 	"os/exec"
@@ -23,8 +22,8 @@ const driverName = "linux"
 
 var (
 	errUnknownOS             = goof.New("unknown OS")
-	errUnknownFileSystem     = goof.New("unknown file system")
 	errUnsupportedFileSystem = goof.New("unsupported file system")
+	errDeviceNotBlank        = goof.New("device is not blank")
 )
 
 func init() {
@@ -67,9 +66,16 @@ func (d *driver) Mounts(
 		return nil, goof.New("cannot specify mountPoint and deviceName")
 	}
 
+	// the file system of an encrypted device is mounted from its mapping
+	var mapperPath string
+	if deviceName != "" {
+		mapperPath, _ = luksMapperPath(deviceName)
+	}
+
 	matchedMounts := []*types.MountInfo{}
 	for _, m := range mounts {
-		if m.MountPoint == mountPoint || m.Source == deviceName {
+		if m.MountPoint == mountPoint || m.Source == deviceName ||
+			(mapperPath != "" && m.Source == mapperPath) {
 			matchedMounts = append(matchedMounts, m)
 		}
 	}
@@ -93,11 +99,42 @@ func (d *driver) Mount(
 		return nil
 	}
 
+	// a bind mount's source is a directory rather than a device with a file
+	// system to probe
+	if flag, _ := parseOptions(opts.MountOptions); flag&BIND == BIND {
+		err := mount(deviceName, mountPoint, "", opts.MountOptions)
+		if err != nil {
+			return goof.WithFieldsE(goof.Fields{
+				"source":     deviceName,
+				"mountPoint": mountPoint,
+			}, "error bind mounting directory", err)
+		}
+		return nil
+	}
+
 	fsType, err := probeFsType(deviceName)
 	if err != nil {
 		return err
 	}
 
+	// the file system of an encrypted device is mounted from its mapping
+	if fsType == sigLUKS {
+		if deviceName, err = d.luksOpen(ctx, deviceName); err != nil {
+			return err
+		}
+		d.recordLUKS(opts.Opts)
+		if fsType, err = probeFsType(deviceName); err != nil {
+			return err
+		}
+	}
+
+	if !isFileSystem(fsType) {
+		return goof.WithFieldsE(goof.Fields{
+			"deviceName": deviceName,
+			"contents":   fsType,
+		}, "error mounting device", errUnsupportedFileSystem)
+	}
+
 	options := formatMountLabel("", opts.MountLabel)
 	options = fmt.Sprintf("%s,%s", opts.MountOptions, opts.MountLabel)
 	if fsType == "xfs" {
@@ -105,6 +142,9 @@ func (d *driver) Mount(
 	}
 
 	if err := mount(deviceName, mountPoint, fsType, options); err != nil {
+		if isLUKSMapperPath(deviceName) {
+			d.luksClose(ctx, deviceName)
+		}
 		return goof.WithFieldsE(goof.Fields{
 			"deviceName": deviceName,
 			"mountPoint": mountPoint,
@@ -122,7 +162,36 @@ func (d *driver) Unmount(
 	mountPoint string,
 	opts types.Store) error {
 
-	return unmount(mountPoint)
+	mounts, err := getMounts()
+	if err != nil {
+		return err
+	}
+	var source string
+	for _, m := range mounts {
+		if m.MountPoint == mountPoint {
+			source = m.Source
+			break
+		}
+	}
+
+	if err := unmount(mountPoint); err != nil {
+		return err
+	}
+	if !isLUKSMapperPath(source) {
+		return nil
+	}
+
+	// the mapping of an encrypted device is closed once the device is no
+	// longer mounted anywhere, such as at a bind mount
+	if mounts, err = getMounts(); err != nil {
+		return err
+	}
+	for _, m := range mounts {
+		if m.Source == source {
+			return nil
+		}
+	}
+	return d.luksClose(ctx, source)
 }
 
 func (d *driver) IsMounted(
@@ -138,112 +207,160 @@ func (d *driver) Format(
 	deviceName string,
 	opts *types.DeviceFormatOpts) error {
 
-	fsType, err := probeFsType(deviceName)
-	if err != nil && err != errUnknownFileSystem {
+	fsType, blank, err := probeDevice(deviceName)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case fsType == sigLUKS && !opts.OverwriteFS:
+		// the file system is created within the existing encryption
+	case (opts.Encrypted || d.luksEnabled()) && (blank || opts.OverwriteFS):
+		if err := d.luksFormat(ctx, deviceName); err != nil {
+			return err
+		}
+	default:
+		return d.format(ctx, deviceName, fsType, blank, opts)
+	}
+
+	d.recordLUKS(opts.Opts)
+
+	// the mapping is closed after the file system is created unless it was
+	// already open
+	mapperPath, err := luksMapperPath(deviceName)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(mapperPath); os.IsNotExist(err) {
+		defer d.luksClose(ctx, mapperPath)
+	}
+	if _, err := d.luksOpen(ctx, deviceName); err != nil {
 		return err
 	}
-	fsDetected := fsType != ""
+
+	if fsType, blank, err = probeDevice(mapperPath); err != nil {
+		return err
+	}
+	return d.format(ctx, mapperPath, fsType, blank, opts)
+}
+
+// format creates a file system on the device if the device is blank or if
+// overwriting the device's contents is requested.
+func (d *driver) format(
+	ctx types.Context,
+	deviceName, fsType string,
+	blank bool,
+	opts *types.DeviceFormatOpts) error {
+
+	fsDetected := isFileSystem(fsType)
 
 	ctx.WithFields(log.Fields{
 		"fsDetected":  fsDetected,
 		"fsType":      fsType,
+		"blank":       blank,
 		"deviceName":  deviceName,
 		"overwriteFs": opts.OverwriteFS,
 		"driverName":  driverName}).Info("probe information")
 
-	if opts.OverwriteFS || !fsDetected {
-		switch opts.NewFSType {
-		case "ext4":
-			if err := exec.Command(
-				"mkfs.ext4", "-F", deviceName).Run(); err != nil {
-				return goof.WithFieldE(
-					"deviceName", deviceName,
-					"error creating filesystem",
-					err)
-			}
-		case "xfs":
-			if err := exec.Command(
-				"mkfs.xfs", "-f", deviceName).Run(); err != nil {
-				return goof.WithFieldE(
-					"deviceName", deviceName,
-					"error creating filesystem",
-					err)
+	if !opts.OverwriteFS {
+		if fsDetected {
+			return nil
+		}
+		// a device that has contents other than a file system, such as swap,
+		// a partition table, or data that is not recognized, is never
+		// formatted unless overwriting it is requested
+		if !blank {
+			contents := fsType
+			if contents == "" {
+				contents = "unknown"
 			}
-		default:
-			return errUnsupportedFileSystem
+			return goof.WithFieldsE(goof.Fields{
+				"deviceName": deviceName,
+				"contents":   contents,
+			}, "error creating filesystem", errDeviceNotBlank)
 		}
 	}
 
-	return nil
-}
-
-func (d *driver) isNfsDevice(device string) bool {
-	return strings.Contains(device, ":")
-}
-
-func (d *driver) nfsMount(device, target string) error {
-	command := exec.Command("mount", device, target)
-	output, err := command.CombinedOutput()
+	cmd, err := d.mkfsCommand(opts.NewFSType, deviceName)
 	if err != nil {
-		return goof.WithError(fmt.Sprintf("failed mounting: %s", output), err)
+		return err
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return goof.WithFieldsE(goof.Fields{
+			"deviceName": deviceName,
+			"fsType":     opts.NewFSType,
+			"output":     string(out),
+		}, "error creating filesystem", err)
 	}
 
 	return nil
 }
 
-func (d *driver) fileModeMountPath() (fileMode os.FileMode) {
-	return os.FileMode(d.volumeFileMode())
-}
-
-// from github.com/docker/docker/daemon/graphdriver/devmapper/
-// this should be abstracted outside of graphdriver but within Docker package,
-// here temporarily
-type probeData struct {
-	fsName string
-	magic  string
-	offset uint64
-}
+func (d *driver) Resize(
+	ctx types.Context,
+	deviceName, mountPoint string,
+	opts types.Store) error {
 
-func probeFsType(device string) (string, error) {
-	probes := []probeData{
-		{"btrfs", "_BHRfS_M", 0x10040},
-		{"ext4", "\123\357", 0x438},
-		{"xfs", "XFSB", 0},
+	fsType, err := probeFsType(deviceName)
+	if err != nil {
+		return err
 	}
 
-	maxLen := uint64(0)
-	for _, p := range probes {
-		l := p.offset + uint64(len(p.magic))
-		if l > maxLen {
-			maxLen = l
+	// the mapping of an encrypted device is grown before its file system
+	if fsType == sigLUKS {
+		if deviceName, err = d.luksResize(ctx, deviceName); err != nil {
+			return err
+		}
+		if fsType, err = probeFsType(deviceName); err != nil {
+			return err
 		}
 	}
 
-	file, err := os.Open(device)
-	if err != nil {
-		return "", err
+	var cmd *exec.Cmd
+	switch fsType {
+	case "ext2", "ext3", "ext4":
+		cmd = exec.Command("resize2fs", deviceName)
+	case "xfs":
+		cmd = exec.Command("xfs_growfs", mountPoint)
+	case "btrfs":
+		cmd = exec.Command("btrfs", "filesystem", "resize", "max", mountPoint)
+	default:
+		return errUnsupportedFileSystem
 	}
-	defer file.Close()
 
-	buffer := make([]byte, maxLen)
-	l, err := file.Read(buffer)
-	if err != nil {
-		return "", err
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return goof.WithFieldsE(goof.Fields{
+			"deviceName": deviceName,
+			"mountPoint": mountPoint,
+			"fsType":     fsType,
+			"output":     string(out),
+		}, "error resizing filesystem", err)
 	}
 
-	if uint64(l) != maxLen {
-		return "", goof.WithField(
-			"device", device, "error detecting filesystem")
-	}
+	ctx.WithFields(log.Fields{
+		"deviceName": deviceName,
+		"mountPoint": mountPoint,
+		"fsType":     fsType,
+		"driverName": driverName}).Info("resized filesystem")
+	return nil
+}
 
-	for _, p := range probes {
-		if bytes.Equal(
-			[]byte(p.magic), buffer[p.offset:p.offset+uint64(len(p.magic))]) {
-			return p.fsName, nil
-		}
+func (d *driver) isNfsDevice(device string) bool {
+	return strings.Contains(device, ":")
+}
+
+func (d *driver) nfsMount(device, target string) error {
+	command := exec.Command("mount", device, target)
+	output, err := command.CombinedOutput()
+	if err != nil {
+		return goof.WithError(fmt.Sprintf("failed mounting: %s", output), err)
 	}
 
-	return "", errUnknownFileSystem
+	return nil
+}
+
+func (d *driver) fileModeMountPath() (fileMode os.FileMode) {
+	return os.FileMode(d.volumeFileMode())
 }
 
 func (d *driver) volumeMountPath(target string) string {
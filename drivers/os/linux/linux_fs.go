@@ -0,0 +1,214 @@
+// +build linux
+
+package linux
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/akutz/goof"
+)
+
+// The names of the signatures that are detected on a device that are not
+// file systems. The names are those that blkid reports.
+const (
+	sigSwap = "swap"
+	sigLUKS = "crypto_LUKS"
+	sigGPT  = "gpt"
+	sigDOS  = "dos"
+)
+
+// probeData is a signature that identifies the contents of a device.
+//
+// from github.com/docker/docker/daemon/graphdriver/devmapper/
+// this should be abstracted outside of graphdriver but within Docker package,
+// here temporarily
+type probeData struct {
+	fsName string
+	magic  string
+	offset uint64
+}
+
+// probes are checked in order, so the signatures of the contents that may
+// contain another signature, such as a partition table whose first partition
+// holds a file system, are checked first.
+var probes = []probeData{
+	{sigLUKS, "LUKS\xba\xbe", 0},
+	{sigGPT, "EFI PART", 512},
+	{sigGPT, "EFI PART", 4096},
+	{"btrfs", "_BHRfS_M", 0x10040},
+	{"ext", "\123\357", 0x438},
+	{"xfs", "XFSB", 0},
+	{sigSwap, "SWAPSPACE2", 4096 - 10},
+	{sigSwap, "SWAP-SPACE", 4096 - 10},
+	{sigSwap, "SWAPSPACE2", 8192 - 10},
+	{sigSwap, "SWAPSPACE2", 16384 - 10},
+	{sigSwap, "SWAPSPACE2", 65536 - 10},
+	{sigDOS, "\x55\xaa", 510},
+}
+
+// probeLen is the number of bytes at the start of a device that are read in
+// order to detect its contents.
+var probeLen = func() uint64 {
+	l := uint64(0)
+	for _, p := range probes {
+		if pl := p.offset + uint64(len(p.magic)); pl > l {
+			l = pl
+		}
+	}
+	return l
+}()
+
+var (
+	// errUnknownFileSystem is returned when a device is blank.
+	errUnknownFileSystem = goof.New("unknown file system")
+
+	// errUnknownContents is returned when a device is not blank but none of
+	// the known signatures are detected.
+	errUnknownContents = goof.New("unknown device contents")
+)
+
+// probeFsType returns the name of the file system, or other known contents,
+// on the device. errUnknownFileSystem is returned if the device is blank and
+// errUnknownContents if the device is not blank but its contents are not
+// recognized.
+func probeFsType(device string) (string, error) {
+	file, err := os.Open(device)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	fsType, err := probe(file)
+	if err != nil && err != errUnknownFileSystem &&
+		err != errUnknownContents {
+		return "", goof.WithFieldE(
+			"device", device, "error detecting filesystem", err)
+	}
+	return fsType, err
+}
+
+// probeDevice returns the name of the file system, or other known contents,
+// on the device and a flag indicating whether the device is blank.
+func probeDevice(device string) (string, bool, error) {
+	fsType, err := probeFsType(device)
+	switch err {
+	case errUnknownFileSystem:
+		return "", true, nil
+	case errUnknownContents:
+		return "", false, nil
+	}
+	return fsType, false, err
+}
+
+// probe detects the contents of the data read from r. A device that is
+// smaller than the probed region is treated as if it were padded with zeros.
+func probe(r io.ReaderAt) (string, error) {
+	buf := make([]byte, probeLen)
+	if _, err := r.ReadAt(buf, 0); err != nil && err != io.EOF {
+		return "", err
+	}
+
+	for _, p := range probes {
+		end := p.offset + uint64(len(p.magic))
+		if !bytes.Equal([]byte(p.magic), buf[p.offset:end]) {
+			continue
+		}
+		if p.fsName == "ext" {
+			return probeExtVersion(buf), nil
+		}
+		return p.fsName, nil
+	}
+
+	for _, b := range buf {
+		if b != 0 {
+			return "", errUnknownContents
+		}
+	}
+	return "", errUnknownFileSystem
+}
+
+// The offset of an ext file system's superblock and the offsets of its
+// feature flags within the superblock.
+const (
+	extSuperblock      = 0x400
+	extFeatureCompat   = extSuperblock + 0x5c
+	extFeatureIncompat = extSuperblock + 0x60
+	extFeatureRoCompat = extSuperblock + 0x64
+)
+
+// probeExtVersion returns the version of an ext file system by its feature
+// flags as blkid does: a journal indicates ext3, and the features that were
+// introduced with ext4 indicate ext4.
+func probeExtVersion(buf []byte) string {
+	var (
+		compat   = binary.LittleEndian.Uint32(buf[extFeatureCompat:])
+		incompat = binary.LittleEndian.Uint32(buf[extFeatureIncompat:])
+		roCompat = binary.LittleEndian.Uint32(buf[extFeatureRoCompat:])
+	)
+
+	const (
+		compatHasJournal = 0x0004
+
+		// the ext3 incompatible features are filetype, recover, journal_dev,
+		// and meta_bg
+		incompatExt3 = 0x0002 | 0x0004 | 0x0008 | 0x0010
+
+		// the ext2 and ext3 read-only compatible features are sparse_super,
+		// large_file, and btree_dir
+		roCompatExt3 = 0x0001 | 0x0002 | 0x0004
+	)
+
+	switch {
+	case incompat&^incompatExt3 != 0 || roCompat&^roCompatExt3 != 0:
+		return "ext4"
+	case compat&compatHasJournal != 0:
+		return "ext3"
+	default:
+		return "ext2"
+	}
+}
+
+// isFileSystem returns a flag indicating whether the detected contents are a
+// file system that may be mounted.
+func isFileSystem(fsType string) bool {
+	switch fsType {
+	case "", sigSwap, sigLUKS, sigGPT, sigDOS:
+		return false
+	}
+	return true
+}
+
+// mkfsCommands are the commands that create the supported file systems and
+// the arguments that force the creation of a file system over existing
+// contents.
+var mkfsCommands = map[string][]string{
+	"btrfs": {"mkfs.btrfs", "-f"},
+	"ext3":  {"mkfs.ext3", "-F"},
+	"ext4":  {"mkfs.ext4", "-F"},
+	"xfs":   {"mkfs.xfs", "-f"},
+}
+
+// mkfsCommand returns the command that creates a file system of the given
+// type on the device with the configured arguments.
+func (d *driver) mkfsCommand(fsType, device string) (*exec.Cmd, error) {
+	cmd, ok := mkfsCommands[fsType]
+	if !ok {
+		return nil, errUnsupportedFileSystem
+	}
+	args := append([]string{}, cmd[1:]...)
+	args = append(args, d.mkfsArgs(fsType)...)
+	args = append(args, device)
+	return exec.Command(cmd[0], args...), nil
+}
+
+// mkfsArgs returns the configured arguments for the mkfs command of the
+// file system type, such as an inode ratio or a label.
+func (d *driver) mkfsArgs(fsType string) []string {
+	return strings.Fields(
+		d.config.GetString("linux.mkfs." + fsType + ".args"))
+}
@@ -0,0 +1,141 @@
+// +build linux
+
+package linux
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	gofigCore "github.com/akutz/gofig"
+	"github.com/stretchr/testify/assert"
+)
+
+// imageSize is the size of the image files that stand in for devices.
+const imageSize = 1024 * 1024
+
+// newImage creates a sparse image file, writes the data at the offsets, and
+// returns the path to the image.
+func newImage(t *testing.T, data map[int64][]byte) string {
+	f, err := ioutil.TempFile("", "linux-fs-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if err := f.Truncate(imageSize); err != nil {
+		t.Fatal(err)
+	}
+	for off, b := range data {
+		if _, err := f.WriteAt(b, off); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return f.Name()
+}
+
+// extImage returns the data for an ext file system with the feature flags.
+func extImage(compat, incompat, roCompat uint32) map[int64][]byte {
+	features := make([]byte, 12)
+	binary.LittleEndian.PutUint32(features[0:], compat)
+	binary.LittleEndian.PutUint32(features[4:], incompat)
+	binary.LittleEndian.PutUint32(features[8:], roCompat)
+	return map[int64][]byte{
+		0x438:            []byte("\123\357"),
+		extFeatureCompat: features,
+	}
+}
+
+func TestProbeFsType(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   map[int64][]byte
+		fsType string
+		err    error
+	}{
+		{"blank", nil, "", errUnknownFileSystem},
+		{"unknown", map[int64][]byte{
+			0x2000: []byte("data")}, "", errUnknownContents},
+		{"btrfs", map[int64][]byte{
+			0x10040: []byte("_BHRfS_M")}, "btrfs", nil},
+		{"xfs", map[int64][]byte{0: []byte("XFSB")}, "xfs", nil},
+		{"ext2", extImage(0, 0x2, 0x1), "ext2", nil},
+		{"ext3", extImage(0x4, 0x2, 0x1), "ext3", nil},
+		{"ext4", extImage(0x4, 0x2|0x40, 0x1), "ext4", nil},
+		{"ext4RoCompat", extImage(0x4, 0x2, 0x1|0x8), "ext4", nil},
+		{"swap", map[int64][]byte{
+			4096 - 10: []byte("SWAPSPACE2")}, sigSwap, nil},
+		{"swap64k", map[int64][]byte{
+			65536 - 10: []byte("SWAPSPACE2")}, sigSwap, nil},
+		{"luks", map[int64][]byte{
+			0: []byte("LUKS\xba\xbe")}, sigLUKS, nil},
+		{"gpt", map[int64][]byte{
+			510: []byte("\x55\xaa"),
+			512: []byte("EFI PART")}, sigGPT, nil},
+		{"dos", map[int64][]byte{
+			510: []byte("\x55\xaa")}, sigDOS, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			image := newImage(t, tt.data)
+			defer os.Remove(image)
+
+			fsType, err := probeFsType(image)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.fsType, fsType)
+		})
+	}
+}
+
+func TestProbeShortDevice(t *testing.T) {
+	buf := make([]byte, 1024)
+	fsType, err := probe(bytes.NewReader(buf))
+	assert.Equal(t, errUnknownFileSystem, err)
+	assert.Empty(t, fsType)
+
+	copy(buf, "XFSB")
+	fsType, err = probe(bytes.NewReader(buf))
+	assert.NoError(t, err)
+	assert.Equal(t, "xfs", fsType)
+}
+
+func TestIsFileSystem(t *testing.T) {
+	for _, fsType := range []string{"btrfs", "ext2", "ext3", "ext4", "xfs"} {
+		assert.True(t, isFileSystem(fsType), fsType)
+	}
+	for _, fsType := range []string{"", sigSwap, sigLUKS, sigGPT, sigDOS} {
+		assert.False(t, isFileSystem(fsType), fsType)
+	}
+}
+
+func TestMkfsCommand(t *testing.T) {
+	config := gofigCore.New()
+	if err := config.ReadConfig(bytes.NewReader([]byte(`
+linux:
+  mkfs:
+    ext4:
+      args: -i 65536 -L data
+`))); err != nil {
+		t.Fatal(err)
+	}
+	d := &driver{config: config}
+
+	cmd, err := d.mkfsCommand("ext4", "/dev/xvdb")
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{
+			"mkfs.ext4", "-F", "-i", "65536", "-L", "data", "/dev/xvdb",
+		}, cmd.Args)
+	}
+
+	cmd, err = d.mkfsCommand("btrfs", "/dev/xvdb")
+	if assert.NoError(t, err) {
+		assert.Equal(t,
+			[]string{"mkfs.btrfs", "-f", "/dev/xvdb"}, cmd.Args)
+	}
+
+	_, err = d.mkfsCommand("ext2", "/dev/xvdb")
+	assert.Equal(t, errUnsupportedFileSystem, err)
+}
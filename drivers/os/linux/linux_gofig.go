@@ -11,5 +11,15 @@ func init() {
 	r := gofigCore.NewRegistration("Linux")
 	r.Key(gofig.Int, "", 0700, "", "linux.volume.filemode")
 	r.Key(gofig.String, "", "/data", "", "linux.volume.rootpath")
+	r.Key(gofig.String, "", "", "", "linux.mkfs.btrfs.args")
+	r.Key(gofig.String, "", "", "", "linux.mkfs.ext3.args")
+	r.Key(gofig.String, "", "", "", "linux.mkfs.ext4.args")
+	r.Key(gofig.String, "", "", "", "linux.mkfs.xfs.args")
+	r.Key(gofig.Bool, "", false, "", "linux.luks.enabled")
+	r.Key(gofig.String, "", "file", "", "linux.luks.keyprovider")
+	r.Key(gofig.String, "", "", "", "linux.luks.keyfile")
+	r.Key(gofig.String, "", "LIBSTORAGE_LUKS_KEY", "", "linux.luks.keyenv")
+	r.Key(gofig.String, "", "", "", "linux.luks.keyexec")
+	r.Key(gofig.String, "", "", "", "linux.luks.format.args")
 	gofigCore.Register(r)
 }
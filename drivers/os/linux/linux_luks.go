@@ -0,0 +1,397 @@
+// +build linux
+
+package linux
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/akutz/goof"
+
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+const (
+	// luksEncryption is the value of a volume's encryption field when its
+	// device is encrypted with LUKS.
+	luksEncryption = "luks"
+
+	// luksMapperDir is the directory of the device mapper's devices.
+	luksMapperDir = "/dev/mapper"
+
+	// luksMapperPrefix is the prefix of the names of the device mappings
+	// that the driver opens.
+	luksMapperPrefix = "libstorage-"
+
+	// luksMagic is the magic number at the start of a LUKS header.
+	luksMagic = "LUKS\xba\xbe"
+
+	// luksUUIDOffset and luksUUIDLen locate the UUID in the header of both
+	// LUKS1 and LUKS2 devices.
+	luksUUIDOffset = 168
+	luksUUIDLen    = 40
+
+	defaultLUKSKeyEnv = "LIBSTORAGE_LUKS_KEY"
+)
+
+var (
+	errUnknownKeyProvider = goof.New("unknown luks key provider")
+	errMissingKey         = goof.New("missing luks key")
+)
+
+// keyProvider returns the key for the LUKS device. A nil key indicates that
+// the key is read from the file returned as keyFile.
+type keyProvider func(
+	d *driver, deviceName string) (key []byte, keyFile string, err error)
+
+// keyProviders are the providers of the keys with which devices are
+// encrypted, by the names with which they are configured.
+var keyProviders = map[string]keyProvider{
+	"file": fileKeyProvider,
+	"env":  envKeyProvider,
+	"exec": execKeyProvider,
+}
+
+// fileKeyProvider reads the key from the configured file.
+func fileKeyProvider(
+	d *driver, deviceName string) ([]byte, string, error) {
+
+	keyFile := d.config.GetString("linux.luks.keyfile")
+	if keyFile == "" {
+		return nil, "", goof.WithFieldE(
+			"provider", "file", "missing key file", errMissingKey)
+	}
+	if _, err := os.Stat(keyFile); err != nil {
+		return nil, "", goof.WithFieldE(
+			"keyFile", keyFile, "error reading key file", err)
+	}
+	return nil, keyFile, nil
+}
+
+// envKeyProvider reads the key from the configured environment variable.
+func envKeyProvider(
+	d *driver, deviceName string) ([]byte, string, error) {
+
+	keyEnv := d.config.GetString("linux.luks.keyenv")
+	if keyEnv == "" {
+		keyEnv = defaultLUKSKeyEnv
+	}
+	key := os.Getenv(keyEnv)
+	if key == "" {
+		return nil, "", goof.WithFieldE(
+			"keyEnv", keyEnv, "missing key environment variable",
+			errMissingKey)
+	}
+	return []byte(key), "", nil
+}
+
+// execKeyProvider reads the key from the standard output of the configured
+// command. The device's name is the command's last argument so that the
+// command is able to return a different key for each device. A single
+// trailing newline is removed from the output.
+func execKeyProvider(
+	d *driver, deviceName string) ([]byte, string, error) {
+
+	args := strings.Fields(d.config.GetString("linux.luks.keyexec"))
+	if len(args) == 0 {
+		return nil, "", goof.WithFieldE(
+			"provider", "exec", "missing key command", errMissingKey)
+	}
+	args = append(args, deviceName)
+
+	stderr := &bytes.Buffer{}
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stderr = stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, "", goof.WithFieldsE(goof.Fields{
+			"command": args[0],
+			"output":  stderr.String(),
+		}, "error executing key command", err)
+	}
+
+	key := bytes.TrimSuffix(out, []byte("\n"))
+	if len(key) == 0 {
+		return nil, "", goof.WithFieldE(
+			"command", args[0], "key command returned no key", errMissingKey)
+	}
+	return key, "", nil
+}
+
+// luksEnabled returns a flag indicating whether all of the devices the
+// driver formats are encrypted.
+func (d *driver) luksEnabled() bool {
+	return d.config.GetBool("linux.luks.enabled")
+}
+
+// luksKeyProviderName returns the name of the configured key provider.
+func (d *driver) luksKeyProviderName() string {
+	return strings.ToLower(d.config.GetString("linux.luks.keyprovider"))
+}
+
+// luksKey returns the key for the device from the configured key provider.
+func (d *driver) luksKey(deviceName string) ([]byte, string, error) {
+	name := d.luksKeyProviderName()
+	provider, ok := keyProviders[name]
+	if !ok {
+		return nil, "", goof.WithFieldE(
+			"provider", name, "error getting luks key", errUnknownKeyProvider)
+	}
+	return provider(d, deviceName)
+}
+
+// luksUUID returns the UUID recorded in the device's LUKS header.
+func luksUUID(deviceName string) (string, error) {
+	f, err := os.Open(deviceName)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	return readLUKSUUID(f, deviceName)
+}
+
+func readLUKSUUID(r io.ReaderAt, deviceName string) (string, error) {
+	buf := make([]byte, luksUUIDOffset+luksUUIDLen)
+	if _, err := r.ReadAt(buf, 0); err != nil {
+		return "", goof.WithFieldE(
+			"deviceName", deviceName, "error reading luks header", err)
+	}
+	if !bytes.HasPrefix(buf, []byte(luksMagic)) {
+		return "", goof.WithField(
+			"deviceName", deviceName, "device is not encrypted with luks")
+	}
+	uuid := string(bytes.TrimRight(buf[luksUUIDOffset:], "\x00"))
+	if uuid == "" {
+		return "", goof.WithField(
+			"deviceName", deviceName, "missing luks uuid")
+	}
+	return uuid, nil
+}
+
+// luksMapperName returns the name of the device mapping of the device. The
+// mapping is named after the device's LUKS UUID rather than the device's
+// name since a device name is reused once its volume is detached.
+func luksMapperName(deviceName string) (string, error) {
+	uuid, err := luksUUID(deviceName)
+	if err != nil {
+		return "", err
+	}
+	return luksMapperPrefix + uuid, nil
+}
+
+// luksMapperPath returns the path to the device mapping of the device.
+func luksMapperPath(deviceName string) (string, error) {
+	name, err := luksMapperName(deviceName)
+	if err != nil {
+		return "", err
+	}
+	return path.Join(luksMapperDir, name), nil
+}
+
+// luksBackingDevice returns the device of the open mapping with the
+// specified name.
+func luksBackingDevice(mapperName string) (string, error) {
+	out, err := exec.Command(
+		"cryptsetup", "status", mapperName).CombinedOutput()
+	if err != nil {
+		return "", goof.WithFieldsE(goof.Fields{
+			"mapperName": mapperName,
+			"output":     string(out),
+		}, "error getting encrypted device status", err)
+	}
+	device := parseLUKSStatusDevice(out)
+	if device == "" {
+		return "", goof.WithFields(goof.Fields{
+			"mapperName": mapperName,
+			"output":     string(out),
+		}, "error getting encrypted device status")
+	}
+	return device, nil
+}
+
+// parseLUKSStatusDevice returns the device in the output of the cryptsetup
+// status command.
+func parseLUKSStatusDevice(out []byte) string {
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == "device:" {
+			return fields[1]
+		}
+	}
+	return ""
+}
+
+// isSameDevice returns a flag indicating whether the paths refer to the same
+// device, such as a device and a symlink to it.
+func isSameDevice(a, b string) bool {
+	if a == b {
+		return true
+	}
+	ra, err := filepath.EvalSymlinks(a)
+	if err != nil {
+		return false
+	}
+	rb, err := filepath.EvalSymlinks(b)
+	if err != nil {
+		return false
+	}
+	return ra == rb
+}
+
+// isLUKSMapperPath returns a flag indicating whether the device is a mapping
+// that the driver opened.
+func isLUKSMapperPath(deviceName string) bool {
+	return path.Dir(deviceName) == luksMapperDir &&
+		strings.HasPrefix(path.Base(deviceName), luksMapperPrefix)
+}
+
+// luksCommand returns the cryptsetup command with the key of the device. The
+// key is read from standard input unless the key provider returns a file.
+func (d *driver) luksCommand(
+	deviceName string, args ...string) (*exec.Cmd, error) {
+
+	key, keyFile, err := d.luksKey(deviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	if keyFile == "" {
+		keyFile = "-"
+	}
+	args = append([]string{"-q", "--key-file", keyFile}, args...)
+	cmd := exec.Command("cryptsetup", args...)
+	if key != nil {
+		cmd.Stdin = bytes.NewReader(key)
+	}
+	return cmd, nil
+}
+
+// luksFormat encrypts the device with LUKS.
+func (d *driver) luksFormat(ctx types.Context, deviceName string) error {
+	args := []string{"luksFormat"}
+	args = append(args,
+		strings.Fields(d.config.GetString("linux.luks.format.args"))...)
+	args = append(args, deviceName)
+
+	cmd, err := d.luksCommand(deviceName, args...)
+	if err != nil {
+		return err
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return goof.WithFieldsE(goof.Fields{
+			"deviceName": deviceName,
+			"output":     string(out),
+		}, "error encrypting device", err)
+	}
+
+	ctx.WithFields(log.Fields{
+		"deviceName":  deviceName,
+		"keyProvider": d.luksKeyProviderName(),
+		"driverName":  driverName}).Info("encrypted device")
+	return nil
+}
+
+// luksOpen opens the device's mapping if it is not open and returns the path
+// to the mapping. An error is returned if the mapping is open for another
+// device, such as a copy of the volume that has the same LUKS UUID.
+func (d *driver) luksOpen(
+	ctx types.Context, deviceName string) (string, error) {
+
+	mapperName, err := luksMapperName(deviceName)
+	if err != nil {
+		return "", err
+	}
+	mapperPath := path.Join(luksMapperDir, mapperName)
+
+	if _, err := os.Stat(mapperPath); err == nil {
+		backingDevice, err := luksBackingDevice(mapperName)
+		if err != nil {
+			return "", err
+		}
+		if !isSameDevice(backingDevice, deviceName) {
+			return "", goof.WithFields(goof.Fields{
+				"deviceName":    deviceName,
+				"mapperPath":    mapperPath,
+				"backingDevice": backingDevice,
+			}, "encrypted device mapping is open for another device")
+		}
+		return mapperPath, nil
+	}
+
+	cmd, err := d.luksCommand(
+		deviceName, "luksOpen", deviceName, mapperName)
+	if err != nil {
+		return "", err
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return "", goof.WithFieldsE(goof.Fields{
+			"deviceName": deviceName,
+			"output":     string(out),
+		}, "error opening encrypted device", err)
+	}
+
+	ctx.WithFields(log.Fields{
+		"deviceName": deviceName,
+		"mapperPath": mapperPath,
+		"driverName": driverName}).Info("opened encrypted device")
+	return mapperPath, nil
+}
+
+// luksClose closes the device mapping at the path if it is open.
+func (d *driver) luksClose(ctx types.Context, mapperPath string) error {
+	if _, err := os.Stat(mapperPath); os.IsNotExist(err) {
+		return nil
+	}
+
+	out, err := exec.Command(
+		"cryptsetup", "luksClose", path.Base(mapperPath)).CombinedOutput()
+	if err != nil {
+		return goof.WithFieldsE(goof.Fields{
+			"mapperPath": mapperPath,
+			"output":     string(out),
+		}, "error closing encrypted device", err)
+	}
+
+	ctx.WithFields(log.Fields{
+		"mapperPath": mapperPath,
+		"driverName": driverName}).Info("closed encrypted device")
+	return nil
+}
+
+// luksResize grows the open mapping of the device to the size of the device
+// and returns the path to the mapping.
+func (d *driver) luksResize(
+	ctx types.Context, deviceName string) (string, error) {
+
+	mapperName, err := luksMapperName(deviceName)
+	if err != nil {
+		return "", err
+	}
+	cmd, err := d.luksCommand(deviceName, "resize", mapperName)
+	if err != nil {
+		return "", err
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return "", goof.WithFieldsE(goof.Fields{
+			"deviceName": deviceName,
+			"output":     string(out),
+		}, "error resizing encrypted device", err)
+	}
+	return path.Join(luksMapperDir, mapperName), nil
+}
+
+// recordLUKS records the encryption of the device in the operation's options
+// so that the caller is able to record it in the volume's fields.
+func (d *driver) recordLUKS(opts types.Store) {
+	if opts == nil {
+		return
+	}
+	opts.Set(types.VolumeFieldEncryption, luksEncryption)
+	opts.Set(types.VolumeFieldEncryptionKeyProvider, d.luksKeyProviderName())
+}
@@ -0,0 +1,177 @@
+// +build linux
+
+package linux
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	gofigCore "github.com/akutz/gofig"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/codedellemc/libstorage/api/utils"
+)
+
+func newLUKSTestDriver(t *testing.T, config string) *driver {
+	c := gofigCore.New()
+	if err := c.ReadConfig(bytes.NewReader([]byte(config))); err != nil {
+		t.Fatal(err)
+	}
+	return &driver{config: c}
+}
+
+func TestFileKeyProvider(t *testing.T) {
+	f, err := ioutil.TempFile("", "linux-luks-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	f.Close()
+
+	d := newLUKSTestDriver(t, fmt.Sprintf(`
+linux:
+  luks:
+    keyprovider: file
+    keyfile: %s
+`, f.Name()))
+
+	cmd, err := d.luksCommand("/dev/xvdb", "luksOpen", "/dev/xvdb", "name")
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{
+			"cryptsetup", "-q", "--key-file", f.Name(),
+			"luksOpen", "/dev/xvdb", "name",
+		}, cmd.Args)
+		assert.Nil(t, cmd.Stdin)
+	}
+
+	d = newLUKSTestDriver(t, `
+linux:
+  luks:
+    keyprovider: file
+    keyfile: /does/not/exist
+`)
+	_, err = d.luksCommand("/dev/xvdb", "luksOpen", "/dev/xvdb", "name")
+	assert.Error(t, err)
+}
+
+func TestEnvKeyProvider(t *testing.T) {
+	d := newLUKSTestDriver(t, `
+linux:
+  luks:
+    keyprovider: env
+    keyenv: LINUX_LUKS_TEST_KEY
+`)
+
+	os.Unsetenv("LINUX_LUKS_TEST_KEY")
+	_, _, err := d.luksKey("/dev/xvdb")
+	assert.Error(t, err)
+
+	os.Setenv("LINUX_LUKS_TEST_KEY", "secret")
+	defer os.Unsetenv("LINUX_LUKS_TEST_KEY")
+
+	cmd, err := d.luksCommand("/dev/xvdb", "luksFormat", "/dev/xvdb")
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{
+			"cryptsetup", "-q", "--key-file", "-", "luksFormat", "/dev/xvdb",
+		}, cmd.Args)
+		key, _ := ioutil.ReadAll(cmd.Stdin)
+		assert.Equal(t, "secret", string(key))
+	}
+}
+
+func TestExecKeyProvider(t *testing.T) {
+	d := newLUKSTestDriver(t, `
+linux:
+  luks:
+    keyprovider: exec
+    keyexec: echo secret
+`)
+
+	key, keyFile, err := d.luksKey("/dev/xvdb")
+	assert.NoError(t, err)
+	assert.Empty(t, keyFile)
+	assert.Equal(t, "secret /dev/xvdb", string(key))
+
+	d = newLUKSTestDriver(t, `
+linux:
+  luks:
+    keyprovider: exec
+    keyexec: "false"
+`)
+	_, _, err = d.luksKey("/dev/xvdb")
+	assert.Error(t, err)
+}
+
+func TestUnknownKeyProvider(t *testing.T) {
+	d := newLUKSTestDriver(t, `
+linux:
+  luks:
+    keyprovider: vault
+`)
+	_, _, err := d.luksKey("/dev/xvdb")
+	assert.Error(t, err)
+}
+
+func TestLUKSMapperPath(t *testing.T) {
+	const uuid = "5e1f2a4c-8b0d-4d9e-9c8a-2f6b7e3d1a90"
+
+	f, err := ioutil.TempFile("", "linux-luks-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	header := make([]byte, 512)
+	copy(header, luksMagic)
+	copy(header[luksUUIDOffset:], uuid)
+	if _, err := f.Write(header); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	mapperPath, err := luksMapperPath(f.Name())
+	assert.NoError(t, err)
+	assert.Equal(t, "/dev/mapper/libstorage-"+uuid, mapperPath)
+	assert.True(t, isLUKSMapperPath(mapperPath))
+	assert.False(t, isLUKSMapperPath("/dev/mapper/root"))
+	assert.False(t, isLUKSMapperPath("/dev/xvdb"))
+
+	// a device without a luks header has no mapping
+	_, err = readLUKSUUID(bytes.NewReader(make([]byte, 512)), "/dev/xvdb")
+	assert.Error(t, err)
+	header = make([]byte, 512)
+	copy(header, luksMagic)
+	_, err = readLUKSUUID(bytes.NewReader(header), "/dev/xvdb")
+	assert.Error(t, err)
+}
+
+func TestParseLUKSStatusDevice(t *testing.T) {
+	out := []byte(`/dev/mapper/libstorage-5e1f2a4c is active.
+  type:    LUKS1
+  cipher:  aes-xts-plain64
+  keysize: 256 bits
+  device:  /dev/xvdf
+  offset:  4096 sectors
+  size:    2093056 sectors
+  mode:    read/write
+`)
+	assert.Equal(t, "/dev/xvdf", parseLUKSStatusDevice(out))
+	assert.Empty(t, parseLUKSStatusDevice([]byte("is inactive.\n")))
+}
+
+func TestRecordLUKS(t *testing.T) {
+	d := newLUKSTestDriver(t, `
+linux:
+  luks:
+    keyprovider: env
+`)
+	opts := utils.NewStore()
+	d.recordLUKS(opts)
+	assert.Equal(t, "luks", opts.GetString("encryption"))
+	assert.Equal(t, "env", opts.GetString("encryptionKeyProvider"))
+
+	// a nil store is ignored
+	d.recordLUKS(nil)
+}
@@ -0,0 +1,109 @@
+// +build !libstorage_storage_driver libstorage_storage_driver_cinder
+
+package cinder
+
+import (
+	gofigCore "github.com/akutz/gofig"
+	gofig "github.com/akutz/gofig/types"
+
+	"github.com/codedellemc/libstorage/api/registry"
+)
+
+const (
+	// Name is the provider's name.
+	Name = "cinder"
+
+	// InstanceIDFieldAvailabilityZone is the key to retrieve the availability
+	// zone value from the InstanceID Field map.
+	InstanceIDFieldAvailabilityZone = "availabilityZone"
+
+	// DefaultEndpointType is the default catalog endpoint type.
+	DefaultEndpointType = "public"
+
+	// DefaultStatusTimeout is the default amount of time to wait for a volume
+	// or snapshot to reach its expected status.
+	DefaultStatusTimeout = "2m"
+
+	// DefaultMetadataURL is the default URL of the instance metadata.
+	DefaultMetadataURL = "http://169.254.169.254/openstack/latest/" +
+		"meta_data.json"
+
+	// DefaultConfigDrivePath is the default path of the config drive.
+	DefaultConfigDrivePath = "/dev/disk/by-label/config-2"
+
+	// AuthURL is a key constant.
+	AuthURL = "authURL"
+
+	// UserID is a key constant.
+	UserID = "userID"
+
+	// UserName is a key constant.
+	UserName = "userName"
+
+	// Password is a key constant.
+	Password = "password"
+
+	// ProjectID is a key constant.
+	ProjectID = "projectID"
+
+	// ProjectName is a key constant.
+	ProjectName = "projectName"
+
+	// DomainID is a key constant.
+	DomainID = "domainID"
+
+	// DomainName is a key constant.
+	DomainName = "domainName"
+
+	// RegionName is a key constant.
+	RegionName = "regionName"
+
+	// EndpointType is a key constant.
+	EndpointType = "endpointType"
+
+	// Insecure is a key constant.
+	Insecure = "insecure"
+
+	// AvailabilityZone is a key constant.
+	AvailabilityZone = "availabilityZone"
+
+	// VolumeType is a key constant.
+	VolumeType = "volumeType"
+
+	// StatusTimeout is a key constant.
+	StatusTimeout = "statusTimeout"
+
+	// MetadataURL is a key constant.
+	MetadataURL = "metadataURL"
+
+	// ConfigDrivePath is a key constant.
+	ConfigDrivePath = "configDrivePath"
+)
+
+func init() {
+	r := gofigCore.NewRegistration("Cinder")
+	r.Key(gofig.String, "", "", "The Keystone URL", Name+"."+AuthURL)
+	r.Key(gofig.String, "", "", "", Name+"."+UserID)
+	r.Key(gofig.String, "", "", "", Name+"."+UserName)
+	r.Key(gofig.String, "", "", "", Name+"."+Password)
+	r.Key(gofig.String, "", "", "", Name+"."+ProjectID)
+	r.Key(gofig.String, "", "", "", Name+"."+ProjectName)
+	r.Key(gofig.String, "", "", "", Name+"."+DomainID)
+	r.Key(gofig.String, "", "", "", Name+"."+DomainName)
+	r.Key(gofig.String, "", "", "", Name+"."+RegionName)
+	r.Key(gofig.String, "", DefaultEndpointType,
+		"The catalog endpoint type", Name+"."+EndpointType)
+	r.Key(gofig.Bool, "", false,
+		"Skip TLS verification", Name+"."+Insecure)
+	r.Key(gofig.String, "", "",
+		"The default availability zone", Name+"."+AvailabilityZone)
+	r.Key(gofig.String, "", "",
+		"The default volume type", Name+"."+VolumeType)
+	r.Key(gofig.String, "", DefaultStatusTimeout, "", Name+"."+StatusTimeout)
+	r.Key(gofig.String, "", DefaultMetadataURL, "", Name+"."+MetadataURL)
+	r.Key(gofig.String, "", DefaultConfigDrivePath, "",
+		Name+"."+ConfigDrivePath)
+	gofigCore.Register(r)
+
+	registry.RegisterSecretConfigKeys(Name + "." + Password)
+}
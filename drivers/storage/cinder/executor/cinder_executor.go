@@ -0,0 +1,111 @@
+// +build !libstorage_storage_executor libstorage_storage_executor_cinder
+
+package executor
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	gofig "github.com/akutz/gofig/types"
+	"github.com/akutz/goof"
+
+	"github.com/codedellemc/libstorage/api/registry"
+	"github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/drivers/storage/cinder"
+	cinderUtils "github.com/codedellemc/libstorage/drivers/storage/cinder/utils"
+)
+
+const (
+	diskByIDPath = "/dev/disk/by-id"
+
+	// virtioPrefix prefixes the names of the links to virtio disks. The
+	// rest of a link's name is the disk's serial number, which is the
+	// beginning of the ID of the Cinder volume attached as the disk.
+	virtioPrefix = "virtio-"
+)
+
+// driver is the storage executor for the cinder storage driver.
+type driver struct {
+	config gofig.Config
+}
+
+func init() {
+	registry.RegisterStorageExecutor(cinder.Name, newDriver)
+}
+
+func newDriver() types.StorageExecutor {
+	return &driver{}
+}
+
+func (d *driver) Init(ctx types.Context, config gofig.Config) error {
+	d.config = config
+	return nil
+}
+
+func (d *driver) Name() string {
+	return cinder.Name
+}
+
+// Supported returns a flag indicating whether or not the platform
+// implementing the executor is valid for the host on which the executor
+// resides.
+func (d *driver) Supported(
+	ctx types.Context,
+	opts types.Store) (bool, error) {
+
+	return cinderUtils.IsOpenStackInstance(ctx, d.config)
+}
+
+// InstanceID returns the instance ID from the config drive or the metadata
+// service.
+func (d *driver) InstanceID(
+	ctx types.Context,
+	opts types.Store) (*types.InstanceID, error) {
+
+	return cinderUtils.InstanceID(ctx, d.config)
+}
+
+// NextDevice returns the next available device. Nova chooses the device
+// name when a volume is attached, so the next device is not predicted.
+func (d *driver) NextDevice(
+	ctx types.Context,
+	opts types.Store) (string, error) {
+
+	return "", types.ErrNotImplemented
+}
+
+// LocalDevices returns a map of the serial numbers of the local virtio
+// disks to their device paths.
+func (d *driver) LocalDevices(
+	ctx types.Context,
+	opts *types.LocalDevicesOpts) (*types.LocalDevices, error) {
+
+	files, err := ioutil.ReadDir(diskByIDPath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, goof.WithError("error reading "+diskByIDPath, err)
+	}
+
+	devMap := map[string]string{}
+	for _, f := range files {
+		name := f.Name()
+		if !strings.HasPrefix(name, virtioPrefix) ||
+			strings.Contains(name, "-part") {
+			continue
+		}
+		devPath, err := filepath.EvalSymlinks(path.Join(diskByIDPath, name))
+		if err != nil {
+			continue
+		}
+		devMap[strings.TrimPrefix(name, virtioPrefix)] = devPath
+	}
+
+	ld := &types.LocalDevices{Driver: d.Name()}
+	if len(devMap) > 0 {
+		ld.DeviceMap = devMap
+	}
+
+	return ld, nil
+}
@@ -0,0 +1,812 @@
+// +build !libstorage_storage_driver libstorage_storage_driver_cinder
+
+package storage
+
+import (
+	"crypto/tls"
+	"net/http"
+	"strings"
+	"time"
+
+	gofig "github.com/akutz/gofig/types"
+	"github.com/akutz/goof"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/registry"
+	"github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/api/utils"
+	"github.com/codedellemc/libstorage/drivers/storage/cinder"
+
+	"github.com/rackspace/gophercloud"
+	"github.com/rackspace/gophercloud/openstack"
+	"github.com/rackspace/gophercloud/openstack/blockstorage/v1/snapshots"
+	"github.com/rackspace/gophercloud/openstack/blockstorage/v1/volumes"
+	"github.com/rackspace/gophercloud/openstack/compute/v2/extensions/volumeattach"
+)
+
+const (
+	// serialLength is the length of a virtio disk's serial number. Nova
+	// sets the serial number of an attached volume's disk to the beginning
+	// of the volume's ID.
+	serialLength = 20
+
+	minPollInterval = time.Duration(100 * time.Millisecond)
+	maxPollInterval = time.Duration(2 * time.Second)
+)
+
+type driver struct {
+	config       gofig.Config
+	compute      *gophercloud.ServiceClient
+	blockStorage *gophercloud.ServiceClient
+}
+
+func init() {
+	registry.RegisterStorageDriver(cinder.Name, newDriver)
+}
+
+func newDriver() types.StorageDriver {
+	return &driver{}
+}
+
+// Name returns the name of the driver
+func (d *driver) Name() string {
+	return cinder.Name
+}
+
+// Init initializes the driver.
+func (d *driver) Init(ctx types.Context, config gofig.Config) error {
+	d.config = config
+
+	// the project is the tenant of the identity v2.0 API. With the v3 API
+	// the domain qualifies both the user's name and the project's name.
+	authOpts := gophercloud.AuthOptions{
+		IdentityEndpoint: d.getString(cinder.AuthURL),
+		UserID:           d.getString(cinder.UserID),
+		Username:         d.getString(cinder.UserName),
+		Password:         d.getString(cinder.Password),
+		TenantID:         d.getString(cinder.ProjectID),
+		TenantName:       d.getString(cinder.ProjectName),
+		DomainID:         d.getString(cinder.DomainID),
+		DomainName:       d.getString(cinder.DomainName),
+		AllowReauth:      true,
+	}
+	endpointOpts := gophercloud.EndpointOpts{
+		Region: d.getString(cinder.RegionName),
+		Availability: gophercloud.Availability(
+			d.getString(cinder.EndpointType)),
+	}
+
+	fields := map[string]interface{}{
+		"provider":     cinder.Name,
+		"moduleName":   cinder.Name,
+		"authURL":      authOpts.IdentityEndpoint,
+		"userID":       authOpts.UserID,
+		"userName":     authOpts.Username,
+		"projectID":    authOpts.TenantID,
+		"projectName":  authOpts.TenantName,
+		"domainID":     authOpts.DomainID,
+		"domainName":   authOpts.DomainName,
+		"regionName":   endpointOpts.Region,
+		"endpointType": endpointOpts.Availability,
+	}
+
+	ctx.Info("initializing driver: ", fields)
+
+	provider, err := openstack.NewClient(authOpts.IdentityEndpoint)
+	if err != nil {
+		return goof.WithFieldsE(fields, "invalid auth url", err)
+	}
+	if d.config.GetBool(cinder.Name + "." + cinder.Insecure) {
+		provider.HTTPClient = http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+			},
+		}
+	}
+	if err := openstack.Authenticate(provider, authOpts); err != nil {
+		return goof.WithFieldsE(fields, "error authenticating", err)
+	}
+
+	if d.compute, err = openstack.NewComputeV2(
+		provider, endpointOpts); err != nil {
+		return goof.WithFieldsE(fields, "error getting compute client", err)
+	}
+	if d.blockStorage, err = openstack.NewBlockStorageV1(
+		provider, endpointOpts); err != nil {
+		return goof.WithFieldsE(
+			fields, "error getting block storage client", err)
+	}
+
+	ctx.WithFields(fields).Info("storage driver initialized")
+	return nil
+}
+
+func (d *driver) getString(key string) string {
+	return d.config.GetString(cinder.Name + "." + key)
+}
+
+func (d *driver) statusTimeout() time.Duration {
+	timeout, err := time.ParseDuration(d.getString(cinder.StatusTimeout))
+	if err != nil {
+		timeout, _ = time.ParseDuration(cinder.DefaultStatusTimeout)
+	}
+	return timeout
+}
+
+// NextDeviceInfo returns the information about the driver's next available
+// device workflow. Nova chooses the device name when a volume is attached.
+func (d *driver) NextDeviceInfo(
+	ctx types.Context) (*types.NextDeviceInfo, error) {
+	return &types.NextDeviceInfo{Ignore: true}, nil
+}
+
+// Type returns the type of storage the driver provides.
+func (d *driver) Type(ctx types.Context) (types.StorageType, error) {
+	return types.Block, nil
+}
+
+// InstanceInspect returns an instance.
+func (d *driver) InstanceInspect(
+	ctx types.Context,
+	opts types.Store) (*types.Instance, error) {
+
+	iid := context.MustInstanceID(ctx)
+	return &types.Instance{
+		Name:         iid.ID,
+		Region:       d.getString(cinder.RegionName),
+		InstanceID:   iid,
+		ProviderName: iid.Driver,
+	}, nil
+}
+
+// Volumes returns all volumes or a filtered list of volumes.
+func (d *driver) Volumes(
+	ctx types.Context,
+	opts *types.VolumesOpts) ([]*types.Volume, error) {
+
+	vols, err := d.getVolumes()
+	if err != nil {
+		return nil, goof.WithError("error getting volumes", err)
+	}
+
+	var volumes []*types.Volume
+	for i := range vols {
+		volumes = append(
+			volumes, d.toTypesVolume(ctx, &vols[i], opts.Attachments))
+	}
+	return volumes, nil
+}
+
+// VolumeInspect inspects a single volume.
+func (d *driver) VolumeInspect(
+	ctx types.Context,
+	volumeID string,
+	opts *types.VolumeInspectOpts) (*types.Volume, error) {
+
+	vol, err := volumes.Get(d.blockStorage, volumeID).Extract()
+	if err != nil {
+		if isNotFound(err) {
+			return nil, utils.NewNotFoundError(volumeID)
+		}
+		return nil, goof.WithFieldE(
+			"volumeID", volumeID, "error getting volume", err)
+	}
+	return d.toTypesVolume(ctx, vol, opts.Attachments), nil
+}
+
+var errVolNameExists = goof.New("volume name already exists")
+
+// VolumeCreate creates a new volume.
+func (d *driver) VolumeCreate(
+	ctx types.Context,
+	volumeName string,
+	opts *types.VolumeCreateOpts) (*types.Volume, error) {
+
+	fields := map[string]interface{}{
+		"driverName": d.Name(),
+		"volumeName": volumeName,
+		"opts":       opts,
+	}
+
+	ctx.WithFields(fields).Debug("creating volume")
+
+	if opts.Size == nil || *opts.Size <= 0 {
+		return nil, goof.WithFields(fields, "missing volume size")
+	}
+	if err := d.assertNoVolume(volumeName); err != nil {
+		return nil, goof.WithFieldsE(fields, "error creating volume", err)
+	}
+
+	createOpts := &volumes.CreateOpts{
+		Name:         volumeName,
+		Size:         int(*opts.Size),
+		VolumeType:   d.getString(cinder.VolumeType),
+		Availability: d.getString(cinder.AvailabilityZone),
+	}
+	if opts.Type != nil && *opts.Type != "" {
+		createOpts.VolumeType = *opts.Type
+	}
+	if opts.AvailabilityZone != nil && *opts.AvailabilityZone != "" {
+		createOpts.Availability = *opts.AvailabilityZone
+	}
+
+	return d.createVolume(ctx, fields, createOpts)
+}
+
+// VolumeCreateFromSnapshot creates a new volume from an existing snapshot.
+// The volume is the size of the snapshot unless a larger size is requested.
+func (d *driver) VolumeCreateFromSnapshot(
+	ctx types.Context,
+	snapshotID, volumeName string,
+	opts *types.VolumeCreateOpts) (*types.Volume, error) {
+
+	fields := map[string]interface{}{
+		"driverName": d.Name(),
+		"snapshotID": snapshotID,
+		"volumeName": volumeName,
+		"opts":       opts,
+	}
+
+	ctx.WithFields(fields).Debug("creating volume from snapshot")
+
+	if _, err := d.SnapshotInspect(ctx, snapshotID, nil); err != nil {
+		return nil, err
+	}
+	if err := d.assertNoVolume(volumeName); err != nil {
+		return nil, goof.WithFieldsE(fields, "error creating volume", err)
+	}
+
+	// the volume type and availability zone default to the snapshot's
+	// rather than the configured defaults
+	createOpts := &volumes.CreateOpts{
+		Name:       volumeName,
+		SnapshotID: snapshotID,
+	}
+	if opts.Size != nil {
+		createOpts.Size = int(*opts.Size)
+	}
+	if opts.Type != nil {
+		createOpts.VolumeType = *opts.Type
+	}
+	if opts.AvailabilityZone != nil {
+		createOpts.Availability = *opts.AvailabilityZone
+	}
+
+	return d.createVolume(ctx, fields, createOpts)
+}
+
+// VolumeCopy copies an existing volume.
+func (d *driver) VolumeCopy(
+	ctx types.Context,
+	volumeID, volumeName string,
+	opts types.Store) (*types.Volume, error) {
+
+	fields := map[string]interface{}{
+		"driverName": d.Name(),
+		"volumeID":   volumeID,
+		"volumeName": volumeName,
+	}
+
+	ctx.WithFields(fields).Debug("copying volume")
+
+	if _, err := d.VolumeInspect(
+		ctx, volumeID, &types.VolumeInspectOpts{}); err != nil {
+		return nil, err
+	}
+	if err := d.assertNoVolume(volumeName); err != nil {
+		return nil, goof.WithFieldsE(fields, "error copying volume", err)
+	}
+
+	return d.createVolume(ctx, fields, &volumes.CreateOpts{
+		Name:        volumeName,
+		SourceVolID: volumeID,
+	})
+}
+
+// VolumeSnapshot snapshots a volume. Attached volumes are snapshotted as
+// well, so the snapshot of an attached volume is only crash consistent.
+func (d *driver) VolumeSnapshot(
+	ctx types.Context,
+	volumeID, snapshotName string,
+	opts types.Store) (*types.Snapshot, error) {
+
+	fields := map[string]interface{}{
+		"driverName":   d.Name(),
+		"volumeID":     volumeID,
+		"snapshotName": snapshotName,
+	}
+
+	ctx.WithFields(fields).Debug("snapshotting volume")
+
+	if _, err := d.VolumeInspect(
+		ctx, volumeID, &types.VolumeInspectOpts{}); err != nil {
+		return nil, err
+	}
+
+	snap, err := snapshots.Create(d.blockStorage, &snapshots.CreateOpts{
+		VolumeID: volumeID,
+		Name:     snapshotName,
+		Force:    true,
+	}).Extract()
+	if err != nil {
+		return nil, goof.WithFieldsE(
+			fields, "error snapshotting volume", err)
+	}
+
+	snap, err = d.waitSnapshotStatus(ctx, snap.ID, "available")
+	if err != nil {
+		return nil, goof.WithFieldsE(
+			fields, "error waiting for snapshot", err)
+	}
+
+	return toTypesSnapshot(snap), nil
+}
+
+// VolumeRemove removes a volume.
+func (d *driver) VolumeRemove(
+	ctx types.Context,
+	volumeID string,
+	opts types.Store) error {
+
+	fields := map[string]interface{}{
+		"provider": d.Name(),
+		"volumeID": volumeID,
+	}
+
+	if err := volumes.Delete(d.blockStorage, volumeID).Err; err != nil {
+		if isNotFound(err) {
+			return utils.NewNotFoundError(volumeID)
+		}
+		return goof.WithFieldsE(fields, "error removing volume", err)
+	}
+
+	if err := d.waitVolumeRemoved(ctx, volumeID); err != nil {
+		return goof.WithFieldsE(
+			fields, "error waiting for volume removal", err)
+	}
+
+	return nil
+}
+
+// VolumeResize grows a volume.
+func (d *driver) VolumeResize(
+	ctx types.Context,
+	volumeID string,
+	opts *types.VolumeResizeOpts) (*types.Volume, error) {
+
+	fields := map[string]interface{}{
+		"provider": d.Name(),
+		"volumeID": volumeID,
+		"size":     opts.Size,
+	}
+
+	vol, err := d.VolumeInspect(ctx, volumeID, &types.VolumeInspectOpts{})
+	if err != nil {
+		return nil, err
+	}
+	if opts.Size < vol.Size {
+		return nil, goof.WithFields(
+			fields, "volume cannot be shrunk")
+	}
+	if opts.Size > vol.Size {
+		if err := d.extendVolume(volumeID, opts.Size); err != nil {
+			return nil, goof.WithFieldsE(
+				fields, "error resizing volume", err)
+		}
+		// the volume is "extending" until it returns to the status it had
+		// before the resize
+		if _, err := d.waitVolumeStatus(
+			ctx, volumeID, vol.Status); err != nil {
+			return nil, goof.WithFieldsE(
+				fields, "error waiting for volume resize", err)
+		}
+	}
+
+	return d.VolumeInspect(ctx, volumeID, &types.VolumeInspectOpts{
+		Attachments: types.VolAttReq,
+		Opts:        opts.Opts,
+	})
+}
+
+// VolumeUpdate renames a volume and sets or removes its metadata. A volume's
+// metadata is presented as the volume's fields.
+func (d *driver) VolumeUpdate(
+	ctx types.Context,
+	volumeID string,
+	opts *types.VolumeUpdateOpts) (*types.Volume, error) {
+
+	fields := map[string]interface{}{
+		"provider": d.Name(),
+		"volumeID": volumeID,
+	}
+
+	if _, err := d.VolumeInspect(
+		ctx, volumeID, &types.VolumeInspectOpts{}); err != nil {
+		return nil, err
+	}
+
+	if opts.Name != nil {
+		if err := volumes.Update(
+			d.blockStorage, volumeID,
+			&volumes.UpdateOpts{Name: *opts.Name}).Err; err != nil {
+			return nil, goof.WithFieldsE(
+				fields, "error renaming volume", err)
+		}
+	}
+
+	if len(opts.Fields) > 0 {
+		if err := d.setVolumeMetadata(volumeID, opts.Fields); err != nil {
+			return nil, goof.WithFieldsE(
+				fields, "error setting metadata", err)
+		}
+	}
+
+	for _, k := range opts.RemoveFields {
+		err := d.deleteVolumeMetadata(volumeID, k)
+		if err != nil && !isNotFound(err) {
+			return nil, goof.WithFieldsE(
+				fields, "error removing metadata", err)
+		}
+	}
+
+	return d.VolumeInspect(ctx, volumeID, &types.VolumeInspectOpts{
+		Attachments: types.VolAttReq,
+		Opts:        opts.Opts,
+	})
+}
+
+var errVolAlreadyAttached = goof.New("volume already attached to a host")
+
+// VolumeAttach attaches a volume and provides a token clients can use
+// to validate that device has appeared locally.
+func (d *driver) VolumeAttach(
+	ctx types.Context,
+	volumeID string,
+	opts *types.VolumeAttachOpts) (*types.Volume, string, error) {
+
+	iid := context.MustInstanceID(ctx)
+	fields := map[string]interface{}{
+		"provider":   d.Name(),
+		"volumeID":   volumeID,
+		"instanceID": iid.ID,
+	}
+
+	vol, err := d.VolumeInspect(
+		ctx, volumeID, &types.VolumeInspectOpts{Attachments: types.VolAttReq})
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(vol.Attachments) > 0 {
+		if !opts.Force {
+			return nil, "", errVolAlreadyAttached
+		}
+		if _, err := d.VolumeDetach(ctx, volumeID, &types.VolumeDetachOpts{
+			Force: true,
+			Opts:  opts.Opts,
+		}); err != nil {
+			return nil, "", goof.WithFieldsE(
+				fields, "error detaching volume", err)
+		}
+	}
+
+	if _, err := volumeattach.Create(
+		d.compute, iid.ID,
+		&volumeattach.CreateOpts{VolumeID: volumeID}).Extract(); err != nil {
+		return nil, "", goof.WithFieldsE(
+			fields, "error attaching volume", err)
+	}
+
+	if _, err := d.waitVolumeStatus(ctx, volumeID, "in-use"); err != nil {
+		return nil, "", goof.WithFieldsE(
+			fields, "error waiting for volume attach", err)
+	}
+
+	attachedVol, err := d.VolumeInspect(
+		ctx, volumeID, &types.VolumeInspectOpts{
+			Attachments: types.VolAttReqTrue,
+			Opts:        opts.Opts,
+		})
+	if err != nil {
+		return nil, "", goof.WithError("error getting volume", err)
+	}
+
+	// the token is the serial number of the volume's disk, which is a key of
+	// the executor's local devices
+	return attachedVol, volumeSerial(volumeID), nil
+}
+
+var (
+	errVolAlreadyDetached = goof.New("volume already detached")
+	errVolNotAttachedHere = goof.New("volume not attached to instance")
+)
+
+// VolumeDetach detaches a volume from the instance in the context. A forced
+// detach detaches the volume from all instances.
+func (d *driver) VolumeDetach(
+	ctx types.Context,
+	volumeID string,
+	opts *types.VolumeDetachOpts) (*types.Volume, error) {
+
+	iid := context.MustInstanceID(ctx)
+	fields := map[string]interface{}{
+		"provider":   d.Name(),
+		"volumeID":   volumeID,
+		"instanceID": iid.ID,
+	}
+
+	vol, err := volumes.Get(d.blockStorage, volumeID).Extract()
+	if err != nil {
+		if isNotFound(err) {
+			return nil, utils.NewNotFoundError(volumeID)
+		}
+		return nil, goof.WithFieldsE(fields, "error getting volume", err)
+	}
+
+	if len(vol.Attachments) == 0 {
+		return nil, errVolAlreadyDetached
+	}
+
+	detached := false
+	for _, serverID := range attachedServers(vol) {
+		if serverID != iid.ID && !opts.Force {
+			continue
+		}
+		if err := volumeattach.Delete(
+			d.compute, serverID, volumeID).Err; err != nil {
+			return nil, goof.WithFieldsE(
+				fields, "error detaching volume", err)
+		}
+		detached = true
+	}
+	if !detached {
+		return nil, errVolNotAttachedHere
+	}
+
+	if _, err := d.waitVolumeStatus(ctx, volumeID, "available"); err != nil {
+		return nil, goof.WithFieldsE(
+			fields, "error waiting for volume detach", err)
+	}
+
+	ctx.Info("detached volume", volumeID)
+
+	return d.VolumeInspect(ctx, volumeID, &types.VolumeInspectOpts{
+		Attachments: types.VolAttReqTrue,
+		Opts:        opts.Opts,
+	})
+}
+
+// assertNoVolume returns an error if a volume with the given name exists.
+// Cinder permits volumes with the same name, but volumes are located by name.
+func (d *driver) assertNoVolume(name string) error {
+	vols, err := d.getVolumes()
+	if err != nil {
+		return err
+	}
+	for _, v := range vols {
+		if v.Name == name {
+			return errVolNameExists
+		}
+	}
+	return nil
+}
+
+// createVolume creates a volume and waits for it to become available.
+func (d *driver) createVolume(
+	ctx types.Context,
+	fields map[string]interface{},
+	opts *volumes.CreateOpts) (*types.Volume, error) {
+
+	vol, err := volumes.Create(d.blockStorage, opts).Extract()
+	if err != nil {
+		return nil, goof.WithFieldsE(fields, "error creating volume", err)
+	}
+
+	if _, err := d.waitVolumeStatus(ctx, vol.ID, "available"); err != nil {
+		return nil, goof.WithFieldsE(
+			fields, "error waiting for volume", err)
+	}
+
+	return d.VolumeInspect(ctx, vol.ID, &types.VolumeInspectOpts{
+		Attachments: types.VolAttReqTrue,
+	})
+}
+
+// getVolumes returns all of the project's volumes.
+func (d *driver) getVolumes() ([]volumes.Volume, error) {
+	page, err := volumes.List(d.blockStorage, &volumes.ListOpts{}).AllPages()
+	if err != nil {
+		return nil, err
+	}
+	return volumes.ExtractVolumes(page)
+}
+
+// extendVolume grows a volume with the volume's "os-extend" action.
+func (d *driver) extendVolume(volumeID string, size int64) error {
+	_, err := d.blockStorage.Post(
+		d.blockStorage.ServiceURL("volumes", volumeID, "action"),
+		map[string]interface{}{
+			"os-extend": map[string]interface{}{"new_size": size},
+		},
+		nil,
+		&gophercloud.RequestOpts{OkCodes: []int{202}})
+	return err
+}
+
+// setVolumeMetadata sets the given keys of a volume's metadata. The volume's
+// other keys are left as they are.
+func (d *driver) setVolumeMetadata(
+	volumeID string, metadata map[string]string) error {
+
+	_, err := d.blockStorage.Post(
+		d.blockStorage.ServiceURL("volumes", volumeID, "metadata"),
+		map[string]interface{}{"metadata": metadata},
+		nil,
+		&gophercloud.RequestOpts{OkCodes: []int{200}})
+	return err
+}
+
+// deleteVolumeMetadata removes a key from a volume's metadata.
+func (d *driver) deleteVolumeMetadata(volumeID, key string) error {
+	_, err := d.blockStorage.Delete(
+		d.blockStorage.ServiceURL("volumes", volumeID, "metadata", key),
+		&gophercloud.RequestOpts{OkCodes: []int{200}})
+	return err
+}
+
+// attachedServers returns the IDs of the servers to which a volume is
+// attached.
+func attachedServers(vol *volumes.Volume) []string {
+	var serverIDs []string
+	for _, att := range vol.Attachments {
+		if serverID, ok := att["server_id"].(string); ok && serverID != "" {
+			serverIDs = append(serverIDs, serverID)
+		}
+	}
+	return serverIDs
+}
+
+// isNotFound returns a flag indicating whether the error is the response to
+// a request for a resource that does not exist.
+func isNotFound(err error) bool {
+	if e, ok := err.(*gophercloud.UnexpectedResponseCodeError); ok {
+		return e.Actual == http.StatusNotFound
+	}
+	return false
+}
+
+// volumeSerial returns the serial number of the disk of an attached volume.
+func volumeSerial(volumeID string) string {
+	if len(volumeID) > serialLength {
+		return volumeID[:serialLength]
+	}
+	return volumeID
+}
+
+func (d *driver) toTypesVolume(
+	ctx types.Context,
+	vol *volumes.Volume,
+	attachments types.VolumeAttachmentsTypes) *types.Volume {
+
+	volume := &types.Volume{
+		Name:             vol.Name,
+		ID:               vol.ID,
+		AvailabilityZone: vol.AvailabilityZone,
+		Status:           vol.Status,
+		Type:             vol.VolumeType,
+		Size:             int64(vol.Size),
+	}
+
+	if len(vol.Metadata) > 0 {
+		volume.Fields = map[string]string{}
+		for k, v := range vol.Metadata {
+			volume.Fields[k] = v
+		}
+	}
+
+	if !attachments.Requested() {
+		return volume
+	}
+
+	// a device name is only known for the local instance's attachments
+	var (
+		iid, _ = context.InstanceID(ctx)
+		ld, _  = context.LocalDevices(ctx)
+	)
+
+	for _, serverID := range attachedServers(vol) {
+		deviceName := ""
+		if attachments.Devices() && ld != nil &&
+			iid != nil && iid.ID == serverID {
+			deviceName = ld.DeviceMap[volumeSerial(vol.ID)]
+		}
+		volume.Attachments = append(volume.Attachments,
+			&types.VolumeAttachment{
+				VolumeID: vol.ID,
+				InstanceID: &types.InstanceID{
+					ID:     serverID,
+					Driver: d.Name(),
+				},
+				DeviceName: deviceName,
+				Status:     vol.Status,
+			})
+	}
+
+	return volume
+}
+
+// waitVolumeStatus polls a volume until it has the given status. An error is
+// returned if the volume enters an error status.
+func (d *driver) waitVolumeStatus(
+	ctx types.Context,
+	volumeID, status string) (*volumes.Volume, error) {
+
+	var vol *volumes.Volume
+	err := d.poll(ctx, func() (bool, error) {
+		var err error
+		vol, err = volumes.Get(d.blockStorage, volumeID).Extract()
+		if err != nil {
+			return false, err
+		}
+		if isErrorStatus(vol.Status) {
+			return false, goof.WithField(
+				"status", vol.Status, "volume entered error status")
+		}
+		return vol.Status == status, nil
+	})
+	return vol, err
+}
+
+// waitVolumeRemoved polls a volume until it no longer exists.
+func (d *driver) waitVolumeRemoved(ctx types.Context, volumeID string) error {
+	return d.poll(ctx, func() (bool, error) {
+		vol, err := volumes.Get(d.blockStorage, volumeID).Extract()
+		if err != nil {
+			if isNotFound(err) {
+				return true, nil
+			}
+			return false, err
+		}
+		if isErrorStatus(vol.Status) {
+			return false, goof.WithField(
+				"status", vol.Status, "volume entered error status")
+		}
+		return false, nil
+	})
+}
+
+// isErrorStatus returns a flag indicating whether the status is one of
+// Cinder's error statuses, ex. "error" or "error_extending".
+func isErrorStatus(status string) bool {
+	return strings.HasPrefix(status, "error")
+}
+
+var errStatusTimeout = goof.New("timed out waiting for status")
+
+// poll calls done with an increasing interval until it returns true or an
+// error, or until the status timeout expires.
+func (d *driver) poll(ctx types.Context, done func() (bool, error)) error {
+	timeout := time.NewTimer(d.statusTimeout())
+	defer timeout.Stop()
+
+	for interval := minPollInterval; ; interval *= 2 {
+		ok, err := done()
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+
+		if interval > maxPollInterval {
+			interval = maxPollInterval
+		}
+		select {
+		case <-time.After(interval):
+		case <-timeout.C:
+			return errStatusTimeout
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
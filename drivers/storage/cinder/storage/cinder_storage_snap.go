@@ -0,0 +1,159 @@
+// +build !libstorage_storage_driver libstorage_storage_driver_cinder
+
+package storage
+
+import (
+	"time"
+
+	"github.com/akutz/goof"
+
+	"github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/api/utils"
+
+	"github.com/rackspace/gophercloud/openstack/blockstorage/v1/snapshots"
+)
+
+// Snapshots returns all snapshots.
+func (d *driver) Snapshots(
+	ctx types.Context,
+	opts types.Store) ([]*types.Snapshot, error) {
+
+	page, err := snapshots.List(
+		d.blockStorage, &snapshots.ListOpts{}).AllPages()
+	if err != nil {
+		return nil, goof.WithError("error getting snapshots", err)
+	}
+	snaps, err := snapshots.ExtractSnapshots(page)
+	if err != nil {
+		return nil, goof.WithError("error getting snapshots", err)
+	}
+
+	var snapshots []*types.Snapshot
+	for i := range snaps {
+		snapshots = append(snapshots, toTypesSnapshot(&snaps[i]))
+	}
+	return snapshots, nil
+}
+
+// SnapshotInspect inspects a single snapshot.
+func (d *driver) SnapshotInspect(
+	ctx types.Context,
+	snapshotID string,
+	opts types.Store) (*types.Snapshot, error) {
+
+	snap, err := snapshots.Get(d.blockStorage, snapshotID).Extract()
+	if err != nil {
+		if isNotFound(err) {
+			return nil, utils.NewNotFoundError(snapshotID)
+		}
+		return nil, goof.WithFieldE(
+			"snapshotID", snapshotID, "error getting snapshot", err)
+	}
+	return toTypesSnapshot(snap), nil
+}
+
+// SnapshotCopy copies an existing snapshot (not supported by Cinder).
+func (d *driver) SnapshotCopy(
+	ctx types.Context,
+	snapshotID, snapshotName, destinationID string,
+	opts types.Store) (*types.Snapshot, error) {
+
+	return nil, types.ErrNotImplemented
+}
+
+// SnapshotRemove removes a snapshot.
+func (d *driver) SnapshotRemove(
+	ctx types.Context,
+	snapshotID string,
+	opts types.Store) error {
+
+	fields := map[string]interface{}{
+		"provider":   d.Name(),
+		"snapshotID": snapshotID,
+	}
+
+	if err := snapshots.Delete(d.blockStorage, snapshotID).Err; err != nil {
+		if isNotFound(err) {
+			return utils.NewNotFoundError(snapshotID)
+		}
+		return goof.WithFieldsE(fields, "error removing snapshot", err)
+	}
+
+	err := d.poll(ctx, func() (bool, error) {
+		snap, err := snapshots.Get(d.blockStorage, snapshotID).Extract()
+		if err != nil {
+			if isNotFound(err) {
+				return true, nil
+			}
+			return false, err
+		}
+		if isErrorStatus(snap.Status) {
+			return false, goof.WithField(
+				"status", snap.Status, "snapshot entered error status")
+		}
+		return false, nil
+	})
+	if err != nil {
+		return goof.WithFieldsE(
+			fields, "error waiting for snapshot removal", err)
+	}
+
+	return nil
+}
+
+// waitSnapshotStatus polls a snapshot until it has the given status. An
+// error is returned if the snapshot enters an error status.
+func (d *driver) waitSnapshotStatus(
+	ctx types.Context,
+	snapshotID, status string) (*snapshots.Snapshot, error) {
+
+	var snap *snapshots.Snapshot
+	err := d.poll(ctx, func() (bool, error) {
+		var err error
+		snap, err = snapshots.Get(d.blockStorage, snapshotID).Extract()
+		if err != nil {
+			return false, err
+		}
+		if isErrorStatus(snap.Status) {
+			return false, goof.WithField(
+				"status", snap.Status, "snapshot entered error status")
+		}
+		return snap.Status == status, nil
+	})
+	return snap, err
+}
+
+// createdAtLayouts are the layouts of a snapshot's creation time. Cinder
+// omits the time zone, which is UTC.
+var createdAtLayouts = []string{
+	"2006-01-02T15:04:05.000000",
+	"2006-01-02T15:04:05",
+	time.RFC3339Nano,
+}
+
+func toTypesSnapshot(snap *snapshots.Snapshot) *types.Snapshot {
+	snapshot := &types.Snapshot{
+		ID:          snap.ID,
+		Name:        snap.Name,
+		Description: snap.Description,
+		Status:      snap.Status,
+		VolumeID:    snap.VolumeID,
+		VolumeSize:  int64(snap.Size),
+	}
+
+	for _, layout := range createdAtLayouts {
+		if t, err := time.Parse(layout, snap.CreatedAt); err == nil {
+			snapshot.StartTime = t.Unix()
+			break
+		}
+	}
+
+	if len(snap.Metadata) > 0 {
+		snapshot.Fields = map[string]string{}
+		for k, v := range snap.Metadata {
+			snapshot.Fields[k] = v
+		}
+	}
+
+	return snapshot
+}
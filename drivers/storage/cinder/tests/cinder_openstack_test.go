@@ -0,0 +1,511 @@
+// +build !libstorage_storage_driver libstorage_storage_driver_cinder
+
+package cinder
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	osUserName         = "demo"
+	osPassword         = "secret"
+	osDomainName       = "Default"
+	osProjectID        = "0123456789abcdef"
+	osProjectName      = "demo"
+	osRegion           = "RegionOne"
+	osAvailabilityZone = "nova"
+	osInstanceID       = "8d3c9a60-7a23-4c5b-9b1e-6d3c3c1e2f10"
+	osToken            = "token-1"
+
+	volumePath   = "/volume/v1/" + osProjectID
+	computePath  = "/compute/v2/" + osProjectID
+	metadataPath = "/openstack/latest/meta_data.json"
+)
+
+// osVolume is a volume as it is represented by the Cinder v1 API.
+type osVolume struct {
+	ID               string              `json:"id"`
+	Name             string              `json:"display_name"`
+	Status           string              `json:"status"`
+	Size             int                 `json:"size"`
+	AvailabilityZone string              `json:"availability_zone"`
+	VolumeType       string              `json:"volume_type"`
+	SnapshotID       string              `json:"snapshot_id,omitempty"`
+	SourceVolID      string              `json:"source_volid,omitempty"`
+	Metadata         map[string]string   `json:"metadata"`
+	Attachments      []map[string]string `json:"attachments"`
+
+	next string
+}
+
+// osSnapshot is a snapshot as it is represented by the Cinder v1 API.
+type osSnapshot struct {
+	ID        string `json:"id"`
+	Name      string `json:"display_name"`
+	Status    string `json:"status"`
+	Size      int    `json:"size"`
+	VolumeID  string `json:"volume_id"`
+	CreatedAt string `json:"created_at"`
+
+	next string
+}
+
+// osServer is a stand-in for the Keystone, Cinder v1, and Nova APIs of a
+// cloud with a single user, project, region, and instance, as well as for
+// the instance's metadata service.
+//
+// Operations that are asynchronous in OpenStack leave volumes and snapshots
+// in a transitional status, such as "creating" or "extending". Each one
+// moves to its final status after it is next returned by a GET request, so
+// the driver must poll for the final status.
+type osServer struct {
+	sync.Mutex
+	*httptest.Server
+
+	// failCreate causes volumes to move to the "error" status rather than
+	// to "available" once they are created.
+	failCreate bool
+
+	volumes   map[string]*osVolume
+	snapshots map[string]*osSnapshot
+	nextID    int
+}
+
+func newOSServer() *osServer {
+	s := &osServer{
+		volumes:   map[string]*osVolume{},
+		snapshots: map[string]*osSnapshot{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+func (s *osServer) authURLv2() string {
+	return s.URL + "/v2.0"
+}
+
+func (s *osServer) authURLv3() string {
+	return s.URL + "/v3"
+}
+
+func (s *osServer) metadataURL() string {
+	return s.URL + metadataPath
+}
+
+func (s *osServer) newID() string {
+	s.nextID++
+	return fmt.Sprintf("%08x-0000-4000-8000-%012x", s.nextID, s.nextID)
+}
+
+func (s *osServer) handle(w http.ResponseWriter, req *http.Request) {
+	s.Lock()
+	defer s.Unlock()
+
+	p, m := req.URL.Path, req.Method
+	switch {
+	case p == metadataPath && m == http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]string{
+			"uuid":              osInstanceID,
+			"availability_zone": osAvailabilityZone,
+		})
+	case p == "/v2.0/tokens" && m == http.MethodPost:
+		s.authV2(w, req)
+	case p == "/v3/auth/tokens" && m == http.MethodPost:
+		s.authV3(w, req)
+	case req.Header.Get("X-Auth-Token") != osToken:
+		w.WriteHeader(http.StatusUnauthorized)
+	case strings.HasPrefix(p, volumePath+"/"):
+		s.volumeAPI(w, req, strings.Split(p[len(volumePath)+1:], "/"))
+	case strings.HasPrefix(p, computePath+"/servers/"+osInstanceID+"/"):
+		s.computeAPI(w, req, strings.Split(p[len(computePath)+1:], "/"))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (s *osServer) authV2(w http.ResponseWriter, req *http.Request) {
+	var body struct {
+		Auth struct {
+			Credentials struct {
+				Username string `json:"username"`
+				Password string `json:"password"`
+			} `json:"passwordCredentials"`
+			TenantName string `json:"tenantName"`
+		} `json:"auth"`
+	}
+	if !readJSON(w, req, &body) {
+		return
+	}
+	if body.Auth.Credentials.Username != osUserName ||
+		body.Auth.Credentials.Password != osPassword ||
+		body.Auth.TenantName != osProjectName {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var catalog []interface{}
+	for svcType, url := range s.serviceURLs() {
+		catalog = append(catalog, map[string]interface{}{
+			"type": svcType,
+			"name": svcType,
+			"endpoints": []interface{}{map[string]string{
+				"region":      osRegion,
+				"publicURL":   url,
+				"internalURL": url,
+				"adminURL":    url,
+			}},
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access": map[string]interface{}{
+			"token": map[string]interface{}{
+				"id":      osToken,
+				"expires": tokenExpiry(),
+				"tenant": map[string]string{
+					"id": osProjectID, "name": osProjectName},
+			},
+			"serviceCatalog": catalog,
+		},
+	})
+}
+
+func (s *osServer) authV3(w http.ResponseWriter, req *http.Request) {
+	type name struct {
+		Name   string `json:"name"`
+		Domain struct {
+			Name string `json:"name"`
+		} `json:"domain"`
+	}
+	var body struct {
+		Auth struct {
+			Identity struct {
+				Password struct {
+					User struct {
+						name
+						Password string `json:"password"`
+					} `json:"user"`
+				} `json:"password"`
+			} `json:"identity"`
+			Scope struct {
+				Project name `json:"project"`
+			} `json:"scope"`
+		} `json:"auth"`
+	}
+	if !readJSON(w, req, &body) {
+		return
+	}
+	user, project := body.Auth.Identity.Password.User, body.Auth.Scope.Project
+	if user.Name != osUserName || user.Domain.Name != osDomainName ||
+		user.Password != osPassword ||
+		project.Name != osProjectName || project.Domain.Name != osDomainName {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var catalog []interface{}
+	for svcType, url := range s.serviceURLs() {
+		var endpoints []interface{}
+		for _, iface := range []string{"public", "internal", "admin"} {
+			endpoints = append(endpoints, map[string]string{
+				"interface": iface,
+				"region":    osRegion,
+				"url":       url,
+			})
+		}
+		catalog = append(catalog, map[string]interface{}{
+			"type":      svcType,
+			"name":      svcType,
+			"endpoints": endpoints,
+		})
+	}
+	w.Header().Set("X-Subject-Token", osToken)
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"token": map[string]interface{}{
+			"expires_at": tokenExpiry(),
+			"project": map[string]string{
+				"id": osProjectID, "name": osProjectName},
+			"catalog": catalog,
+		},
+	})
+}
+
+func (s *osServer) serviceURLs() map[string]string {
+	return map[string]string{
+		"volume":  s.URL + volumePath + "/",
+		"compute": s.URL + computePath + "/",
+	}
+}
+
+func tokenExpiry() string {
+	return time.Now().Add(time.Hour).UTC().Format(
+		"2006-01-02T15:04:05.000000Z")
+}
+
+func (s *osServer) volumeAPI(
+	w http.ResponseWriter, req *http.Request, parts []string) {
+
+	var (
+		m    = req.Method
+		vol  *osVolume
+		snap *osSnapshot
+	)
+	if len(parts) > 1 && parts[1] != "detail" {
+		switch parts[0] {
+		case "volumes":
+			if vol = s.volumes[parts[1]]; vol == nil {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+		case "snapshots":
+			if snap = s.snapshots[parts[1]]; snap == nil {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+		}
+	}
+
+	switch {
+	case parts[0] == "volumes" && vol == nil && m == http.MethodGet:
+		vols := []*osVolume{}
+		for _, v := range s.volumes {
+			vols = append(vols, v)
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"volumes": vols})
+	case parts[0] == "volumes" && len(parts) == 1 && m == http.MethodPost:
+		s.createVolume(w, req)
+	case vol != nil && len(parts) == 2 && m == http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]interface{}{"volume": vol})
+		if vol.next != "" {
+			vol.Status, vol.next = vol.next, ""
+		}
+	case vol != nil && len(parts) == 2 && m == http.MethodPut:
+		var body struct {
+			Volume struct {
+				Name string `json:"display_name"`
+			} `json:"volume"`
+		}
+		if readJSON(w, req, &body) {
+			vol.Name = body.Volume.Name
+			writeJSON(w, http.StatusOK, map[string]interface{}{"volume": vol})
+		}
+	case vol != nil && len(parts) == 2 && m == http.MethodDelete:
+		if vol.Status != "available" && vol.Status != "error" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		for _, sn := range s.snapshots {
+			if sn.VolumeID == vol.ID {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+		}
+		delete(s.volumes, vol.ID)
+		w.WriteHeader(http.StatusAccepted)
+	case vol != nil && len(parts) == 3 && parts[2] == "action" &&
+		m == http.MethodPost:
+		var body struct {
+			Extend *struct {
+				NewSize int `json:"new_size"`
+			} `json:"os-extend"`
+		}
+		if !readJSON(w, req, &body) {
+			return
+		}
+		if body.Extend == nil || body.Extend.NewSize <= vol.Size {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		vol.Size = body.Extend.NewSize
+		vol.Status, vol.next = "extending", vol.Status
+		w.WriteHeader(http.StatusAccepted)
+	case vol != nil && len(parts) == 3 && parts[2] == "metadata" &&
+		m == http.MethodPost:
+		var body struct {
+			Metadata map[string]string `json:"metadata"`
+		}
+		if readJSON(w, req, &body) {
+			for k, v := range body.Metadata {
+				vol.Metadata[k] = v
+			}
+			writeJSON(w, http.StatusOK, map[string]interface{}{
+				"metadata": vol.Metadata})
+		}
+	case vol != nil && len(parts) == 4 && parts[2] == "metadata" &&
+		m == http.MethodDelete:
+		if _, ok := vol.Metadata[parts[3]]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		delete(vol.Metadata, parts[3])
+		w.WriteHeader(http.StatusOK)
+	case parts[0] == "snapshots" && snap == nil && m == http.MethodGet:
+		snaps := []*osSnapshot{}
+		for _, sn := range s.snapshots {
+			snaps = append(snaps, sn)
+		}
+		writeJSON(w, http.StatusOK,
+			map[string]interface{}{"snapshots": snaps})
+	case parts[0] == "snapshots" && len(parts) == 1 && m == http.MethodPost:
+		s.createSnapshot(w, req)
+	case snap != nil && m == http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]interface{}{"snapshot": snap})
+		if snap.next != "" {
+			snap.Status, snap.next = snap.next, ""
+		}
+	case snap != nil && m == http.MethodDelete:
+		delete(s.snapshots, snap.ID)
+		w.WriteHeader(http.StatusAccepted)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (s *osServer) createVolume(w http.ResponseWriter, req *http.Request) {
+	var body struct {
+		Volume osVolume `json:"volume"`
+	}
+	if !readJSON(w, req, &body) {
+		return
+	}
+
+	vol := body.Volume
+	vol.ID = s.newID()
+	vol.Metadata = map[string]string{}
+	vol.Attachments = []map[string]string{}
+	vol.Status, vol.next = "creating", "available"
+	if s.failCreate {
+		vol.next = "error"
+	}
+	if vol.AvailabilityZone == "" {
+		vol.AvailabilityZone = osAvailabilityZone
+	}
+
+	if snap := s.snapshots[vol.SnapshotID]; snap != nil {
+		if vol.Size < snap.Size {
+			vol.Size = snap.Size
+		}
+	} else if src := s.volumes[vol.SourceVolID]; src != nil {
+		vol.Size, vol.VolumeType = src.Size, src.VolumeType
+	} else if vol.SnapshotID != "" || vol.SourceVolID != "" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if vol.Size <= 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	s.volumes[vol.ID] = &vol
+	writeJSON(w, http.StatusOK, map[string]interface{}{"volume": vol})
+}
+
+func (s *osServer) createSnapshot(w http.ResponseWriter, req *http.Request) {
+	var body struct {
+		Snapshot struct {
+			Name     string `json:"display_name"`
+			VolumeID string `json:"volume_id"`
+			Force    bool   `json:"force"`
+		} `json:"snapshot"`
+	}
+	if !readJSON(w, req, &body) {
+		return
+	}
+
+	vol := s.volumes[body.Snapshot.VolumeID]
+	if vol == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if vol.Status != "available" && !body.Snapshot.Force {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	snap := &osSnapshot{
+		ID:        s.newID(),
+		Name:      body.Snapshot.Name,
+		Status:    "creating",
+		Size:      vol.Size,
+		VolumeID:  vol.ID,
+		CreatedAt: time.Now().UTC().Format("2006-01-02T15:04:05.000000"),
+		next:      "available",
+	}
+	s.snapshots[snap.ID] = snap
+	writeJSON(w, http.StatusOK, map[string]interface{}{"snapshot": snap})
+}
+
+// computeAPI handles the volume attachments of the instance.
+func (s *osServer) computeAPI(
+	w http.ResponseWriter, req *http.Request, parts []string) {
+
+	if len(parts) < 3 || parts[2] != "os-volume_attachments" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	switch {
+	case len(parts) == 3 && req.Method == http.MethodPost:
+		var body struct {
+			Attachment struct {
+				VolumeID string `json:"volumeId"`
+			} `json:"volumeAttachment"`
+		}
+		if !readJSON(w, req, &body) {
+			return
+		}
+		vol := s.volumes[body.Attachment.VolumeID]
+		if vol == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if vol.Status != "available" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		vol.Attachments = []map[string]string{{
+			"volume_id": vol.ID,
+			"server_id": osInstanceID,
+			"device":    "/dev/vdb",
+		}}
+		vol.Status, vol.next = "attaching", "in-use"
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"volumeAttachment": map[string]string{
+				"id":       vol.ID,
+				"volumeId": vol.ID,
+				"serverId": osInstanceID,
+				"device":   "/dev/vdb",
+			},
+		})
+	case len(parts) == 4 && req.Method == http.MethodDelete:
+		vol := s.volumes[parts[3]]
+		if vol == nil || len(vol.Attachments) == 0 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		vol.Attachments = []map[string]string{}
+		vol.Status, vol.next = "detaching", "available"
+		w.WriteHeader(http.StatusAccepted)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func readJSON(w http.ResponseWriter, req *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(req.Body).Decode(v); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,406 @@
+// +build !libstorage_storage_driver libstorage_storage_driver_cinder
+
+package cinder
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	log "github.com/Sirupsen/logrus"
+	gofig "github.com/akutz/gofig/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/registry"
+	"github.com/codedellemc/libstorage/api/server"
+	apitests "github.com/codedellemc/libstorage/api/tests"
+	"github.com/codedellemc/libstorage/api/types"
+
+	// load the driver
+	"github.com/codedellemc/libstorage/drivers/storage/cinder"
+	_ "github.com/codedellemc/libstorage/drivers/storage/cinder/executor"
+	_ "github.com/codedellemc/libstorage/drivers/storage/cinder/storage"
+	cinderUtils "github.com/codedellemc/libstorage/drivers/storage/cinder/utils"
+)
+
+const (
+	volumeName  = "cinder-test-vol-1"
+	volumeName2 = "cinder-test-vol-2"
+)
+
+// openstack is a local stand-in for Keystone, Cinder, Nova, and the metadata
+// service.
+var openstack *osServer
+
+// configYAML returns the configuration for the stand-in using the identity
+// API at authURL. The domain is only used by the identity v3 API.
+func configYAML(authURL, domainName string) []byte {
+	return []byte(fmt.Sprintf(`
+cinder:
+  authURL: %s
+  userName: %s
+  password: %s
+  projectName: %s
+  domainName: %s
+  regionName: %s
+  statusTimeout: 10s
+  metadataURL: %s
+  configDrivePath: ""
+`,
+		authURL,
+		osUserName,
+		osPassword,
+		osProjectName,
+		domainName,
+		osRegion,
+		openstack.metadataURL()))
+}
+
+func configYAMLv3() []byte {
+	return configYAML(openstack.authURLv3(), osDomainName)
+}
+
+func TestMain(m *testing.M) {
+	server.CloseOnAbort()
+	openstack = newOSServer()
+	ec := m.Run()
+	openstack.Close()
+	os.Exit(ec)
+}
+
+func TestInstanceID(t *testing.T) {
+	config := registry.NewConfig()
+	config.Set(cinder.Name+"."+cinder.MetadataURL, openstack.metadataURL())
+	config.Set(cinder.Name+"."+cinder.ConfigDrivePath, "")
+
+	iid, err := cinderUtils.InstanceID(context.Background(), config)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, osInstanceID, iid.ID)
+
+	apitests.Run(
+		t, cinder.Name, configYAMLv3(),
+		(&apitests.InstanceIDTest{
+			Driver:   cinder.Name,
+			Expected: iid,
+		}).Test)
+}
+
+func TestServices(t *testing.T) {
+	tf := func(config gofig.Config, client types.Client, t *testing.T) {
+		reply, err := client.API().Services(nil)
+		assert.NoError(t, err)
+		assert.Equal(t, len(reply), 1)
+
+		_, ok := reply[cinder.Name]
+		assert.True(t, ok)
+	}
+	apitests.Run(t, cinder.Name, configYAMLv3(), tf)
+}
+
+// TestServicesIdentityV2 ensures the driver authenticates with the identity
+// v2.0 API as well as with the v3 API.
+func TestServicesIdentityV2(t *testing.T) {
+	tf := func(config gofig.Config, client types.Client, t *testing.T) {
+		_, err := client.API().VolumesByService(nil, cinder.Name, 0)
+		assert.NoError(t, err)
+	}
+	apitests.Run(t, cinder.Name, configYAML(openstack.authURLv2(), ""), tf)
+}
+
+func volumeCreate(
+	t *testing.T, client types.Client, volumeName string) *types.Volume {
+
+	log.WithField("volumeName", volumeName).Info("creating volume")
+	size := int64(1)
+	volumeType := "ssd"
+
+	reply, err := client.API().VolumeCreate(
+		nil, cinder.Name, &types.VolumeCreateRequest{
+			Name: volumeName,
+			Size: &size,
+			Type: &volumeType,
+		})
+	assert.NoError(t, err)
+	if err != nil {
+		t.FailNow()
+	}
+	apitests.LogAsJSON(reply, t)
+
+	assert.Equal(t, volumeName, reply.Name)
+	assert.Equal(t, size, reply.Size)
+	assert.Equal(t, volumeType, reply.Type)
+	assert.Equal(t, "available", reply.Status)
+	assert.Equal(t, osAvailabilityZone, reply.AvailabilityZone)
+	return reply
+}
+
+func volumeRemove(t *testing.T, client types.Client, volumeID string) {
+	log.WithField("volumeID", volumeID).Info("removing volume")
+	err := client.API().VolumeRemove(nil, cinder.Name, volumeID)
+	assert.NoError(t, err)
+	if err != nil {
+		t.FailNow()
+	}
+}
+
+func volumeByName(
+	t *testing.T, client types.Client, volumeName string) *types.Volume {
+
+	vols, err := client.API().VolumesByService(nil, cinder.Name, 0)
+	assert.NoError(t, err)
+	if err != nil {
+		t.FailNow()
+	}
+	for _, vol := range vols {
+		if vol.Name == volumeName {
+			return vol
+		}
+	}
+	t.Fatalf("volume %s not found", volumeName)
+	return nil
+}
+
+func TestVolumeCreateRemove(t *testing.T) {
+	tf := func(config gofig.Config, client types.Client, t *testing.T) {
+		vol := volumeCreate(t, client, volumeName)
+		volumeRemove(t, client, vol.ID)
+
+		_, err := client.API().VolumeInspect(nil, cinder.Name, vol.ID, 0)
+		assert.Error(t, err)
+	}
+	apitests.Run(t, cinder.Name, configYAMLv3(), tf)
+}
+
+func TestVolumeCreateDuplicateName(t *testing.T) {
+	tf := func(config gofig.Config, client types.Client, t *testing.T) {
+		vol := volumeCreate(t, client, volumeName)
+		defer volumeRemove(t, client, vol.ID)
+
+		size := int64(1)
+		_, err := client.API().VolumeCreate(
+			nil, cinder.Name, &types.VolumeCreateRequest{
+				Name: volumeName,
+				Size: &size,
+			})
+		assert.Error(t, err)
+	}
+	apitests.Run(t, cinder.Name, configYAMLv3(), tf)
+}
+
+func TestVolumes(t *testing.T) {
+	tf := func(config gofig.Config, client types.Client, t *testing.T) {
+		vol1 := volumeCreate(t, client, volumeName)
+		vol2 := volumeCreate(t, client, volumeName2)
+
+		assert.Equal(t, vol1.ID, volumeByName(t, client, volumeName).ID)
+		assert.Equal(t, vol2.ID, volumeByName(t, client, volumeName2).ID)
+
+		volumeRemove(t, client, vol1.ID)
+		volumeRemove(t, client, vol2.ID)
+	}
+	apitests.Run(t, cinder.Name, configYAMLv3(), tf)
+}
+
+func TestVolumeResizeUpdate(t *testing.T) {
+	tf := func(config gofig.Config, client types.Client, t *testing.T) {
+		vol := volumeCreate(t, client, volumeName)
+		defer volumeRemove(t, client, vol.ID)
+
+		reply, err := client.API().VolumeResize(
+			nil, cinder.Name, vol.ID, &types.VolumeResizeRequest{Size: 3})
+		assert.NoError(t, err)
+		if err != nil {
+			t.FailNow()
+		}
+		assert.Equal(t, int64(3), reply.Size)
+
+		name := volumeName2
+		reply, err = client.API().VolumeUpdate(
+			nil, cinder.Name, vol.ID, &types.VolumeUpdateRequest{
+				Name:   &name,
+				Fields: map[string]string{"owner": "root", "env": "test"},
+			})
+		assert.NoError(t, err)
+		if err != nil {
+			t.FailNow()
+		}
+		assert.Equal(t, volumeName2, reply.Name)
+		assert.Equal(t, "root", reply.Fields["owner"])
+
+		reply, err = client.API().VolumeUpdate(
+			nil, cinder.Name, vol.ID, &types.VolumeUpdateRequest{
+				RemoveFields: []string{"env"},
+			})
+		assert.NoError(t, err)
+		if err != nil {
+			t.FailNow()
+		}
+		assert.Equal(t, map[string]string{"owner": "root"}, reply.Fields)
+	}
+	apitests.Run(t, cinder.Name, configYAMLv3(), tf)
+}
+
+// TestVolumeResizeAttached ensures the driver waits for a resized volume to
+// return to its previous status rather than to "available".
+func TestVolumeResizeAttached(t *testing.T) {
+	tf := func(config gofig.Config, client types.Client, t *testing.T) {
+		vol := volumeCreate(t, client, volumeName)
+		defer volumeRemove(t, client, vol.ID)
+
+		_, _, err := client.API().VolumeAttach(
+			nil, cinder.Name, vol.ID, &types.VolumeAttachRequest{})
+		assert.NoError(t, err)
+		if err != nil {
+			t.FailNow()
+		}
+
+		reply, err := client.API().VolumeResize(
+			nil, cinder.Name, vol.ID, &types.VolumeResizeRequest{Size: 2})
+		assert.NoError(t, err)
+		if err != nil {
+			t.FailNow()
+		}
+		assert.Equal(t, int64(2), reply.Size)
+		assert.Equal(t, "in-use", reply.Status)
+
+		_, err = client.API().VolumeDetach(
+			nil, cinder.Name, vol.ID, &types.VolumeDetachRequest{})
+		assert.NoError(t, err)
+	}
+	apitests.Run(t, cinder.Name, configYAMLv3(), tf)
+}
+
+func TestVolumeAttach(t *testing.T) {
+	tf := func(config gofig.Config, client types.Client, t *testing.T) {
+		vol := volumeCreate(t, client, volumeName)
+
+		reply, token, err := client.API().VolumeAttach(
+			nil, cinder.Name, vol.ID, &types.VolumeAttachRequest{})
+		assert.NoError(t, err)
+		if err != nil {
+			t.FailNow()
+		}
+		apitests.LogAsJSON(reply, t)
+
+		// the token is the serial number of the volume's virtio disk
+		assert.Equal(t, vol.ID[:20], token)
+		assert.Equal(t, "in-use", reply.Status)
+		if assert.Len(t, reply.Attachments, 1) {
+			assert.Equal(t,
+				osInstanceID, reply.Attachments[0].InstanceID.ID)
+		}
+
+		// the volume cannot be attached twice without force
+		_, _, err = client.API().VolumeAttach(
+			nil, cinder.Name, vol.ID, &types.VolumeAttachRequest{})
+		assert.Error(t, err)
+
+		reply, err = client.API().VolumeDetach(
+			nil, cinder.Name, vol.ID, &types.VolumeDetachRequest{})
+		assert.NoError(t, err)
+		if err != nil {
+			t.FailNow()
+		}
+		assert.Equal(t, "available", reply.Status)
+		assert.Len(t, reply.Attachments, 0)
+
+		volumeRemove(t, client, vol.ID)
+	}
+	apitests.Run(t, cinder.Name, configYAMLv3(), tf)
+}
+
+func TestVolumeSnapshot(t *testing.T) {
+	tf := func(config gofig.Config, client types.Client, t *testing.T) {
+		vol := volumeCreate(t, client, volumeName)
+
+		snap, err := client.API().VolumeSnapshot(
+			nil, cinder.Name, vol.ID,
+			&types.VolumeSnapshotRequest{SnapshotName: "snap-1"})
+		assert.NoError(t, err)
+		if err != nil {
+			t.FailNow()
+		}
+		apitests.LogAsJSON(snap, t)
+		assert.Equal(t, "snap-1", snap.Name)
+		assert.Equal(t, vol.ID, snap.VolumeID)
+		assert.Equal(t, vol.Size, snap.VolumeSize)
+		assert.Equal(t, "available", snap.Status)
+		assert.NotZero(t, snap.StartTime)
+
+		snaps, err := client.API().SnapshotsByService(nil, cinder.Name)
+		assert.NoError(t, err)
+		assert.Contains(t, snaps, snap.ID)
+
+		vol2, err := client.API().VolumeCreateFromSnapshot(
+			nil, cinder.Name, snap.ID,
+			&types.VolumeCreateRequest{Name: volumeName2})
+		assert.NoError(t, err)
+		if err != nil {
+			t.FailNow()
+		}
+		assert.Equal(t, volumeName2, vol2.Name)
+		assert.Equal(t, vol.Size, vol2.Size)
+
+		// the volume has a snapshot and cannot be removed
+		assert.Error(t, client.API().VolumeRemove(nil, cinder.Name, vol.ID))
+
+		assert.NoError(t,
+			client.API().SnapshotRemove(nil, cinder.Name, snap.ID))
+		_, err = client.API().SnapshotInspect(nil, cinder.Name, snap.ID)
+		assert.Error(t, err)
+
+		volumeRemove(t, client, vol2.ID)
+		volumeRemove(t, client, vol.ID)
+	}
+	apitests.Run(t, cinder.Name, configYAMLv3(), tf)
+}
+
+func TestVolumeCopy(t *testing.T) {
+	tf := func(config gofig.Config, client types.Client, t *testing.T) {
+		vol := volumeCreate(t, client, volumeName)
+
+		reply, err := client.API().VolumeCopy(
+			nil, cinder.Name, vol.ID,
+			&types.VolumeCopyRequest{VolumeName: volumeName2})
+		assert.NoError(t, err)
+		if err != nil {
+			t.FailNow()
+		}
+		assert.Equal(t, volumeName2, reply.Name)
+		assert.Equal(t, vol.Size, reply.Size)
+		assert.Equal(t, vol.Type, reply.Type)
+
+		volumeRemove(t, client, reply.ID)
+		volumeRemove(t, client, vol.ID)
+	}
+	apitests.Run(t, cinder.Name, configYAMLv3(), tf)
+}
+
+func setFailCreate(fail bool) {
+	openstack.Lock()
+	defer openstack.Unlock()
+	openstack.failCreate = fail
+}
+
+func TestVolumeCreateError(t *testing.T) {
+	tf := func(config gofig.Config, client types.Client, t *testing.T) {
+		setFailCreate(true)
+		defer setFailCreate(false)
+
+		size := int64(1)
+		_, err := client.API().VolumeCreate(
+			nil, cinder.Name, &types.VolumeCreateRequest{
+				Name: volumeName,
+				Size: &size,
+			})
+		assert.Error(t, err)
+
+		vol := volumeByName(t, client, volumeName)
+		assert.Equal(t, "error", vol.Status)
+		volumeRemove(t, client, vol.ID)
+	}
+	apitests.Run(t, cinder.Name, configYAMLv3(), tf)
+}
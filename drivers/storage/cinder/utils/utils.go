@@ -0,0 +1,163 @@
+// +build !libstorage_storage_driver libstorage_storage_driver_cinder
+
+package utils
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"path"
+	"time"
+
+	gofig "github.com/akutz/gofig/types"
+	"github.com/akutz/goof"
+
+	"github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/drivers/storage/cinder"
+)
+
+// configDriveMetadata is the path of the metadata on a config drive.
+const configDriveMetadata = "openstack/latest/meta_data.json"
+
+type instanceMetadata struct {
+	UUID             string `json:"uuid"`
+	AvailabilityZone string `json:"availability_zone"`
+}
+
+// IsOpenStackInstance returns a flag indicating whether the executing host is
+// an OpenStack instance based on whether or not the host has a config drive
+// or can access the metadata service.
+func IsOpenStackInstance(
+	ctx types.Context, config gofig.Config) (bool, error) {
+
+	if p := configDrivePath(config); p != "" {
+		if _, err := os.Stat(p); err == nil {
+			return true, nil
+		}
+	}
+
+	req, err := http.NewRequest(http.MethodGet, metadataURL(config), nil)
+	if err != nil {
+		return false, err
+	}
+	client := &http.Client{Timeout: time.Duration(1 * time.Second)}
+	res, err := doRequestWithClient(ctx, client, req)
+	if err != nil {
+		// the metadata service is unreachable from hosts outside OpenStack
+		return false, nil
+	}
+	res.Body.Close()
+	return res.StatusCode >= 200 && res.StatusCode <= 299, nil
+}
+
+// InstanceID returns the instance ID for the local host. The instance's
+// metadata is read from the config drive if the host has one, otherwise it
+// is requested from the metadata service.
+func InstanceID(
+	ctx types.Context, config gofig.Config) (*types.InstanceID, error) {
+
+	md, err := readConfigDrive(config)
+	if err != nil {
+		ctx.WithError(err).Debug(
+			"error reading config drive; using metadata service")
+		if md, err = readMetadataService(ctx, config); err != nil {
+			return nil, goof.WithError(
+				"error reading instance metadata", err)
+		}
+	}
+	if md.UUID == "" {
+		return nil, goof.New("instance metadata missing uuid")
+	}
+
+	return &types.InstanceID{
+		ID:     md.UUID,
+		Driver: cinder.Name,
+		Fields: map[string]string{
+			cinder.InstanceIDFieldAvailabilityZone: md.AvailabilityZone,
+		},
+	}, nil
+}
+
+func configDrivePath(config gofig.Config) string {
+	return config.GetString(cinder.Name + "." + cinder.ConfigDrivePath)
+}
+
+func metadataURL(config gofig.Config) string {
+	return config.GetString(cinder.Name + "." + cinder.MetadataURL)
+}
+
+// readConfigDrive reads the instance metadata from the config drive. The
+// config drive path is either a directory where the config drive is already
+// mounted, or the config drive's device, which is mounted read-only for as
+// long as it takes to read the metadata.
+func readConfigDrive(config gofig.Config) (*instanceMetadata, error) {
+	p := configDrivePath(config)
+	if p == "" {
+		return nil, goof.New("config drive disabled")
+	}
+
+	fi, err := os.Stat(p)
+	if err != nil {
+		return nil, err
+	}
+	if fi.IsDir() {
+		return readMetadataFile(path.Join(p, configDriveMetadata))
+	}
+
+	mnt, err := ioutil.TempDir("", "libstorage-cinder-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(mnt)
+
+	if out, err := exec.Command(
+		"mount", "-o", "ro", p, mnt).CombinedOutput(); err != nil {
+		return nil, goof.WithFieldsE(
+			goof.Fields{"device": p, "output": string(out)},
+			"error mounting config drive", err)
+	}
+	defer exec.Command("umount", mnt).Run()
+
+	return readMetadataFile(path.Join(mnt, configDriveMetadata))
+}
+
+func readMetadataFile(name string) (*instanceMetadata, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return decodeMetadata(f)
+}
+
+func readMetadataService(
+	ctx types.Context, config gofig.Config) (*instanceMetadata, error) {
+
+	req, err := http.NewRequest(http.MethodGet, metadataURL(config), nil)
+	if err != nil {
+		return nil, err
+	}
+	client := &http.Client{Timeout: time.Duration(5 * time.Second)}
+	res, err := doRequestWithClient(ctx, client, req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, goof.WithField(
+			"status", res.Status, "error requesting instance metadata")
+	}
+	return decodeMetadata(res.Body)
+}
+
+func decodeMetadata(r io.Reader) (*instanceMetadata, error) {
+	md := &instanceMetadata{}
+	if err := json.NewDecoder(r).Decode(md); err != nil {
+		return nil, err
+	}
+	return md, nil
+}
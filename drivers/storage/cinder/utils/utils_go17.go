@@ -0,0 +1,18 @@
+// +build go1.7
+// +build !libstorage_storage_driver libstorage_storage_driver_cinder
+
+package utils
+
+import (
+	"net/http"
+
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+func doRequestWithClient(
+	ctx types.Context,
+	client *http.Client,
+	req *http.Request) (*http.Response, error) {
+	req = req.WithContext(ctx)
+	return client.Do(req)
+}
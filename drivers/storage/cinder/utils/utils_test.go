@@ -0,0 +1,97 @@
+// +build !libstorage_storage_driver libstorage_storage_driver_cinder
+
+package utils
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/registry"
+	"github.com/codedellemc/libstorage/drivers/storage/cinder"
+)
+
+func TestInstanceIDConfigDrive(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cinder-utils-test")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(dir)
+
+	mdDir := path.Join(dir, path.Dir(configDriveMetadata))
+	if !assert.NoError(t, os.MkdirAll(mdDir, 0755)) {
+		t.FailNow()
+	}
+	err = ioutil.WriteFile(
+		path.Join(dir, configDriveMetadata),
+		[]byte(`{"uuid": "instance-1", "availability_zone": "az1"}`),
+		0644)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	config := registry.NewConfig()
+	config.Set(cinder.Name+"."+cinder.ConfigDrivePath, dir)
+	config.Set(cinder.Name+"."+cinder.MetadataURL, "http://127.0.0.1:1")
+
+	ctx := context.Background()
+	ok, err := IsOpenStackInstance(ctx, config)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	iid, err := InstanceID(ctx, config)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, "instance-1", iid.ID)
+	assert.Equal(t, cinder.Name, iid.Driver)
+	assert.Equal(t, "az1",
+		iid.Fields[cinder.InstanceIDFieldAvailabilityZone])
+}
+
+func TestInstanceIDMetadataService(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, req *http.Request) {
+			w.Write([]byte(
+				`{"uuid": "instance-2", "availability_zone": "az2"}`))
+		}))
+	defer s.Close()
+
+	config := registry.NewConfig()
+	config.Set(cinder.Name+"."+cinder.ConfigDrivePath, "/dev/null/missing")
+	config.Set(cinder.Name+"."+cinder.MetadataURL,
+		s.URL+"/openstack/latest/meta_data.json")
+
+	ctx := context.Background()
+	ok, err := IsOpenStackInstance(ctx, config)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	iid, err := InstanceID(ctx, config)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, "instance-2", iid.ID)
+	assert.Equal(t, "az2",
+		iid.Fields[cinder.InstanceIDFieldAvailabilityZone])
+}
+
+func TestIsOpenStackInstanceUnreachable(t *testing.T) {
+	config := registry.NewConfig()
+	config.Set(cinder.Name+"."+cinder.ConfigDrivePath, "")
+	config.Set(cinder.Name+"."+cinder.MetadataURL, "http://127.0.0.1:1")
+
+	ctx := context.Background()
+	ok, err := IsOpenStackInstance(ctx, config)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	_, err = InstanceID(ctx, config)
+	assert.Error(t, err)
+}
@@ -5,6 +5,8 @@ package ebs
 import (
 	gofigCore "github.com/akutz/gofig"
 	gofig "github.com/akutz/gofig/types"
+
+	"github.com/codedellemc/libstorage/api/registry"
 )
 
 const (
@@ -91,4 +93,9 @@ func init() {
 	r.Key(gofig.String, "", "", "", NameAWS+"."+KmsKeyID)
 
 	gofigCore.Register(r)
+
+	registry.RegisterSecretConfigKeys(
+		Name+"."+SecretKey,
+		NameEC2+"."+SecretKey,
+		NameAWS+"."+SecretKey)
 }
@@ -36,6 +36,11 @@ const (
 	waitVolumeAttach = "attach"
 	// waitVolumeDetach signifies to wait for volume detachment to complete
 	waitVolumeDetach = "detach"
+
+	// minMaxResults and maxMaxResults are the bounds of the maximum number
+	// of volumes EC2 returns with each page
+	minMaxResults = 5
+	maxMaxResults = 500
 )
 
 type driver struct {
@@ -244,6 +249,27 @@ func (d *driver) InstanceInspect(
 func (d *driver) Volumes(
 	ctx types.Context,
 	opts *types.VolumesOpts) ([]*types.Volume, error) {
+
+	// Get a page of the volumes via EC2 API when a page is requested. EC2
+	// cannot return fewer than minMaxResults volumes per page, so smaller
+	// pages are left to the server.
+	if page := opts.Page; page != nil &&
+		(page.Token != "" || page.Limit >= minMaxResults) {
+
+		ec2vols, next, err := d.getVolumesPage(ctx, page)
+		if err != nil {
+			return nil, goof.WithError("error getting volume", err)
+		}
+		page.Next = next
+		page.Paged = true
+		vols, convErr := d.toTypesVolume(ctx, ec2vols, opts.Attachments)
+		if convErr != nil {
+			return nil, goof.WithError(
+				"error converting to types.Volume", convErr)
+		}
+		return vols, nil
+	}
+
 	// Get all volumes via EC2 API
 	ec2vols, err := d.getVolume(ctx, "", "")
 	if err != nil {
@@ -556,6 +582,95 @@ var (
 	errVolAlreadyAttached = goof.New("volume already attached to a host")
 )
 
+// VolumeResize resizes a volume (not implemented).
+func (d *driver) VolumeResize(
+	ctx types.Context,
+	volumeID string,
+	opts *types.VolumeResizeOpts) (*types.Volume, error) {
+	return nil, types.ErrNotImplemented
+}
+
+// nameTagKey is the key of the tag that stores a volume's name.
+const nameTagKey = "Name"
+
+var (
+	errEmptyVolName   = goof.New("volume name cannot be empty")
+	errReservedTagKey = goof.WithField(
+		"key", nameTagKey, "tag is reserved for the volume name")
+)
+
+// VolumeUpdate renames a volume and sets or removes its tags. A volume's
+// name is stored in its "Name" tag, and all other tags are presented as the
+// volume's fields. The "Name" tag cannot be set or removed as a field, and a
+// volume cannot be renamed to an empty name.
+func (d *driver) VolumeUpdate(
+	ctx types.Context,
+	volumeID string,
+	opts *types.VolumeUpdateOpts) (*types.Volume, error) {
+	// Initialize for logging
+	fields := map[string]interface{}{
+		"provider": d.Name(),
+		"volumeID": volumeID,
+	}
+
+	if opts.Name != nil && *opts.Name == "" {
+		return nil, errEmptyVolName
+	}
+	if _, ok := opts.Fields[nameTagKey]; ok {
+		return nil, errReservedTagKey
+	}
+	for _, k := range opts.RemoveFields {
+		if k == nameTagKey {
+			return nil, errReservedTagKey
+		}
+	}
+
+	if opts.Name != nil {
+		if err := d.createTags(ctx, volumeID, *opts.Name); err != nil {
+			return nil, goof.WithFieldsE(
+				fields, "error renaming volume", err)
+		}
+	}
+
+	if len(opts.Fields) > 0 {
+		ctInput := &awsec2.CreateTagsInput{
+			Resources: []*string{&volumeID},
+			Tags:      []*awsec2.Tag{},
+		}
+		for k, v := range opts.Fields {
+			ctInput.Tags = append(ctInput.Tags, &awsec2.Tag{
+				Key:   aws.String(k),
+				Value: aws.String(v),
+			})
+		}
+		if _, err := mustSession(ctx).CreateTags(ctInput); err != nil {
+			return nil, goof.WithFieldsE(
+				fields, "error creating tags", err)
+		}
+	}
+
+	if len(opts.RemoveFields) > 0 {
+		dtInput := &awsec2.DeleteTagsInput{
+			Resources: []*string{&volumeID},
+			Tags:      []*awsec2.Tag{},
+		}
+		for _, k := range opts.RemoveFields {
+			dtInput.Tags = append(dtInput.Tags, &awsec2.Tag{
+				Key: aws.String(k),
+			})
+		}
+		if _, err := mustSession(ctx).DeleteTags(dtInput); err != nil {
+			return nil, goof.WithFieldsE(
+				fields, "error deleting tags", err)
+		}
+	}
+
+	return d.VolumeInspect(ctx, volumeID, &types.VolumeInspectOpts{
+		Attachments: types.VolAttReq,
+		Opts:        opts.Opts,
+	})
+}
+
 // VolumeAttach attaches a volume and provides a token clients can use
 // to validate that device has appeared locally.
 func (d *driver) VolumeAttach(
@@ -901,6 +1016,44 @@ func (d *driver) getVolume(
 	return resp.Volumes, nil
 }
 
+// getVolumesPage returns a page of the volumes and the token of the next
+// page. The token is empty when there are no more volumes.
+func (d *driver) getVolumesPage(
+	ctx types.Context,
+	page *types.VolumesPage) ([]*awsec2.Volume, string, error) {
+
+	maxResults := int64(page.Limit)
+	if maxResults == 0 || maxResults > maxMaxResults {
+		maxResults = maxMaxResults
+	} else if maxResults < minMaxResults {
+		maxResults = minMaxResults
+	}
+
+	dvInput := &awsec2.DescribeVolumesInput{MaxResults: &maxResults}
+
+	if avaiZone := d.mustAvailabilityZone(ctx); avaiZone != nil {
+		dvInput.Filters = []*awsec2.Filter{{
+			Name:   aws.String("availability-zone"),
+			Values: []*string{avaiZone},
+		}}
+	}
+
+	if page.Token != "" {
+		dvInput.NextToken = aws.String(page.Token)
+	}
+
+	resp, err := mustSession(ctx).DescribeVolumes(dvInput)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var next string
+	if resp.NextToken != nil {
+		next = *resp.NextToken
+	}
+	return resp.Volumes, next, nil
+}
+
 var errGetLocDevs = goof.New("error getting local devices from context")
 
 // Converts EC2 API volumes to libStorage types.Volume
@@ -969,6 +1122,17 @@ func (d *driver) toTypesVolume(
 		if volume.Iops != nil {
 			volumeSD.IOPS = *volume.Iops
 		}
+
+		// All tags other than the volume's name are exposed as its fields
+		for _, tag := range volume.Tags {
+			if *tag.Key == nameTagKey {
+				continue
+			}
+			if volumeSD.Fields == nil {
+				volumeSD.Fields = map[string]string{}
+			}
+			volumeSD.Fields[*tag.Key] = *tag.Value
+		}
 		volumesSD = append(volumesSD, volumeSD)
 	}
 	return volumesSD, nil
@@ -1178,7 +1342,7 @@ func (d *driver) createTags(ctx types.Context, id, name string) (err error) {
 	ctInput.Tags = append(
 		ctInput.Tags,
 		&awsec2.Tag{
-			Key:   aws.String("Name"),
+			Key:   aws.String(nameTagKey),
 			Value: &inputName,
 		})
 
@@ -1285,7 +1449,7 @@ func (d *driver) waitSnapshotComplete(
 // Retrieve volume or snapshot name
 func (d *driver) getName(tags []*awsec2.Tag) string {
 	for _, tag := range tags {
-		if *tag.Key == "Name" {
+		if *tag.Key == nameTagKey {
 			return *tag.Value
 		}
 	}
@@ -5,6 +5,8 @@ package efs
 import (
 	gofigCore "github.com/akutz/gofig"
 	gofig "github.com/akutz/gofig/types"
+
+	"github.com/codedellemc/libstorage/api/registry"
 )
 
 const (
@@ -102,4 +104,6 @@ func init() {
 	r.Key(gofig.Bool, "", false,
 		"A flag that disables the session cache", ConfigEFSDisableSessionCache)
 	gofigCore.Register(r)
+
+	registry.RegisterSecretConfigKeys(ConfigEFSSecretKey)
 }
@@ -557,6 +557,21 @@ func (d *driver) VolumeRemove(
 
 var errInvalidSecGroups = goof.New("security groups required")
 
+// VolumeResize resizes a volume (not implemented).
+func (d *driver) VolumeResize(
+	ctx types.Context,
+	volumeID string,
+	opts *types.VolumeResizeOpts) (*types.Volume, error) {
+	return nil, types.ErrNotImplemented
+}
+
+func (d *driver) VolumeUpdate(
+	ctx types.Context,
+	volumeID string,
+	opts *types.VolumeUpdateOpts) (*types.Volume, error) {
+	return nil, types.ErrNotImplemented
+}
+
 // VolumeAttach attaches a volume and provides a token clients can use
 // to validate that device has appeared locally.
 func (d *driver) VolumeAttach(
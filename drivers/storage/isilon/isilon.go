@@ -5,6 +5,8 @@ package isilon
 import (
 	gofigCore "github.com/akutz/gofig"
 	gofig "github.com/akutz/gofig/types"
+
+	"github.com/codedellemc/libstorage/api/registry"
 )
 
 const (
@@ -25,4 +27,6 @@ func init() {
 	r.Key(gofig.Bool, "", false, "", "isilon.quotas")
 	r.Key(gofig.Bool, "", false, "", "isilon.sharedMounts")
 	gofigCore.Register(r)
+
+	registry.RegisterSecretConfigKeys("isilon.password")
 }
@@ -11,7 +11,7 @@ const (
 )
 
 var (
-	lsxMutex = types.Run.Join("lsx.lock")
+	lsxMutex = types.Run.Join(lsxLockFileName)
 )
 
 func init() {
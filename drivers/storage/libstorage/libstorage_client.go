@@ -123,13 +123,14 @@ func (c *client) updateExecutor(ctx types.Context) error {
 	}
 
 	ctx.Debug("waiting on executor lock")
-	if err := c.lsxMutexWait(); err != nil {
+	lock, err := c.lsxMutexWait(ctx)
+	if err != nil {
 		return err
 	}
 	defer func() {
 		ctx.Debug("signalling executor lock")
-		if err := c.lsxMutexSignal(); err != nil {
-			panic(err)
+		if err := c.lsxMutexSignal(lock); err != nil {
+			ctx.WithError(err).Error("error releasing executor lock")
 		}
 	}()
 
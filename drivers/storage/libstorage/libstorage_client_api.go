@@ -42,6 +42,39 @@ func (c *client) Root(
 	return c.APIClient.Root(c.requireCtx(ctx))
 }
 
+func (c *client) Tasks(
+	ctx types.Context,
+	opts *types.TasksOpts) (map[string]*types.Task, error) {
+
+	return c.APIClient.Tasks(c.requireCtx(ctx), opts)
+}
+
+func (c *client) TaskInspect(
+	ctx types.Context, taskID int) (*types.Task, error) {
+
+	return c.APIClient.TaskInspect(c.requireCtx(ctx), taskID)
+}
+
+func (c *client) TaskCancel(
+	ctx types.Context, taskID int) (*types.Task, error) {
+
+	return c.APIClient.TaskCancel(c.requireCtx(ctx), taskID)
+}
+
+func (c *client) Events(
+	ctx types.Context,
+	services ...string) (<-chan *types.Event, error) {
+
+	return c.APIClient.Events(c.requireCtx(ctx), services...)
+}
+
+func (c *client) Audit(
+	ctx types.Context,
+	opts *types.AuditOpts) ([]*types.AuditRecord, error) {
+
+	return c.APIClient.Audit(c.requireCtx(ctx), opts)
+}
+
 func (c *client) Services(
 	ctx types.Context) (map[string]*types.ServiceInfo, error) {
 
@@ -57,6 +90,19 @@ func (c *client) Services(
 	return svcInfo, err
 }
 
+func (c *client) ServicesReload(
+	ctx types.Context) (map[string]*types.ServiceInfo, error) {
+
+	svcInfo, err := c.APIClient.ServicesReload(c.requireCtx(ctx))
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range svcInfo {
+		c.serviceCache.Set(k, v)
+	}
+	return svcInfo, nil
+}
+
 func (c *client) ServiceInspect(
 	ctx types.Context, service string) (*types.ServiceInfo, error) {
 
@@ -238,13 +284,32 @@ func (c *client) VolumeRemove(
 	return nil
 }
 
+func (c *client) VolumeResize(
+	ctx types.Context,
+	service, volumeID string,
+	request *types.VolumeResizeRequest) (*types.Volume, error) {
+
+	ctx = c.withInstanceID(c.requireCtx(ctx), service)
+	return c.APIClient.VolumeResize(ctx, service, volumeID, request)
+}
+
+func (c *client) VolumeUpdate(
+	ctx types.Context,
+	service, volumeID string,
+	request *types.VolumeUpdateRequest) (*types.Volume, error) {
+
+	ctx = c.withInstanceID(c.requireCtx(ctx), service)
+	return c.APIClient.VolumeUpdate(ctx, service, volumeID, request)
+}
+
 func (c *client) VolumeAttach(
 	ctx types.Context,
 	service string,
 	volumeID string,
 	request *types.VolumeAttachRequest) (*types.Volume, string, error) {
 
-	if c.isController() {
+	// a controller client may only target another instance
+	if c.isController() && !hasTargetInstance(request.InstanceID) {
 		return nil, "", utils.NewUnsupportedForClientTypeError(
 			c.clientType, "VolumeAttach")
 	}
@@ -265,7 +330,8 @@ func (c *client) VolumeDetach(
 	volumeID string,
 	request *types.VolumeDetachRequest) (*types.Volume, error) {
 
-	if c.isController() {
+	// a controller client may only target another instance
+	if c.isController() && !hasTargetInstance(request.InstanceID) {
 		return nil, utils.NewUnsupportedForClientTypeError(
 			c.clientType, "VolumeDetach")
 	}
@@ -304,7 +370,8 @@ func (c *client) VolumeDetachAllForService(
 	service string,
 	request *types.VolumeDetachRequest) (types.VolumeMap, error) {
 
-	if c.isController() {
+	// a controller client may only target another instance
+	if c.isController() && !hasTargetInstance(request.InstanceID) {
 		return nil, utils.NewUnsupportedForClientTypeError(
 			c.clientType, "VolumeDetachAllForService")
 	}
@@ -7,7 +7,6 @@ import (
 	"strconv"
 	"strings"
 	"syscall"
-	"time"
 
 	"github.com/akutz/goof"
 	"github.com/akutz/gotil"
@@ -283,14 +282,15 @@ func (c *client) runExecutor(
 	}
 
 	ctx.Debug("waiting on executor lock")
-	if err := c.lsxMutexWait(); err != nil {
+	lock, err := c.lsxMutexWait(ctx)
+	if err != nil {
 		return nil, err
 	}
 
 	defer func() {
 		ctx.Debug("signalling executor lock")
-		if err := c.lsxMutexSignal(); err != nil {
-			panic(err)
+		if err := c.lsxMutexSignal(lock); err != nil {
+			ctx.WithError(err).Error("error releasing executor lock")
 		}
 	}()
 
@@ -326,27 +326,22 @@ func (c *client) runExecutor(
 	return out, err
 }
 
-func (c *client) lsxMutexWait() error {
+// lsxMutexWait acquires the executor lock. The wait is bounded by the
+// context's deadline and the configured executor lock timeout.
+func (c *client) lsxMutexWait(ctx types.Context) (*lsxLock, error) {
 
 	if c.isController() {
-		return utils.NewUnsupportedForClientTypeError(
+		return nil, utils.NewUnsupportedForClientTypeError(
 			c.clientType, "lsxMutexWait")
 	}
 
-	for {
-		f, err := os.OpenFile(lsxMutex, os.O_CREATE|os.O_EXCL, 0644)
-		if err != nil {
-			time.Sleep(time.Millisecond * 500)
-			continue
-		}
-		return f.Close()
-	}
+	return lockExecutor(ctx, lsxMutex, c.getLSXLockTimeout())
 }
 
-func (c *client) lsxMutexSignal() error {
+func (c *client) lsxMutexSignal(lock *lsxLock) error {
 	if c.isController() {
 		return utils.NewUnsupportedForClientTypeError(
 			c.clientType, "lsxMutexSignal")
 	}
-	return os.RemoveAll(lsxMutex)
+	return lock.unlock()
 }
@@ -0,0 +1,147 @@
+package libstorage
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/akutz/goof"
+	gocontext "golang.org/x/net/context"
+
+	"github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/api/utils"
+)
+
+// lsxLockRetryInterval is how often an attempt is made to acquire the
+// executor lock while it is held by another process.
+const lsxLockRetryInterval = 100 * time.Millisecond
+
+// lsxLockHolder is the information that the holder of the executor lock
+// records in the lock file.
+type lsxLockHolder struct {
+	// PID is the ID of the process that holds the lock.
+	PID int `json:"pid"`
+
+	// StartTime is the epoch time at which the lock was acquired.
+	StartTime int64 `json:"startTime"`
+}
+
+// lsxLock is the lock that serializes the execution of the executor on a
+// host. The lock is released by the operating system if its holder exits
+// without releasing it.
+type lsxLock struct {
+	path string
+	f    *os.File
+}
+
+// lockExecutor acquires the executor lock at the specified path. An
+// ErrLockTimeout error is returned if the lock is not acquired before the
+// context's deadline or the timeout elapses. A timeout of zero waits until
+// the context is done.
+func lockExecutor(
+	ctx types.Context,
+	path string,
+	timeout time.Duration) (*lsxLock, error) {
+
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	l := &lsxLock{path: path}
+
+	for {
+		ok, err := l.tryLock()
+		if err != nil {
+			return nil, goof.WithFieldE(
+				"path", path, "error acquiring executor lock", err)
+		}
+		if ok {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			if ctx.Err() != gocontext.DeadlineExceeded {
+				return nil, goof.WithFieldE(
+					"path", path, "executor lock wait cancelled", ctx.Err())
+			}
+			return nil, l.timeoutError()
+		case <-expired:
+			return nil, l.timeoutError()
+		case <-time.After(lsxLockRetryInterval):
+		}
+	}
+
+	// a holder that releases the lock clears the lock file, so a recorded
+	// holder indicates the previous holder exited without releasing the lock
+	if prev := readLSXLockHolder(path); prev != nil && prev.PID > 0 {
+		ctx.WithFields(log.Fields{
+			"path":      path,
+			"holderPID": prev.PID,
+			"startTime": prev.StartTime,
+		}).Warn("acquired executor lock from stale holder")
+	}
+
+	buf, err := json.Marshal(&lsxLockHolder{
+		PID:       os.Getpid(),
+		StartTime: time.Now().Unix(),
+	})
+	if err != nil {
+		l.unlock()
+		return nil, err
+	}
+	if err := l.writeHolder(buf); err != nil {
+		l.unlock()
+		return nil, goof.WithFieldE(
+			"path", path, "error recording executor lock holder", err)
+	}
+
+	return l, nil
+}
+
+func (l *lsxLock) writeHolder(buf []byte) error {
+	if err := l.f.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := l.f.WriteAt(buf, 0); err != nil {
+		return err
+	}
+	return l.f.Sync()
+}
+
+func (l *lsxLock) timeoutError() error {
+	if holder := readLSXLockHolder(l.path); holder != nil {
+		return utils.NewLockTimeoutError(
+			l.path, holder.PID, holder.StartTime)
+	}
+	return utils.NewLockTimeoutError(l.path, 0, 0)
+}
+
+// readLSXLockHolder returns the holder recorded in the lock file at the
+// specified path, or nil if no holder is recorded.
+func readLSXLockHolder(path string) *lsxLockHolder {
+	buf, err := ioutil.ReadFile(path)
+	if err != nil || len(buf) == 0 {
+		return nil
+	}
+	holder := &lsxLockHolder{}
+	if err := json.Unmarshal(buf, holder); err != nil {
+		return nil
+	}
+	return holder
+}
+
+// getLSXLockTimeout returns the configured executor lock timeout.
+func (c *client) getLSXLockTimeout() time.Duration {
+	val := c.config.GetString(types.ConfigExecutorLockTimeout)
+	timeout, err := time.ParseDuration(val)
+	if err != nil {
+		return 5 * time.Minute
+	}
+	return timeout
+}
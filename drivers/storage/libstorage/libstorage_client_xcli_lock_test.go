@@ -0,0 +1,79 @@
+package libstorage
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	gocontext "golang.org/x/net/context"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+func newLockTestPath(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return filepath.Join(dir, lsxLockFileName), func() { os.RemoveAll(dir) }
+}
+
+func TestLockExecutor(t *testing.T) {
+	path, cleanup := newLockTestPath(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	l, err := lockExecutor(ctx, path, time.Second)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	holder := readLSXLockHolder(path)
+	if assert.NotNil(t, holder) {
+		assert.Equal(t, os.Getpid(), holder.PID)
+		assert.NotZero(t, holder.StartTime)
+	}
+
+	_, err = lockExecutor(ctx, path, 200*time.Millisecond)
+	if assert.IsType(t, &types.ErrLockTimeout{}, err) {
+		fields := err.(*types.ErrLockTimeout).Fields()
+		assert.Equal(t, path, fields["path"])
+		assert.Equal(t, os.Getpid(), fields["holderPID"])
+	}
+
+	goCtx, cancel := gocontext.WithTimeout(
+		gocontext.Background(), 200*time.Millisecond)
+	defer cancel()
+	_, err = lockExecutor(context.New(goCtx), path, 0)
+	assert.IsType(t, &types.ErrLockTimeout{}, err)
+
+	assert.NoError(t, l.unlock())
+	assert.Nil(t, readLSXLockHolder(path))
+
+	l, err = lockExecutor(ctx, path, time.Second)
+	assert.NoError(t, err)
+	assert.NoError(t, l.unlock())
+}
+
+func TestLockExecutorStaleHolder(t *testing.T) {
+	path, cleanup := newLockTestPath(t)
+	defer cleanup()
+
+	// a holder that exited without releasing the lock
+	err := ioutil.WriteFile(
+		path, []byte(`{"pid":2147483647,"startTime":1}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := lockExecutor(context.Background(), path, time.Second)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, os.Getpid(), readLSXLockHolder(path).PID)
+	assert.NoError(t, l.unlock())
+}
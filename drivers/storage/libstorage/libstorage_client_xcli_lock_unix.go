@@ -0,0 +1,37 @@
+// +build !windows
+
+package libstorage
+
+import "github.com/codedellemc/libstorage/api/utils"
+
+// lsxLockFileName is the name of the executor lock file. The name differs
+// from the "lsx.lock" file that older clients exclusively create and remove,
+// since this file is never removed and would otherwise block those clients
+// forever.
+const lsxLockFileName = "lsx.flock"
+
+// tryLock attempts to acquire an exclusive advisory lock on the lock file
+// without blocking. The lock file is never removed so that every process
+// locks the same file.
+func (l *lsxLock) tryLock() (bool, error) {
+	f, err := utils.TryLockFile(l.path)
+	if err != nil || f == nil {
+		return false, err
+	}
+	l.f = f
+	return true, nil
+}
+
+// unlock clears the recorded holder and releases the lock.
+func (l *lsxLock) unlock() error {
+	if l.f == nil {
+		return nil
+	}
+	defer func() { l.f = nil }()
+
+	if err := l.f.Truncate(0); err != nil {
+		l.f.Close()
+		return err
+	}
+	return utils.UnlockFile(l.f)
+}
@@ -0,0 +1,47 @@
+// +build windows
+
+package libstorage
+
+import (
+	"os"
+
+	"github.com/codedellemc/libstorage/api/utils"
+)
+
+// lsxLockFileName is the name of the executor lock file. The file is removed
+// when the lock is released, as older clients do.
+const lsxLockFileName = "lsx.lock"
+
+// tryLock attempts to acquire the lock by exclusively creating the lock
+// file. If the file exists but the process recorded as its holder no longer
+// exists then the file is removed so that the next attempt may succeed.
+func (l *lsxLock) tryLock() (bool, error) {
+	f, err := utils.TryLockFile(l.path)
+	if err != nil {
+		return false, err
+	}
+	if f != nil {
+		l.f = f
+		return true, nil
+	}
+
+	holder := readLSXLockHolder(l.path)
+	if holder == nil || holder.PID <= 0 {
+		return false, nil
+	}
+	if _, err := os.FindProcess(holder.PID); err != nil {
+		if err := os.Remove(l.path); err != nil && !os.IsNotExist(err) {
+			return false, err
+		}
+	}
+	return false, nil
+}
+
+// unlock releases the lock by removing the lock file.
+func (l *lsxLock) unlock() error {
+	if l.f == nil {
+		return nil
+	}
+	defer func() { l.f = nil }()
+	return utils.UnlockFile(l.f)
+}
@@ -79,6 +79,7 @@ func (d *driver) Init(ctx types.Context, config gofig.Config) error {
 	logRes := config.GetBool(types.ConfigLogHTTPResponses)
 	apiClient.LogRequests(logReq)
 	apiClient.LogResponses(logRes)
+	apiClient.SetAuthToken(config.GetString(types.ConfigClientAuthToken))
 
 	logFields["enableInstanceIDHeaders"] = EnableInstanceIDHeaders
 	logFields["enableLocalDevicesHeaders"] = EnableLocalDevicesHeaders
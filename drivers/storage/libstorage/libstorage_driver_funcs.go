@@ -2,6 +2,8 @@ package libstorage
 
 import (
 	"github.com/akutz/goof"
+
+	apiclient "github.com/codedellemc/libstorage/api/client"
 	"github.com/codedellemc/libstorage/api/context"
 	"github.com/codedellemc/libstorage/api/types"
 	"github.com/codedellemc/libstorage/api/utils"
@@ -75,11 +77,24 @@ func (d *driver) Volumes(
 		return nil, goof.New("missing service name")
 	}
 
+	// the page is forwarded to the server, which pages the volumes on
+	// behalf of the driver
+	var page *types.Page
+	if opts.Page != nil {
+		page = &types.Page{Limit: opts.Page.Limit, Token: opts.Page.Token}
+		ctx = apiclient.WithPage(ctx, page)
+	}
+
 	objMap, err := d.client.VolumesByService(ctx, serviceName, opts.Attachments)
 	if err != nil {
 		return nil, err
 	}
 
+	if page != nil {
+		opts.Page.Next = page.Next
+		opts.Page.Paged = true
+	}
+
 	objs := []*types.Volume{}
 	for _, o := range objMap {
 		objs = append(objs, o)
@@ -202,6 +217,110 @@ func (d *driver) VolumeRemove(
 	return d.client.VolumeRemove(ctx, serviceName, volumeID)
 }
 
+func (d *driver) VolumeResize(
+	ctx types.Context,
+	volumeID string,
+	opts *types.VolumeResizeOpts) (*types.Volume, error) {
+
+	ctx = d.requireCtx(ctx)
+	serviceName, ok := context.ServiceName(ctx)
+	if !ok {
+		return nil, goof.New("missing service name")
+	}
+
+	req := &types.VolumeResizeRequest{
+		Size: opts.Size,
+		Opts: opts.Opts.Map(),
+	}
+
+	vol, err := d.client.VolumeResize(ctx, serviceName, volumeID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if !d.isController() && d.config.GetBool(types.ConfigClientGrowFS) {
+		if err := d.growFS(ctx, volumeID, opts.Opts); err != nil {
+			return nil, err
+		}
+	}
+
+	return vol, nil
+}
+
+func (d *driver) VolumeUpdate(
+	ctx types.Context,
+	volumeID string,
+	opts *types.VolumeUpdateOpts) (*types.Volume, error) {
+
+	ctx = d.requireCtx(ctx)
+	serviceName, ok := context.ServiceName(ctx)
+	if !ok {
+		return nil, goof.New("missing service name")
+	}
+
+	req := &types.VolumeUpdateRequest{
+		Name:         opts.Name,
+		Fields:       opts.Fields,
+		RemoveFields: opts.RemoveFields,
+		Opts:         opts.Opts.Map(),
+	}
+
+	return d.client.VolumeUpdate(ctx, serviceName, volumeID, req)
+}
+
+// growFS grows the file systems of a resized volume's local mounts.
+func (d *driver) growFS(
+	ctx types.Context,
+	volumeID string,
+	opts types.Store) error {
+
+	client, ok := context.Client(ctx)
+	if !ok || client.OS() == nil {
+		ctx.Debug("skipping grow fs; missing os driver")
+		return nil
+	}
+
+	od, ok := client.OS().(types.OSDriverWithResize)
+	if !ok {
+		ctx.Debug("skipping grow fs; os driver cannot resize")
+		return nil
+	}
+
+	vol, err := d.VolumeInspect(
+		ctx,
+		volumeID,
+		&types.VolumeInspectOpts{
+			Attachments: types.VolAttReqWithDevMapForInstance,
+			Opts:        opts,
+		})
+	if err != nil {
+		return err
+	}
+
+	for _, a := range vol.Attachments {
+		if a.DeviceName == "" {
+			continue
+		}
+		mounts, err := od.Mounts(ctx, a.DeviceName, "", opts)
+		if err != nil {
+			return err
+		}
+		for _, m := range mounts {
+			err := od.Resize(ctx, a.DeviceName, m.MountPoint, opts)
+			if err == types.ErrNotImplemented {
+				ctx.WithField("deviceName", a.DeviceName).Warn(
+					"os driver does not support grow fs")
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
 func (d *driver) VolumeAttach(
 	ctx types.Context,
 	volumeID string,
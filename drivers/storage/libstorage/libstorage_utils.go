@@ -96,3 +96,9 @@ func (c *client) withAllLocalDevices(ctx types.Context) (types.Context, error) {
 
 	return ctx.WithValue(context.AllLocalDevicesKey, ldm), nil
 }
+
+// hasTargetInstance returns a flag indicating whether an attach or detach
+// request targets an explicit instance rather than the client's own.
+func hasTargetInstance(iid *types.InstanceID) bool {
+	return iid != nil && iid.ID != ""
+}
@@ -354,6 +354,77 @@ func (d *driver) VolumeRemove(
 	return nil
 }
 
+func (d *driver) VolumeResize(
+	ctx types.Context,
+	volumeID string,
+	opts *types.VolumeResizeOpts) (*types.Volume, error) {
+
+	ctx.WithFields(log.Fields{
+		"volumeID": volumeID,
+		"size":     opts.Size,
+	}).Debug("mockDriver.VolumeResize")
+
+	var modVol *types.Volume
+	for _, vol := range d.volumes {
+		if strings.ToLower(vol.ID) == strings.ToLower(volumeID) {
+			modVol = vol
+			break
+		}
+	}
+
+	if modVol == nil {
+		return nil, utils.NewNotFoundError(volumeID)
+	}
+
+	if opts.Size < modVol.Size {
+		return nil, goof.WithFields(goof.Fields{
+			"volumeID": volumeID,
+			"size":     modVol.Size,
+			"newSize":  opts.Size,
+		}, "cannot shrink volume")
+	}
+
+	modVol.Size = opts.Size
+
+	return modVol, nil
+}
+
+func (d *driver) VolumeUpdate(
+	ctx types.Context,
+	volumeID string,
+	opts *types.VolumeUpdateOpts) (*types.Volume, error) {
+
+	ctx.WithField("volumeID", volumeID).Debug("mockDriver.VolumeUpdate")
+
+	var modVol *types.Volume
+	for _, vol := range d.volumes {
+		if strings.ToLower(vol.ID) == strings.ToLower(volumeID) {
+			modVol = vol
+			break
+		}
+	}
+
+	if modVol == nil {
+		return nil, utils.NewNotFoundError(volumeID)
+	}
+
+	if opts.Name != nil {
+		modVol.Name = *opts.Name
+	}
+
+	if len(opts.Fields) > 0 && modVol.Fields == nil {
+		modVol.Fields = map[string]string{}
+	}
+	for k, v := range opts.Fields {
+		modVol.Fields[k] = v
+	}
+	for _, k := range opts.RemoveFields {
+		delete(modVol.Fields, k)
+	}
+
+	return modVol, nil
+}
+
 func (d *driver) VolumeAttach(
 	ctx types.Context,
 	volumeID string,
@@ -338,6 +338,23 @@ func TestVolumeRemove(t *testing.T) {
 	apitests.RunGroup(t, mock.Name, configYAML, tf1, tf2)
 }
 
+func TestVolumeResize(t *testing.T) {
+
+	tf := func(config gofig.Config, client types.Client, t *testing.T) {
+		volumeID := "vol-000"
+		request := &types.VolumeResizeRequest{Size: 20480}
+
+		reply, err := client.API().VolumeResize(
+			nil, mock.Name, volumeID, request)
+		assert.NoError(t, err)
+		apitests.LogAsJSON(reply, t)
+
+		assert.Equal(t, volumeID, reply.ID)
+		assert.Equal(t, request.Size, reply.Size)
+	}
+	apitests.Run(t, mock.Name, configYAML, tf)
+}
+
 func TestVolumeSnapshot(t *testing.T) {
 
 	tf := func(config gofig.Config, client types.Client, t *testing.T) {
@@ -5,6 +5,8 @@ package rackspace
 import (
 	gofigCore "github.com/akutz/gofig"
 	gofig "github.com/akutz/gofig/types"
+
+	"github.com/codedellemc/libstorage/api/registry"
 )
 
 // Name is the provider's name.
@@ -21,4 +23,6 @@ func init() {
 	r.Key(gofig.String, "", "", "", "rackspace.domainID")
 	r.Key(gofig.String, "", "", "", "rackspace.domainName")
 	gofigCore.Register(r)
+
+	registry.RegisterSecretConfigKeys("rackspace.password")
 }
@@ -6,6 +6,8 @@ import (
 	gofigCore "github.com/akutz/gofig"
 	gofig "github.com/akutz/gofig/types"
 	"github.com/akutz/goof"
+
+	"github.com/codedellemc/libstorage/api/registry"
 )
 
 const (
@@ -43,4 +45,6 @@ func init() {
 	r.Key(gofig.String, "", "", "", "scaleio.thinOrThick")
 	r.Key(gofig.String, "", "", "", "scaleio.version")
 	gofigCore.Register(r)
+
+	registry.RegisterSecretConfigKeys("scaleio.password")
 }
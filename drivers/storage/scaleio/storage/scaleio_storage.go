@@ -16,6 +16,7 @@ import (
 	"github.com/codedellemc/libstorage/api/context"
 	"github.com/codedellemc/libstorage/api/registry"
 	"github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/api/utils"
 	"github.com/codedellemc/libstorage/drivers/storage/scaleio"
 )
 
@@ -425,18 +426,45 @@ func (d *driver) VolumeCreateFromSnapshot(
 	return createdVolume, nil
 }
 
+// VolumeCopy is not implemented since ScaleIO copies a volume only by
+// snapshotting it, and a snapshot remains a member of its source volume's
+// tree.
 func (d *driver) VolumeCopy(
 	ctx types.Context,
 	volumeID, volumeName string,
 	opts types.Store) (*types.Volume, error) {
-	return nil, nil
+	return nil, types.ErrNotImplemented
 }
 
 func (d *driver) VolumeSnapshot(
 	ctx types.Context,
 	volumeID, snapshotName string,
 	opts types.Store) (*types.Snapshot, error) {
-	return nil, nil
+
+	fields := eff(map[string]interface{}{
+		"volumeId":     volumeID,
+		"snapshotName": snapshotName,
+	})
+
+	if volumeID == "" {
+		return nil, goof.New("no volumeID specified")
+	}
+
+	volumes, err := d.getVolume(volumeID, "", 0)
+	if err != nil {
+		return nil, goof.WithFieldsE(fields, "error getting volume", err)
+	}
+	if len(volumes) == 0 {
+		return nil, utils.NewNotFoundError(volumeID)
+	}
+
+	snapshot, err := d.createSnapshot(volumeID, snapshotName)
+	if err != nil {
+		return nil, goof.WithFieldsE(fields, "error creating snapshot", err)
+	}
+
+	log.WithFields(fields).Debug("created snapshot")
+	return snapshot, nil
 }
 
 func (d *driver) VolumeRemove(
@@ -466,6 +494,20 @@ func (d *driver) VolumeRemove(
 	return nil
 }
 
+func (d *driver) VolumeResize(
+	ctx types.Context,
+	volumeID string,
+	opts *types.VolumeResizeOpts) (*types.Volume, error) {
+	return nil, types.ErrNotImplemented
+}
+
+func (d *driver) VolumeUpdate(
+	ctx types.Context,
+	volumeID string,
+	opts *types.VolumeUpdateOpts) (*types.Volume, error) {
+	return nil, types.ErrNotImplemented
+}
+
 func (d *driver) VolumeAttach(
 	ctx types.Context,
 	volumeID string,
@@ -573,27 +615,86 @@ func (d *driver) VolumeDetachAll(
 func (d *driver) Snapshots(
 	ctx types.Context,
 	opts types.Store) ([]*types.Snapshot, error) {
-	return nil, nil
+
+	volumes, err := d.client.GetVolume("", "", "", "", true)
+	if err != nil {
+		return nil, goof.WithError("error getting snapshots", err)
+	}
+
+	snapshots := []*types.Snapshot{}
+	for _, volume := range volumes {
+		if volume.AncestorVolumeID == "" {
+			continue
+		}
+		snapshots = append(snapshots, toTypesSnapshot(volume))
+	}
+	return snapshots, nil
 }
 
 func (d *driver) SnapshotInspect(
 	ctx types.Context,
 	snapshotID string,
 	opts types.Store) (*types.Snapshot, error) {
-	return nil, nil
+
+	volume, err := d.getSnapshot(snapshotID)
+	if err != nil {
+		return nil, err
+	}
+	return toTypesSnapshot(volume), nil
 }
 
+// SnapshotCopy snapshots the snapshot, which ScaleIO supports as long as the
+// copy remains in the same system as the source snapshot.
 func (d *driver) SnapshotCopy(
 	ctx types.Context,
 	snapshotID, snapshotName, destinationID string,
 	opts types.Store) (*types.Snapshot, error) {
-	return nil, nil
+
+	fields := eff(map[string]interface{}{
+		"snapshotId":    snapshotID,
+		"snapshotName":  snapshotName,
+		"destinationId": destinationID,
+	})
+
+	if destinationID != "" && destinationID != d.system.System.ID {
+		return nil, types.ErrNotImplemented
+	}
+
+	if _, err := d.getSnapshot(snapshotID); err != nil {
+		return nil, err
+	}
+
+	snapshot, err := d.createSnapshot(snapshotID, snapshotName)
+	if err != nil {
+		return nil, goof.WithFieldsE(fields, "error copying snapshot", err)
+	}
+
+	log.WithFields(fields).Debug("copied snapshot")
+	return snapshot, nil
 }
 
 func (d *driver) SnapshotRemove(
 	ctx types.Context,
 	snapshotID string,
 	opts types.Store) error {
+
+	fields := eff(map[string]interface{}{
+		"snapshotId": snapshotID,
+	})
+
+	volume, err := d.getSnapshot(snapshotID)
+	if err != nil {
+		return err
+	}
+
+	targetVolume := sio.NewVolume(d.client)
+	targetVolume.Volume = volume
+
+	if err := targetVolume.RemoveVolume("ONLY_ME"); err != nil {
+		return goof.WithFieldsE(fields, "error removing snapshot", err)
+	}
+
+	log.WithFields(fields).Debug("removed snapshot")
 	return nil
 }
 
@@ -687,6 +788,62 @@ func (d *driver) createVolume(ctx types.Context, volumeName string,
 	return volumeResp, nil
 }
 
+// getSnapshot returns the ScaleIO volume that is the snapshot with the given
+// ID. A not found error is returned if the volume is not a snapshot.
+func (d *driver) getSnapshot(snapshotID string) (*siotypes.Volume, error) {
+	if snapshotID == "" {
+		return nil, goof.New("no snapshotID specified")
+	}
+
+	volumes, err := d.getVolume(snapshotID, "", 0)
+	if err != nil {
+		return nil, goof.WithFieldE(
+			"snapshotId", snapshotID, "error getting snapshot", err)
+	}
+	if len(volumes) == 0 || volumes[0].AncestorVolumeID == "" {
+		return nil, utils.NewNotFoundError(snapshotID)
+	}
+	return volumes[0], nil
+}
+
+// createSnapshot snapshots the volume, which may itself be a snapshot, and
+// returns the new snapshot.
+func (d *driver) createSnapshot(
+	volumeID, snapshotName string) (*types.Snapshot, error) {
+
+	resp, err := d.system.CreateSnapshotConsistencyGroup(
+		&siotypes.SnapshotVolumesParam{
+			SnapshotDefs: []*siotypes.SnapshotDef{
+				{
+					VolumeID:     volumeID,
+					SnapshotName: shrink(snapshotName),
+				},
+			},
+		})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.VolumeIDList) == 0 {
+		return nil, goof.New("no snapshot returned")
+	}
+
+	volume, err := d.getSnapshot(resp.VolumeIDList[0])
+	if err != nil {
+		return nil, err
+	}
+	return toTypesSnapshot(volume), nil
+}
+
+func toTypesSnapshot(volume *siotypes.Volume) *types.Snapshot {
+	return &types.Snapshot{
+		ID:         volume.ID,
+		Name:       volume.Name,
+		VolumeID:   volume.AncestorVolumeID,
+		VolumeSize: int64(volume.SizeInKb / 1024 / 1024),
+		StartTime:  int64(volume.CreationTime),
+	}
+}
+
 //TODO change provider to be dynamic...
 
 func eff(fields goof.Fields) map[string]interface{} {
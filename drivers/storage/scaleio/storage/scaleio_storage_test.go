@@ -0,0 +1,258 @@
+// +build !libstorage_storage_driver libstorage_storage_driver_scaleio
+
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	gofigCore "github.com/akutz/gofig"
+	siotypes "github.com/codedellemc/goscaleio/types/v1"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/api/utils"
+)
+
+// gateway is a stand-in for the ScaleIO gateway's REST API that serves the
+// requests the driver makes for a system with one protection domain and one
+// storage pool.
+type gateway struct {
+	sync.Mutex
+	volumes map[string]*siotypes.Volume
+	nextID  int
+}
+
+func newGateway() *gateway {
+	return &gateway{
+		volumes: map[string]*siotypes.Volume{
+			"vol-000": {
+				ID:            "vol-000",
+				Name:          "volume0",
+				SizeInKb:      8 * 1024 * 1024,
+				StoragePoolID: "sp-000",
+				CreationTime:  1500000000,
+				Links: []*siotypes.Link{
+					link("self", "/api/instances/Volume::vol-000"),
+				},
+			},
+		},
+	}
+}
+
+func link(rel, href string) *siotypes.Link {
+	return &siotypes.Link{Rel: rel, HREF: href}
+}
+
+func (g *gateway) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	g.Lock()
+	defer g.Unlock()
+
+	var (
+		p   = strings.TrimPrefix(req.URL.Path, "/api")
+		res interface{}
+	)
+
+	switch {
+	case p == "/version":
+		res = "2.0"
+	case p == "/login":
+		res = "token"
+	case p == "/types/System/instances":
+		res = []*siotypes.System{{
+			ID:   "sys-000",
+			Name: "cluster1",
+			Links: []*siotypes.Link{
+				link("self", "/api/instances/System::sys-000"),
+				link("/api/System/relationship/ProtectionDomain",
+					"/api/instances/System::sys-000"+
+						"/relationships/ProtectionDomain"),
+			},
+		}}
+	case strings.HasSuffix(p, "/relationships/ProtectionDomain"):
+		res = []*siotypes.ProtectionDomain{{
+			ID:       "pd-000",
+			Name:     "pdomain",
+			SystemID: "sys-000",
+			Links: []*siotypes.Link{
+				link("self", "/api/instances/ProtectionDomain::pd-000"),
+				link("/api/ProtectionDomain/relationship/StoragePool",
+					"/api/instances/ProtectionDomain::pd-000"+
+						"/relationships/StoragePool"),
+			},
+		}}
+	case strings.HasSuffix(p, "/relationships/StoragePool"),
+		p == "/types/StoragePool/instances":
+		res = []*siotypes.StoragePool{{
+			ID:                 "sp-000",
+			Name:               "pool1",
+			ProtectionDomainID: "pd-000",
+			Links: []*siotypes.Link{
+				link("self", "/api/instances/StoragePool::sp-000"),
+			},
+		}}
+	case p == "/types/Volume/instances":
+		volumes := []*siotypes.Volume{}
+		for _, v := range g.volumes {
+			volumes = append(volumes, v)
+		}
+		res = volumes
+	case strings.HasSuffix(p, "/action/snapshotVolumes"):
+		param := &siotypes.SnapshotVolumesParam{}
+		if err := json.NewDecoder(req.Body).Decode(param); err != nil {
+			g.writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		resp := &siotypes.SnapshotVolumesResp{SnapshotGroupID: "sg-000"}
+		for _, def := range param.SnapshotDefs {
+			src, ok := g.volumes[def.VolumeID]
+			if !ok {
+				g.writeError(w, http.StatusInternalServerError,
+					"Could not find the volume")
+				return
+			}
+			g.nextID++
+			id := fmt.Sprintf("snap-%03d", g.nextID)
+			g.volumes[id] = &siotypes.Volume{
+				ID:               id,
+				Name:             def.SnapshotName,
+				SizeInKb:         src.SizeInKb,
+				StoragePoolID:    src.StoragePoolID,
+				AncestorVolumeID: src.ID,
+				VolumeType:       "Snapshot",
+				CreationTime:     1500000000 + g.nextID,
+				Links: []*siotypes.Link{
+					link("self", "/api/instances/Volume::"+id),
+				},
+			}
+			resp.VolumeIDList = append(resp.VolumeIDList, id)
+		}
+		res = resp
+	case strings.HasSuffix(p, "/action/removeVolume"):
+		id := strings.TrimSuffix(
+			strings.TrimPrefix(p, "/instances/Volume::"),
+			"/action/removeVolume")
+		delete(g.volumes, id)
+		w.WriteHeader(http.StatusOK)
+		return
+	case strings.HasPrefix(p, "/instances/Volume::"):
+		v, ok := g.volumes[strings.TrimPrefix(p, "/instances/Volume::")]
+		if !ok {
+			g.writeError(w, http.StatusInternalServerError,
+				"Could not find the volume")
+			return
+		}
+		res = v
+	default:
+		g.writeError(w, http.StatusNotFound, "unknown path: "+p)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
+
+func (g *gateway) writeError(w http.ResponseWriter, code int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(&siotypes.Error{
+		Message:        msg,
+		MajorErrorCode: code,
+	})
+}
+
+const gatewayConfigFormat = `
+scaleio:
+  endpoint: %s/api
+  version: "2.0"
+  userName: admin
+  password: password
+  systemName: cluster1
+  protectionDomainName: pdomain
+  storagePoolName: pool1
+`
+
+func newTestDriver(t *testing.T) (*driver, func()) {
+	g := newGateway()
+	s := httptest.NewServer(g)
+
+	config := gofigCore.New()
+	buf := []byte(fmt.Sprintf(gatewayConfigFormat, s.URL))
+	if err := config.ReadConfig(bytes.NewReader(buf)); err != nil {
+		s.Close()
+		t.Fatal(err)
+	}
+
+	d := newDriver().(*driver)
+	if err := d.Init(context.Background(), config); err != nil {
+		s.Close()
+		t.Fatal(err)
+	}
+	return d, s.Close
+}
+
+func TestSnapshots(t *testing.T) {
+	d, closeGateway := newTestDriver(t)
+	defer closeGateway()
+
+	ctx := context.Background()
+
+	snap, err := d.VolumeSnapshot(ctx, "vol-000", "snap0", utils.NewStore())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, "snap0", snap.Name)
+	assert.Equal(t, "vol-000", snap.VolumeID)
+	assert.Equal(t, int64(8), snap.VolumeSize)
+	assert.NotZero(t, snap.StartTime)
+
+	_, err = d.VolumeSnapshot(ctx, "vol-001", "snap1", utils.NewStore())
+	assert.Error(t, err)
+
+	snaps, err := d.Snapshots(ctx, utils.NewStore())
+	assert.NoError(t, err)
+	if assert.Len(t, snaps, 1) {
+		assert.Equal(t, snap, snaps[0])
+	}
+
+	inspected, err := d.SnapshotInspect(ctx, snap.ID, utils.NewStore())
+	assert.NoError(t, err)
+	assert.Equal(t, snap, inspected)
+
+	// a volume is not a snapshot
+	_, err = d.SnapshotInspect(ctx, "vol-000", utils.NewStore())
+	assert.IsType(t, &types.ErrNotFound{}, err)
+
+	snapCopy, err := d.SnapshotCopy(
+		ctx, snap.ID, "snap0-copy", "", utils.NewStore())
+	assert.NoError(t, err)
+	assert.Equal(t, "snap0-copy", snapCopy.Name)
+	assert.Equal(t, snap.ID, snapCopy.VolumeID)
+
+	_, err = d.SnapshotCopy(
+		ctx, snap.ID, "snap0-copy", "sys-001", utils.NewStore())
+	assert.Equal(t, types.ErrNotImplemented, err)
+
+	assert.NoError(t, d.SnapshotRemove(ctx, snapCopy.ID, utils.NewStore()))
+	assert.NoError(t, d.SnapshotRemove(ctx, snap.ID, utils.NewStore()))
+
+	snaps, err = d.Snapshots(ctx, utils.NewStore())
+	assert.NoError(t, err)
+	assert.Empty(t, snaps)
+}
+
+func TestVolumeCopy(t *testing.T) {
+	d, closeGateway := newTestDriver(t)
+	defer closeGateway()
+
+	_, err := d.VolumeCopy(
+		context.Background(), "vol-000", "volume1", utils.NewStore())
+	assert.Equal(t, types.ErrNotImplemented, err)
+}
@@ -1,4 +1,4 @@
-// +build none
+// +build !libstorage_storage_driver libstorage_storage_driver_vbox
 
 package client
 
@@ -139,6 +139,19 @@ func (vb *VirtualBox) Logon() error {
 	return nil
 }
 
+// Logoff logs out of the soap server, which releases the session and all of
+// the object references obtained with it. The session's object reference is
+// cleared even if the request fails so that the next Logon starts a new
+// session.
+func (vb *VirtualBox) Logoff() error {
+	if vb.mobref == "" {
+		return nil
+	}
+	request := logoffRequest{VbID: vb.mobref}
+	vb.mobref = ""
+	return vb.send(request, new(logoffResponse))
+}
+
 // FindMachine finds a machine based on its name or machine id.
 func (vb *VirtualBox) FindMachine(nameOrID string) (*Machine, error) {
 	if err := vb.assertMobRef(); err != nil {
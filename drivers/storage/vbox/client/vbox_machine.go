@@ -1,4 +1,4 @@
-// +build none
+// +build !libstorage_storage_driver libstorage_storage_driver_vbox
 
 package client
 
@@ -0,0 +1,159 @@
+// +build !libstorage_storage_driver libstorage_storage_driver_vbox
+
+package client
+
+import (
+	"fmt"
+)
+
+// Medium represents a virtual hard disk in vbox.
+type Medium struct {
+	mobref      string
+	id          string
+	location    string
+	logicalSize int64
+	vb          *VirtualBox
+}
+
+// GetID returns the ID last populated for this medium
+func (m *Medium) GetID() string {
+	return m.id
+}
+
+// GetLocation returns the location last populated for this medium
+func (m *Medium) GetLocation() string {
+	return m.location
+}
+
+// GetLogicalSize returns the logical size, in bytes, last populated for this
+// medium
+func (m *Medium) GetLogicalSize() int64 {
+	return m.logicalSize
+}
+
+// Release releases the medium's object reference. The medium itself is not
+// affected.
+func (m *Medium) Release() error {
+	return m.vb.Release(m.mobref)
+}
+
+// OpenMedium opens the hard disk at the given location. The medium that is
+// already registered at the location is returned if there is one.
+func (vb *VirtualBox) OpenMedium(location string) (*Medium, error) {
+	if err := vb.assertMobRef(); err != nil {
+		return nil, err
+	}
+
+	request := openMediumRequest{
+		VbID:       vb.mobref,
+		Location:   location,
+		DeviceType: "HardDisk",
+		AccessMode: "ReadWrite",
+	}
+	response := new(openMediumResponse)
+	if err := vb.send(request, response); err != nil {
+		return nil, err
+	}
+
+	return &Medium{mobref: response.Returnval, vb: vb}, nil
+}
+
+// CreateMedium creates a hard disk of the given format at the given location.
+// The medium has no storage until it is the target of a clone.
+func (vb *VirtualBox) CreateMedium(format, location string) (*Medium, error) {
+	if err := vb.assertMobRef(); err != nil {
+		return nil, err
+	}
+
+	request := createMediumRequest{
+		VbID:       vb.mobref,
+		Format:     format,
+		Location:   location,
+		AccessMode: "ReadWrite",
+		DeviceType: "HardDisk",
+	}
+	response := new(createMediumResponse)
+	if err := vb.send(request, response); err != nil {
+		return nil, err
+	}
+
+	return &Medium{mobref: response.Returnval, vb: vb}, nil
+}
+
+// CloneMedium copies the source medium's contents to the target medium and
+// blocks until the copy is complete.
+func (vb *VirtualBox) CloneMedium(source, target *Medium) error {
+	if source.mobref == "" || target.mobref == "" {
+		return fmt.Errorf("Medium missing object reference id")
+	}
+
+	request := cloneToRequest{
+		Mobref:  source.mobref,
+		Target:  target.mobref,
+		Variant: []string{"Standard"},
+	}
+	response := new(cloneToResponse)
+	if err := vb.send(request, response); err != nil {
+		return err
+	}
+
+	progress := response.Returnval
+	defer vb.Release(progress)
+
+	// a negative timeout waits until the clone is complete
+	req1 := waitForCompletionRequest{Mobref: progress, Timeout: -1}
+	rsp1 := new(waitForCompletionResponse)
+	if err := vb.send(req1, rsp1); err != nil {
+		return err
+	}
+
+	req2 := getResultCodeRequest{Mobref: progress}
+	rsp2 := new(getResultCodeResponse)
+	if err := vb.send(req2, rsp2); err != nil {
+		return err
+	}
+	if rsp2.Returnval != 0 {
+		return fmt.Errorf(
+			"Failed to clone medium: result code %#x", uint32(rsp2.Returnval))
+	}
+
+	return nil
+}
+
+// PopulateMediumInfo loads additional descriptive information for medium
+func (vb *VirtualBox) PopulateMediumInfo(medium *Medium) error {
+	if medium.mobref == "" {
+		return fmt.Errorf("Medium missing object reference id")
+	}
+
+	req1 := getMediumIDRequest{Mobref: medium.mobref}
+	rsp1 := new(getMediumIDResponse)
+	if err := vb.send(req1, rsp1); err != nil {
+		return err
+	}
+	medium.id = rsp1.Returnval
+
+	req2 := getMediumLocationRequest{Mobref: medium.mobref}
+	rsp2 := new(getMediumLocationResponse)
+	if err := vb.send(req2, rsp2); err != nil {
+		return err
+	}
+	medium.location = rsp2.Returnval
+
+	req3 := getMediumLogicalSizeRequest{Mobref: medium.mobref}
+	rsp3 := new(getMediumLogicalSizeResponse)
+	if err := vb.send(req3, rsp3); err != nil {
+		return err
+	}
+	medium.logicalSize = rsp3.Returnval
+
+	return nil
+}
+
+// Release releases the managed object with the given reference id.
+func (vb *VirtualBox) Release(mobref string) error {
+	if mobref == "" {
+		return nil
+	}
+	return vb.send(releaseRequest{Mobref: mobref}, new(releaseResponse))
+}
@@ -0,0 +1,265 @@
+// +build !libstorage_storage_driver libstorage_storage_driver_vbox
+
+package client
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const (
+	fakeSession   = "000-session-000"
+	fakeFileError = int32(-2135228404) // VBOX_E_FILE_ERROR
+)
+
+// fakeVboxwebsrv is a stand-in for vboxwebsrv that keeps its media in memory.
+type fakeVboxwebsrv struct {
+	sync.Mutex
+	*httptest.Server
+
+	// storage is the size of the medium stored at each location
+	storage map[string]int64
+
+	// refs are the managed objects, which are either media or progresses
+	refs     map[string]*fakeObject
+	nextRef  int
+	released []string
+
+	// sessions is the number of sessions that are logged on
+	sessions int
+}
+
+type fakeObject struct {
+	location   string
+	id         string
+	resultCode int32
+}
+
+type fakeRequest struct {
+	XMLName  xml.Name
+	This     string `xml:"_this"`
+	VbID     string `xml:"refIVirtualBox"`
+	Username string `xml:"username"`
+	Location string `xml:"location"`
+	Target   string `xml:"target"`
+}
+
+func newFakeVboxwebsrv(storage map[string]int64) *fakeVboxwebsrv {
+	s := &fakeVboxwebsrv{
+		storage: storage,
+		refs:    map[string]*fakeObject{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+func (s *fakeVboxwebsrv) newRef(o *fakeObject) string {
+	s.nextRef++
+	ref := fmt.Sprintf("%03d-ref-%03d", s.nextRef, s.nextRef)
+	s.refs[ref] = o
+	return ref
+}
+
+func (s *fakeVboxwebsrv) handle(resp http.ResponseWriter, req *http.Request) {
+	s.Lock()
+	defer s.Unlock()
+
+	env := new(envelope)
+	r := new(fakeRequest)
+	if err := xml.NewDecoder(req.Body).Decode(env); err != nil {
+		s.fault(resp, err.Error())
+		return
+	}
+	if err := xml.Unmarshal(env.Body.Payload, r); err != nil {
+		s.fault(resp, err.Error())
+		return
+	}
+
+	op := r.XMLName.Local
+	if !strings.HasPrefix(op, "IWebsessionManager_") && r.This == "" {
+		s.fault(resp, "missing _this")
+		return
+	}
+	obj := s.refs[r.This]
+
+	var returnval interface{}
+
+	switch op {
+	case "IWebsessionManager_logon":
+		s.sessions++
+		returnval = fakeSession
+	case "IWebsessionManager_logoff":
+		if r.VbID != fakeSession || s.sessions == 0 {
+			s.fault(resp, "invalid session")
+			return
+		}
+		s.sessions--
+	case "IVirtualBox_openMedium":
+		if _, ok := s.storage[r.Location]; !ok {
+			s.fault(resp, "medium not found: "+r.Location)
+			return
+		}
+		returnval = s.newRef(&fakeObject{
+			location: r.Location,
+			id:       "medium-" + r.Location,
+		})
+	case "IVirtualBox_createMedium":
+		returnval = s.newRef(&fakeObject{location: r.Location})
+	case "IMedium_cloneTo":
+		target := s.refs[r.Target]
+		if obj == nil || target == nil {
+			s.fault(resp, "invalid medium")
+			return
+		}
+		progress := &fakeObject{}
+		if _, ok := s.storage[target.location]; ok {
+			progress.resultCode = fakeFileError
+		} else {
+			s.storage[target.location] = s.storage[obj.location]
+			target.id = "medium-" + target.location
+		}
+		returnval = s.newRef(progress)
+	case "IProgress_waitForCompletion", "IManagedObjectRef_release":
+		if obj == nil {
+			s.fault(resp, "invalid object")
+			return
+		}
+		if op == "IManagedObjectRef_release" {
+			delete(s.refs, r.This)
+			s.released = append(s.released, r.This)
+		}
+	case "IProgress_getResultCode":
+		returnval = obj.resultCode
+	case "IMedium_getId":
+		returnval = obj.id
+	case "IMedium_getLocation":
+		returnval = obj.location
+	case "IMedium_getLogicalSize":
+		returnval = s.storage[obj.location]
+	default:
+		s.fault(resp, "unsupported operation: "+op)
+		return
+	}
+
+	payload := fmt.Sprintf("<vbox:%sResponse>", op)
+	if returnval != nil {
+		payload += fmt.Sprintf("<returnval>%v</returnval>", returnval)
+	}
+	payload += fmt.Sprintf("</vbox:%sResponse>", op)
+
+	resp.WriteHeader(http.StatusOK)
+	resp.Write([]byte(fmt.Sprintf(xmlEnvelope, payload)))
+}
+
+func (s *fakeVboxwebsrv) fault(resp http.ResponseWriter, msg string) {
+	payload := fmt.Sprintf(`<SOAP-ENV:Fault>
+	<faultcode>SOAP-ENV:Client</faultcode>
+	<faultstring>%s</faultstring>
+	</SOAP-ENV:Fault>`, msg)
+	resp.WriteHeader(http.StatusInternalServerError)
+	resp.Write([]byte(fmt.Sprintf(xmlEnvelope, payload)))
+}
+
+func newFakeSession(t *testing.T, s *fakeVboxwebsrv) *VirtualBox {
+	vb := NewVirtualBox(uname, password, s.URL)
+	if err := vb.Logon(); err != nil {
+		t.Fatal("Logon failed:", err)
+	}
+	return vb
+}
+
+func TestCloneMedium(t *testing.T) {
+	server := newFakeVboxwebsrv(map[string]int64{"/vols/vol-1": 1 << 30})
+	defer server.Close()
+	vb := newFakeSession(t, server)
+
+	source, err := vb.OpenMedium("/vols/vol-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	target, err := vb.CreateMedium("vmdk", "/vols/snap-1.vmdk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := vb.CloneMedium(source, target); err != nil {
+		t.Fatal(err)
+	}
+	if err := vb.PopulateMediumInfo(target); err != nil {
+		t.Fatal(err)
+	}
+
+	if target.GetID() != "medium-/vols/snap-1.vmdk" {
+		t.Fatal("Medium id not set properly")
+	}
+	if target.GetLocation() != "/vols/snap-1.vmdk" {
+		t.Fatal("Medium location not set properly")
+	}
+	if target.GetLogicalSize() != 1<<30 {
+		t.Fatal("Medium size not set properly")
+	}
+	if _, ok := server.storage["/vols/snap-1.vmdk"]; !ok {
+		t.Fatal("Medium not cloned")
+	}
+	if len(server.released) != 1 {
+		t.Fatal("Clone progress not released")
+	}
+}
+
+func TestCloneMedium_Failed(t *testing.T) {
+	server := newFakeVboxwebsrv(map[string]int64{
+		"/vols/vol-1": 1 << 30,
+		"/vols/vol-2": 1 << 30,
+	})
+	defer server.Close()
+	vb := newFakeSession(t, server)
+
+	source, err := vb.OpenMedium("/vols/vol-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the target's location is already in use
+	target, err := vb.CreateMedium("vmdk", "/vols/vol-2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := vb.CloneMedium(source, target); err == nil {
+		t.Fatal("Expected failure")
+	}
+}
+
+func TestOpenMedium_NotFound(t *testing.T) {
+	server := newFakeVboxwebsrv(map[string]int64{})
+	defer server.Close()
+	vb := newFakeSession(t, server)
+
+	if _, err := vb.OpenMedium("/vols/vol-1"); err == nil {
+		t.Fatal("Expected failure")
+	}
+}
+
+func TestLogoff(t *testing.T) {
+	server := newFakeVboxwebsrv(map[string]int64{})
+	defer server.Close()
+	vb := newFakeSession(t, server)
+
+	if err := vb.Logoff(); err != nil {
+		t.Fatal(err)
+	}
+	if server.sessions != 0 {
+		t.Fatal("Session not logged off")
+	}
+
+	// a session that is not logged on is not logged off again
+	if err := vb.Logoff(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := vb.OpenMedium("/vols/vol-1"); err == nil {
+		t.Fatal("Expected failure")
+	}
+}
@@ -1,4 +1,4 @@
-// +build none
+// +build !libstorage_storage_driver libstorage_storage_driver_vbox
 
 package client
 
@@ -33,6 +33,15 @@ type logonResponse struct {
 	Returnval string   `xml:"returnval,omitempty"`
 }
 
+type logoffRequest struct {
+	XMLName xml.Name `xml:"http://www.virtualbox.org/ IWebsessionManager_logoff"`
+	VbID    string   `xml:"refIVirtualBox,omitempty"`
+}
+
+type logoffResponse struct {
+	XMLName xml.Name `xml:"IWebsessionManager_logoffResponse"`
+}
+
 type findMachineRequest struct {
 	XMLName  xml.Name `xml:"http://www.virtualbox.org/ IVirtualBox_findMachine"`
 	VbID     string   `xml:"_this,omitempty"`
@@ -99,3 +108,103 @@ type getMediumAttachmentsResponse struct {
 	XMLName   xml.Name            `xml:"IMachine_getMediumAttachmentsResponse"`
 	Returnval []*mediumAttachment `xml:"returnval,omitempty"`
 }
+
+type openMediumRequest struct {
+	XMLName      xml.Name `xml:"http://www.virtualbox.org/ IVirtualBox_openMedium"`
+	VbID         string   `xml:"_this,omitempty"`
+	Location     string   `xml:"location,omitempty"`
+	DeviceType   string   `xml:"deviceType,omitempty"`
+	AccessMode   string   `xml:"accessMode,omitempty"`
+	ForceNewUUID bool     `xml:"forceNewUuid"`
+}
+
+type openMediumResponse struct {
+	XMLName   xml.Name `xml:"IVirtualBox_openMediumResponse"`
+	Returnval string   `xml:"returnval,omitempty"`
+}
+
+type createMediumRequest struct {
+	XMLName    xml.Name `xml:"http://www.virtualbox.org/ IVirtualBox_createMedium"`
+	VbID       string   `xml:"_this,omitempty"`
+	Format     string   `xml:"format,omitempty"`
+	Location   string   `xml:"location,omitempty"`
+	AccessMode string   `xml:"accessMode,omitempty"`
+	DeviceType string   `xml:"aDeviceTypeType,omitempty"`
+}
+
+type createMediumResponse struct {
+	XMLName   xml.Name `xml:"IVirtualBox_createMediumResponse"`
+	Returnval string   `xml:"returnval,omitempty"`
+}
+
+type cloneToRequest struct {
+	XMLName xml.Name `xml:"http://www.virtualbox.org/ IMedium_cloneTo"`
+	Mobref  string   `xml:"_this,omitempty"`
+	Target  string   `xml:"target,omitempty"`
+	Variant []string `xml:"variant,omitempty"`
+	Parent  string   `xml:"parent,omitempty"`
+}
+
+type cloneToResponse struct {
+	XMLName   xml.Name `xml:"IMedium_cloneToResponse"`
+	Returnval string   `xml:"returnval,omitempty"`
+}
+
+type getMediumIDRequest struct {
+	XMLName xml.Name `xml:"http://www.virtualbox.org/ IMedium_getId"`
+	Mobref  string   `xml:"_this,omitempty"`
+}
+
+type getMediumIDResponse struct {
+	XMLName   xml.Name `xml:"IMedium_getIdResponse"`
+	Returnval string   `xml:"returnval,omitempty"`
+}
+
+type getMediumLocationRequest struct {
+	XMLName xml.Name `xml:"http://www.virtualbox.org/ IMedium_getLocation"`
+	Mobref  string   `xml:"_this,omitempty"`
+}
+
+type getMediumLocationResponse struct {
+	XMLName   xml.Name `xml:"IMedium_getLocationResponse"`
+	Returnval string   `xml:"returnval,omitempty"`
+}
+
+type getMediumLogicalSizeRequest struct {
+	XMLName xml.Name `xml:"http://www.virtualbox.org/ IMedium_getLogicalSize"`
+	Mobref  string   `xml:"_this,omitempty"`
+}
+
+type getMediumLogicalSizeResponse struct {
+	XMLName   xml.Name `xml:"IMedium_getLogicalSizeResponse"`
+	Returnval int64    `xml:"returnval,omitempty"`
+}
+
+type waitForCompletionRequest struct {
+	XMLName xml.Name `xml:"http://www.virtualbox.org/ IProgress_waitForCompletion"`
+	Mobref  string   `xml:"_this,omitempty"`
+	Timeout int32    `xml:"timeout"`
+}
+
+type waitForCompletionResponse struct {
+	XMLName xml.Name `xml:"IProgress_waitForCompletionResponse"`
+}
+
+type getResultCodeRequest struct {
+	XMLName xml.Name `xml:"http://www.virtualbox.org/ IProgress_getResultCode"`
+	Mobref  string   `xml:"_this,omitempty"`
+}
+
+type getResultCodeResponse struct {
+	XMLName   xml.Name `xml:"IProgress_getResultCodeResponse"`
+	Returnval int32    `xml:"returnval,omitempty"`
+}
+
+type releaseRequest struct {
+	XMLName xml.Name `xml:"http://www.virtualbox.org/ IManagedObjectRef_release"`
+	Mobref  string   `xml:"_this,omitempty"`
+}
+
+type releaseResponse struct {
+	XMLName xml.Name `xml:"IManagedObjectRef_releaseResponse"`
+}
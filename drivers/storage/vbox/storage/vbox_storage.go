@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"time"
 
 	log "github.com/Sirupsen/logrus"
 	gofig "github.com/akutz/gofig/types"
@@ -17,7 +18,9 @@ import (
 	"github.com/codedellemc/libstorage/api/context"
 	"github.com/codedellemc/libstorage/api/registry"
 	"github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/api/utils"
 	"github.com/codedellemc/libstorage/drivers/storage/vbox"
+	vboxs "github.com/codedellemc/libstorage/drivers/storage/vbox/client"
 )
 
 // Driver represents a vbox driver implementation of StorageDriver
@@ -25,6 +28,8 @@ type driver struct {
 	sync.Mutex
 	config gofig.Config
 	vbox   *vboxc.VirtualBox
+	web    *vboxs.VirtualBox
+	webL   sync.Mutex
 }
 
 func init() {
@@ -64,6 +69,10 @@ func (d *driver) Init(ctx types.Context, config gofig.Config) error {
 			"error logging in", err)
 	}
 
+	// media are cloned with a web service session that is logged on for
+	// each clone
+	d.web = vboxs.NewVirtualBox(d.username(), d.password(), d.endpoint())
+
 	ctx.WithFields(fields).Info("storage driver initialized")
 	return nil
 }
@@ -266,28 +275,134 @@ func (d *driver) VolumeCreate(ctx types.Context, volumeName string,
 	return newVol, nil
 }
 
-// VolumeCreateFromSnapshot (not implemented).
+// VolumeCreateFromSnapshot creates a new volume from a snapshot by cloning
+// the snapshot's medium.
 func (d *driver) VolumeCreateFromSnapshot(
 	ctx types.Context,
 	snapshotID, volumeName string,
 	opts *types.VolumeCreateOpts) (*types.Volume, error) {
-	return nil, types.ErrNotImplemented
+
+	d.Lock()
+	defer d.Unlock()
+	if err := d.refreshSession(ctx); err != nil {
+		return nil, err
+	}
+
+	fields := map[string]interface{}{
+		"provider":   vbox.Name,
+		"snapshotID": snapshotID,
+		"volumeName": volumeName,
+	}
+
+	md, err := d.getSnapshotByID(snapshotID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := d.assertNoVolume(ctx, volumeName); err != nil {
+		return nil, err
+	}
+
+	med, err := d.cloneMedium(
+		ctx, md.Location, filepath.Join(d.volumePath(), volumeName))
+	if err != nil {
+		return nil, goof.WithFieldsE(
+			fields, "error creating volume from snapshot", err)
+	}
+
+	return d.VolumeInspect(
+		ctx, med.GetID(), &types.VolumeInspectOpts{
+			Attachments: types.VolAttFalse})
 }
 
-// VolumeCopy copies an existing volume (not implemented)
+// VolumeCopy copies an existing volume by cloning its medium.
 func (d *driver) VolumeCopy(
 	ctx types.Context,
 	volumeID, volumeName string,
 	opts types.Store) (*types.Volume, error) {
-	return nil, types.ErrNotImplemented
+
+	d.Lock()
+	defer d.Unlock()
+	if err := d.refreshSession(ctx); err != nil {
+		return nil, err
+	}
+
+	fields := map[string]interface{}{
+		"provider":   vbox.Name,
+		"volumeID":   volumeID,
+		"volumeName": volumeName,
+	}
+
+	src, err := d.getMedium(volumeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := d.assertNoVolume(ctx, volumeName); err != nil {
+		return nil, err
+	}
+
+	med, err := d.cloneMedium(
+		ctx, src.Location, filepath.Join(d.volumePath(), volumeName))
+	if err != nil {
+		return nil, goof.WithFieldsE(fields, "error copying volume", err)
+	}
+
+	return d.VolumeInspect(
+		ctx, med.GetID(), &types.VolumeInspectOpts{
+			Attachments: types.VolAttFalse})
 }
 
-// VolumeSnapshot snapshots a volume (not implemented)
+// VolumeSnapshot snapshots a volume by cloning its medium. The snapshot's
+// metadata is kept alongside the clone's .vmdk file.
 func (d *driver) VolumeSnapshot(
 	ctx types.Context,
 	volumeID, snapshotName string,
 	opts types.Store) (*types.Snapshot, error) {
-	return nil, types.ErrNotImplemented
+
+	d.Lock()
+	defer d.Unlock()
+	if err := d.refreshSession(ctx); err != nil {
+		return nil, err
+	}
+
+	fields := map[string]interface{}{
+		"provider":     vbox.Name,
+		"volumeID":     volumeID,
+		"snapshotName": snapshotName,
+	}
+
+	src, err := d.getMedium(volumeID)
+	if err != nil {
+		return nil, err
+	}
+
+	startTime := time.Now().Unix()
+	med, err := d.cloneMedium(ctx, src.Location, d.newSnapshotLocation())
+	if err != nil {
+		return nil, goof.WithFieldsE(fields, "error snapshotting volume", err)
+	}
+
+	md := &snapshotMetadata{
+		Location: med.GetLocation(),
+		Snapshot: &types.Snapshot{
+			ID:         med.GetID(),
+			Name:       snapshotName,
+			VolumeID:   src.ID,
+			VolumeSize: int64(src.LogicalSize / 1024 / 1024 / 1024),
+			Status:     "completed",
+			StartTime:  startTime,
+			Fields:     getCustomFields(nil, opts),
+		},
+	}
+
+	if err := d.writeSnapshot(md); err != nil {
+		d.vbox.RemoveMedium(md.Snapshot.ID)
+		return nil, goof.WithFieldsE(
+			fields, "error writing snapshot metadata", err)
+	}
+
+	return md.Snapshot, nil
 }
 
 // VolumeRemove removes a volume.
@@ -315,6 +430,21 @@ func (d *driver) VolumeRemove(
 	return nil
 }
 
+// VolumeResize resizes a volume (not implemented).
+func (d *driver) VolumeResize(
+	ctx types.Context,
+	volumeID string,
+	opts *types.VolumeResizeOpts) (*types.Volume, error) {
+	return nil, types.ErrNotImplemented
+}
+
+func (d *driver) VolumeUpdate(
+	ctx types.Context,
+	volumeID string,
+	opts *types.VolumeUpdateOpts) (*types.Volume, error) {
+	return nil, types.ErrNotImplemented
+}
+
 // VolumeAttach attaches a volume.
 func (d *driver) VolumeAttach(
 	ctx types.Context,
@@ -418,31 +548,116 @@ func (d *driver) VolumeDetachAll(
 	return nil
 }
 
+// Snapshots returns all snapshots.
 func (d *driver) Snapshots(
 	ctx types.Context,
 	opts types.Store) ([]*types.Snapshot, error) {
-	return nil, nil
+
+	snaps, err := d.getSnapshots()
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshots []*types.Snapshot
+	for _, md := range snaps {
+		snapshots = append(snapshots, md.Snapshot)
+	}
+	return snapshots, nil
 }
 
+// SnapshotInspect returns a snapshot.
 func (d *driver) SnapshotInspect(
 	ctx types.Context,
 	snapshotID string,
 	opts types.Store) (*types.Snapshot, error) {
-	return nil, nil
+
+	md, err := d.getSnapshotByID(snapshotID)
+	if err != nil {
+		return nil, err
+	}
+	return md.Snapshot, nil
 }
 
+// SnapshotCopy copies a snapshot by cloning its medium. The destination is
+// ignored since all media belong to the same VirtualBox host.
 func (d *driver) SnapshotCopy(
 	ctx types.Context,
 	snapshotID, snapshotName, destinationID string,
 	opts types.Store) (*types.Snapshot, error) {
-	return nil, nil
+
+	d.Lock()
+	defer d.Unlock()
+	if err := d.refreshSession(ctx); err != nil {
+		return nil, err
+	}
+
+	fields := map[string]interface{}{
+		"provider":     vbox.Name,
+		"snapshotID":   snapshotID,
+		"snapshotName": snapshotName,
+	}
+
+	ogMD, err := d.getSnapshotByID(snapshotID)
+	if err != nil {
+		return nil, err
+	}
+
+	startTime := time.Now().Unix()
+	med, err := d.cloneMedium(ctx, ogMD.Location, d.newSnapshotLocation())
+	if err != nil {
+		return nil, goof.WithFieldsE(fields, "error copying snapshot", err)
+	}
+
+	md := &snapshotMetadata{
+		Location: med.GetLocation(),
+		Snapshot: &types.Snapshot{
+			ID:          med.GetID(),
+			Name:        snapshotName,
+			Description: ogMD.Snapshot.Description,
+			VolumeID:    ogMD.Snapshot.VolumeID,
+			VolumeSize:  ogMD.Snapshot.VolumeSize,
+			Status:      "completed",
+			StartTime:   startTime,
+			Fields:      getCustomFields(ogMD.Snapshot.Fields, opts),
+		},
+	}
+
+	if err := d.writeSnapshot(md); err != nil {
+		d.vbox.RemoveMedium(md.Snapshot.ID)
+		return nil, goof.WithFieldsE(
+			fields, "error writing snapshot metadata", err)
+	}
+
+	return md.Snapshot, nil
 }
 
+// SnapshotRemove removes a snapshot's medium and its metadata.
 func (d *driver) SnapshotRemove(
 	ctx types.Context,
 	snapshotID string,
 	opts types.Store) error {
-	return nil
+
+	d.Lock()
+	defer d.Unlock()
+	if err := d.refreshSession(ctx); err != nil {
+		return err
+	}
+
+	fields := map[string]interface{}{
+		"provider":   vbox.Name,
+		"snapshotID": snapshotID,
+	}
+
+	md, err := d.getSnapshotByID(snapshotID)
+	if err != nil {
+		return err
+	}
+
+	if err := d.vbox.RemoveMedium(snapshotID); err != nil {
+		return goof.WithFieldsE(fields, "error deleting snapshot", err)
+	}
+
+	return d.removeSnapshot(md)
 }
 
 func (d *driver) Volumes(
@@ -498,6 +713,16 @@ func (d *driver) getVolume(
 		return nil, nil
 	}
 
+	// the media of snapshots are not volumes
+	snaps, err := d.getSnapshots()
+	if err != nil {
+		return nil, err
+	}
+	snapIDs := map[string]bool{}
+	for _, md := range snaps {
+		snapIDs[md.Snapshot.ID] = true
+	}
+
 	var mapDN map[string]string
 	if attachments.Devices() {
 		volumeMapping, err := d.getVolumeMapping(ctx)
@@ -514,6 +739,10 @@ func (d *driver) getVolume(
 	var volumesSD []*types.Volume
 
 	for _, v := range volumes {
+		if snapIDs[v.ID] {
+			continue
+		}
+
 		volumeSD := &types.Volume{
 			Name:   v.Name,
 			ID:     v.ID,
@@ -664,6 +893,99 @@ func (d *driver) createVolume(
 	return d.vbox.CreateMedium("vmdk", path, size)
 }
 
+// assertNoVolume returns an error if a volume with the given name exists.
+func (d *driver) assertNoVolume(ctx types.Context, volumeName string) error {
+	vol, err := d.getVolume(ctx, "", volumeName, types.VolAttFalse)
+	if err != nil {
+		return err
+	}
+	if vol != nil {
+		return goof.New("volume already exists")
+	}
+	return nil
+}
+
+// getMedium returns the medium with the given ID.
+func (d *driver) getMedium(mediumID string) (*vboxc.Medium, error) {
+	media, err := d.vbox.GetMedium(mediumID, "")
+	if err != nil {
+		return nil, err
+	}
+	if len(media) == 0 {
+		return nil, utils.NewNotFoundError(mediumID)
+	}
+	return media[0], nil
+}
+
+// cloneMedium clones the medium at the source location to a new medium at
+// the given location. The clones share the driver's web service session,
+// which is logged on for the clone and logged off once the clone is complete
+// so that no sessions are left open on the server. The returned medium's
+// object reference is released along with the session.
+func (d *driver) cloneMedium(
+	ctx types.Context, srcLocation, location string) (*vboxs.Medium, error) {
+
+	ctx.WithFields(log.Fields{
+		"source":   srcLocation,
+		"location": location,
+	}).Debug("cloning medium")
+
+	d.webL.Lock()
+	defer d.webL.Unlock()
+
+	if err := d.web.Logon(); err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := d.web.Logoff(); err != nil {
+			ctx.WithError(err).Warn("error logging off web session")
+		}
+	}()
+
+	src, err := d.web.OpenMedium(srcLocation)
+	if err != nil {
+		return nil, err
+	}
+	defer src.Release()
+
+	med, err := d.web.CreateMedium("vmdk", location)
+	if err != nil {
+		return nil, err
+	}
+	defer med.Release()
+
+	if err := d.web.CloneMedium(src, med); err != nil {
+		return nil, err
+	}
+
+	if err := d.web.PopulateMediumInfo(med); err != nil {
+		return nil, err
+	}
+	return med, nil
+}
+
+// getCustomFields returns the given fields updated with the custom fields
+// of the request.
+func getCustomFields(
+	fields map[string]string, opts types.Store) map[string]string {
+
+	customFields := map[string]string{}
+	for k, v := range fields {
+		customFields[k] = v
+	}
+	if opts != nil {
+		if cf := opts.GetStore("opts"); cf != nil {
+			for _, k := range cf.Keys() {
+				customFields[k] = cf.GetString(k)
+			}
+		}
+	}
+	if len(customFields) == 0 {
+		return nil
+	}
+	return customFields
+}
+
 func (d *driver) attachVolume(
 	ctx types.Context, volumeID, volumeName string) error {
 
@@ -0,0 +1,99 @@
+// +build !libstorage_storage_driver libstorage_storage_driver_vbox
+
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/codedellemc/libstorage/api/types"
+	"github.com/codedellemc/libstorage/api/utils"
+)
+
+// snapshotMetadata is the metadata of a snapshot. A snapshot is a clone of a
+// volume's medium, and its metadata is kept in a JSON file alongside the
+// snapshot's .vmdk file.
+type snapshotMetadata struct {
+	// Location is the location of the snapshot's medium.
+	Location string `json:"location"`
+
+	// Snapshot is the snapshot.
+	Snapshot *types.Snapshot `json:"snapshot"`
+}
+
+// newSnapshotLocation returns the location of a new snapshot's medium.
+func (d *driver) newSnapshotLocation() string {
+	name := strings.Split(types.MustNewUUID().String(), "-")[0]
+	return filepath.Join(d.volumePath(), fmt.Sprintf("snap-%s.vmdk", name))
+}
+
+// getSnapshotMetadataPath returns the path of the metadata file of the
+// snapshot whose medium is at the given location.
+func getSnapshotMetadataPath(location string) string {
+	return strings.TrimSuffix(location, filepath.Ext(location)) + ".json"
+}
+
+func (d *driver) writeSnapshot(md *snapshotMetadata) error {
+	buf, err := json.MarshalIndent(md, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(getSnapshotMetadataPath(md.Location), buf, 0644)
+}
+
+func (d *driver) removeSnapshot(md *snapshotMetadata) error {
+	err := os.Remove(getSnapshotMetadataPath(md.Location))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func readSnapshot(path string) (*snapshotMetadata, error) {
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	md := &snapshotMetadata{}
+	if err := json.Unmarshal(buf, md); err != nil {
+		return nil, err
+	}
+	if md.Snapshot == nil {
+		return nil, fmt.Errorf("invalid snapshot metadata: %s", path)
+	}
+	return md, nil
+}
+
+// getSnapshots returns the metadata of the snapshots in the volume path.
+func (d *driver) getSnapshots() ([]*snapshotMetadata, error) {
+	paths, err := filepath.Glob(filepath.Join(d.volumePath(), "snap-*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var snaps []*snapshotMetadata
+	for _, p := range paths {
+		md, err := readSnapshot(p)
+		if err != nil {
+			return nil, err
+		}
+		snaps = append(snaps, md)
+	}
+	return snaps, nil
+}
+
+func (d *driver) getSnapshotByID(snapshotID string) (*snapshotMetadata, error) {
+	snaps, err := d.getSnapshots()
+	if err != nil {
+		return nil, err
+	}
+	for _, md := range snaps {
+		if md.Snapshot.ID == snapshotID {
+			return md, nil
+		}
+	}
+	return nil, utils.NewNotFoundError(snapshotID)
+}
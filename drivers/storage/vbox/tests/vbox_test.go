@@ -321,3 +321,91 @@ func TestVolumeAttach(t *testing.T) {
 	}
 	apitests.Run(t, vbox.Name, nil, tf)
 }
+
+func volumeSnapshot(
+	t *testing.T,
+	client types.Client,
+	volumeID, snapshotName string) *types.Snapshot {
+
+	log.WithField("volumeID", volumeID).Info("snapshotting volume")
+	reply, err := client.API().VolumeSnapshot(
+		nil, vbox.Name, volumeID,
+		&types.VolumeSnapshotRequest{SnapshotName: snapshotName})
+	assert.NoError(t, err)
+	if err != nil {
+		t.Error("failed volumeSnapshot")
+		t.FailNow()
+	}
+	apitests.LogAsJSON(reply, t)
+	assert.Equal(t, snapshotName, reply.Name)
+	assert.Equal(t, volumeID, reply.VolumeID)
+	return reply
+}
+
+func snapshotRemove(t *testing.T, client types.Client, snapshotID string) {
+	log.WithField("snapshotID", snapshotID).Info("removing snapshot")
+	err := client.API().SnapshotRemove(nil, vbox.Name, snapshotID)
+	assert.NoError(t, err)
+	if err != nil {
+		t.Error("failed snapshotRemove")
+		t.FailNow()
+	}
+}
+
+func TestVolumeSnapshot(t *testing.T) {
+	if skipTests() {
+		t.SkipNow()
+	}
+
+	tf := func(config gofig.Config, client types.Client, t *testing.T) {
+		vol := volumeCreate(t, client, volumeName)
+		snap := volumeSnapshot(t, client, vol.ID, volumeName)
+
+		reply, err := client.API().SnapshotInspect(nil, vbox.Name, snap.ID)
+		assert.NoError(t, err)
+		if err == nil {
+			assert.Equal(t, vol.ID, reply.VolumeID)
+		}
+
+		// the snapshot's medium is not a volume
+		vols, err := client.API().VolumesByService(nil, vbox.Name, 0)
+		assert.NoError(t, err)
+		assert.NotContains(t, vols, snap.ID)
+
+		fromSnap, err := client.API().VolumeCreateFromSnapshot(
+			nil, vbox.Name, snap.ID,
+			&types.VolumeCreateRequest{Name: volumeName2})
+		assert.NoError(t, err)
+		if err == nil {
+			assert.Equal(t, volumeName2, fromSnap.Name)
+			volumeRemove(t, client, fromSnap.ID)
+		}
+
+		snapshotRemove(t, client, snap.ID)
+		volumeRemove(t, client, vol.ID)
+	}
+	apitests.Run(t, vbox.Name, nil, tf)
+}
+
+func TestVolumeCopy(t *testing.T) {
+	if skipTests() {
+		t.SkipNow()
+	}
+
+	tf := func(config gofig.Config, client types.Client, t *testing.T) {
+		vol := volumeCreate(t, client, volumeName)
+
+		reply, err := client.API().VolumeCopy(
+			nil, vbox.Name, vol.ID,
+			&types.VolumeCopyRequest{VolumeName: volumeName2})
+		assert.NoError(t, err)
+		if err == nil {
+			assert.Equal(t, volumeName2, reply.Name)
+			assert.Equal(t, vol.Size, reply.Size)
+			volumeRemove(t, client, reply.ID)
+		}
+
+		volumeRemove(t, client, vol.ID)
+	}
+	apitests.Run(t, vbox.Name, nil, tf)
+}
@@ -5,6 +5,8 @@ package vbox
 import (
 	gofigCore "github.com/akutz/gofig"
 	gofig "github.com/akutz/gofig/types"
+
+	"github.com/codedellemc/libstorage/api/registry"
 )
 
 const (
@@ -25,4 +27,6 @@ func init() {
 	r.Key(gofig.String,
 		"", "/sys/class/scsi_host/", "", "virtualbox.scsiHostPath")
 	gofigCore.Register(r)
+
+	registry.RegisterSecretConfigKeys("virtualbox.password")
 }
@@ -377,6 +377,67 @@ func (d *driver) VolumeRemove(
 	return nil
 }
 
+func (d *driver) VolumeResize(
+	ctx types.Context,
+	volumeID string,
+	opts *types.VolumeResizeOpts) (*types.Volume, error) {
+
+	context.MustSession(ctx)
+
+	vol, err := d.getVolumeByID(volumeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Size < vol.Size {
+		return nil, goof.WithFields(goof.Fields{
+			"volumeID": volumeID,
+			"size":     vol.Size,
+			"newSize":  opts.Size,
+		}, "cannot shrink volume")
+	}
+
+	vol.Size = opts.Size
+	if err := d.writeVolume(vol); err != nil {
+		return nil, err
+	}
+
+	return vol, nil
+}
+
+func (d *driver) VolumeUpdate(
+	ctx types.Context,
+	volumeID string,
+	opts *types.VolumeUpdateOpts) (*types.Volume, error) {
+
+	context.MustSession(ctx)
+
+	vol, err := d.getVolumeByID(volumeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Name != nil {
+		vol.Name = *opts.Name
+	}
+
+	if len(opts.Fields) > 0 && vol.Fields == nil {
+		vol.Fields = map[string]string{}
+	}
+	for k, v := range opts.Fields {
+		vol.Fields[k] = v
+	}
+	for _, k := range opts.RemoveFields {
+		delete(vol.Fields, k)
+	}
+
+	if err := d.writeVolume(vol); err != nil {
+		return nil, err
+	}
+
+	return vol, nil
+}
+
 func (d *driver) VolumeAttach(
 	ctx types.Context,
 	volumeID string,
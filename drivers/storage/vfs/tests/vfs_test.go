@@ -8,6 +8,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path"
 	"strconv"
@@ -20,13 +23,23 @@ import (
 	gofig "github.com/akutz/gofig/types"
 	"github.com/akutz/goof"
 	"github.com/akutz/gotil"
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	jwt "github.com/dgrijalva/jwt-go"
 	"github.com/stretchr/testify/assert"
+	gocontext "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
+	apiclient "github.com/codedellemc/libstorage/api/client"
 	"github.com/codedellemc/libstorage/api/context"
+	apicsi "github.com/codedellemc/libstorage/api/csi"
+	"github.com/codedellemc/libstorage/api/registry"
 	"github.com/codedellemc/libstorage/api/server"
 	apitests "github.com/codedellemc/libstorage/api/tests"
 	"github.com/codedellemc/libstorage/api/types"
 	"github.com/codedellemc/libstorage/api/utils"
+	"github.com/codedellemc/libstorage/drivers/integration/docker/plugin"
 
 	// load the vfs driver packages
 
@@ -78,6 +91,56 @@ func TestServicesWithControllerClient(t *testing.T) {
 		t, types.ControllerClient, vfs.Name, newTestConfig(t), testServicesFunc)
 }
 
+const reloadServicesConfigYAML = `
+libstorage:
+  server:
+    services:
+      vfs:
+        libstorage:
+          storage:
+            driver: vfs
+      vfs2:
+        libstorage:
+          storage:
+            driver: vfs
+`
+
+func TestServicesReload(t *testing.T) {
+	tc := newTestConfig(t)
+
+	loadConfig := server.LoadConfig
+	defer func() { server.LoadConfig = loadConfig }()
+	server.LoadConfig = func() (gofig.Config, error) {
+		config := registry.NewConfig()
+		if err := config.ReadConfig(bytes.NewReader(tc)); err != nil {
+			return nil, err
+		}
+		err := config.ReadConfig(
+			bytes.NewReader([]byte(reloadServicesConfigYAML)))
+		if err != nil {
+			return nil, err
+		}
+		return config, nil
+	}
+
+	tf := func(config gofig.Config, client types.Client, t *testing.T) {
+		reply, err := client.API().ServicesReload(nil)
+		assert.NoError(t, err)
+		assert.Len(t, reply, 2)
+		assert.Contains(t, reply, vfs.Name)
+		assert.Contains(t, reply, "vfs2")
+
+		reply, err = client.API().Services(nil)
+		assert.NoError(t, err)
+		assert.Len(t, reply, 2)
+
+		vols, err := client.API().VolumesByService(nil, "vfs2", 0)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, vols)
+	}
+	apitests.Run(t, vfs.Name, tc, tf)
+}
+
 func TestServiceInpspect(t *testing.T) {
 	tf := func(config gofig.Config, client types.Client, t *testing.T) {
 
@@ -148,6 +211,117 @@ func TestVolumes(t *testing.T) {
 	apitests.RunWithClientType(t, types.ControllerClient, vfs.Name, tc, tf)
 }
 
+func TestVolumesWithFilter(t *testing.T) {
+	tf := func(config gofig.Config, client types.Client, t *testing.T) {
+		ctx := apiclient.WithFilter(
+			context.Background(), "(name=Volume 001)")
+		reply, err := client.API().Volumes(ctx, types.VolAttNone)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Len(t, reply["vfs"], 1)
+		assert.NotNil(t, reply["vfs"]["vfs-001"])
+	}
+	apitests.Run(t, vfs.Name, newTestConfig(t), tf)
+}
+
+func TestVolumesWithPage(t *testing.T) {
+	tf := func(config gofig.Config, client types.Client, t *testing.T) {
+		page := &types.Page{Limit: 2, Sort: "-id"}
+		ctx := apiclient.WithPage(context.Background(), page)
+
+		reply, err := client.API().Volumes(ctx, types.VolAttNone)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Len(t, reply["vfs"], 2)
+		assert.NotNil(t, reply["vfs"]["vfs-002"])
+		assert.NotNil(t, reply["vfs"]["vfs-001"])
+		assert.NotEmpty(t, page.Next)
+
+		page.Token = page.Next
+		reply, err = client.API().Volumes(ctx, types.VolAttNone)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Len(t, reply["vfs"], 1)
+		assert.NotNil(t, reply["vfs"]["vfs-000"])
+		assert.Empty(t, page.Next)
+	}
+	apitests.Run(t, vfs.Name, newTestConfig(t), tf)
+}
+
+func TestVolumesWithPageBadSort(t *testing.T) {
+	tf := func(config gofig.Config, client types.Client, t *testing.T) {
+		ctx := apiclient.WithPage(
+			context.Background(), &types.Page{Sort: "volumeID"})
+		_, err := client.API().VolumesByService(ctx, "vfs", types.VolAttNone)
+		if assert.Error(t, err) {
+			httpErr := err.(goof.HTTPError)
+			assert.Equal(t, 400, httpErr.Status())
+		}
+	}
+	apitests.Run(t, vfs.Name, newTestConfig(t), tf)
+}
+
+const serviceErrorConfigYAML = `
+libstorage:
+  server:
+    services:
+      vfs:
+        libstorage:
+          storage:
+            driver: vfs
+      errors:
+        libstorage:
+          storage:
+            driver: vfs
+        vfs:
+          root: %s
+`
+
+func TestVolumesWithServiceError(t *testing.T) {
+	tc := newTestConfig(t)
+
+	// the second service fails to list its volumes because one of them
+	// cannot be read, and its name is "errors" so that the service's errors
+	// cannot be confused with its volumes
+	d, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	func() {
+		testDirsLock.Lock()
+		defer testDirsLock.Unlock()
+		testDirs = append(testDirs, d)
+	}()
+	vd := path.Join(d, "vol")
+	if err := os.MkdirAll(vd, 0755); err != nil {
+		t.Fatal(err)
+	}
+	err = ioutil.WriteFile(path.Join(vd, "vfs-000.json"), []byte("{"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tc = append(tc, []byte(fmt.Sprintf(serviceErrorConfigYAML, d))...)
+
+	tf := func(config gofig.Config, client types.Client, t *testing.T) {
+		reply, err := client.API().Volumes(nil, types.VolAttNone)
+		partial, ok := err.(*types.ErrPartialResult)
+		if !assert.True(t, ok, "%v", err) {
+			t.FailNow()
+		}
+		assert.Len(t, reply, 1)
+		assert.Len(t, reply[vfs.Name], 3)
+		assert.Len(t, partial.Errors, 1)
+		if assert.NotNil(t, partial.Errors["errors"]) {
+			assert.NotEmpty(t, partial.Errors["errors"].Message)
+			assert.False(t, partial.Errors["errors"].TimedOut)
+		}
+	}
+	apitests.Run(t, vfs.Name, tc, tf)
+}
+
 func TestVolumesWithAttachmentsTrue(t *testing.T) {
 	tc, _, vols, _ := newTestConfigAll(t)
 	tf := func(config gofig.Config, client types.Client, t *testing.T) {
@@ -452,6 +626,34 @@ func TestSnapshots(t *testing.T) {
 	apitests.Run(t, vfs.Name, tc, tf)
 }
 
+func TestSnapshotsByServiceWithPage(t *testing.T) {
+	tc, _, _, snaps := newTestConfigAll(t)
+	tf := func(config gofig.Config, client types.Client, t *testing.T) {
+		page := &types.Page{Limit: 4, Sort: "name"}
+		ctx := apiclient.WithPage(context.Background(), page)
+
+		listed := map[string]bool{}
+		for pages := 1; ; pages++ {
+			reply, err := client.API().SnapshotsByService(ctx, "vfs")
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.True(t, len(reply) <= page.Limit)
+			for snapshotID := range reply {
+				assert.False(t, listed[snapshotID])
+				listed[snapshotID] = true
+			}
+			if page.Next == "" {
+				assert.Equal(t, 3, pages)
+				break
+			}
+			page.Token = page.Next
+		}
+		assert.Len(t, listed, len(snaps))
+	}
+	apitests.Run(t, vfs.Name, tc, tf)
+}
+
 func TestSnapshotsByService(t *testing.T) {
 	tc, _, _, snaps := newTestConfigAll(t)
 	tf := func(config gofig.Config, client types.Client, t *testing.T) {
@@ -467,6 +669,52 @@ func TestSnapshotsByService(t *testing.T) {
 	apitests.Run(t, vfs.Name, tc, tf)
 }
 
+func TestEvents(t *testing.T) {
+	tf := func(config gofig.Config, client types.Client, t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		events, err := client.API().Events(ctx, vfs.Name)
+		assert.NoError(t, err)
+		if err != nil {
+			t.FailNow()
+		}
+
+		err = client.API().VolumeRemove(nil, vfs.Name, "vfs-002")
+		assert.NoError(t, err)
+
+		var (
+			taskEvent *types.Event
+			volEvent  *types.Event
+			timeout   = time.After(10 * time.Second)
+		)
+
+		for volEvent == nil || taskEvent == nil {
+			select {
+			case ev, ok := <-events:
+				if !ok {
+					t.Fatal("event stream closed")
+				}
+				switch ev.Type {
+				case types.EventTypeTask:
+					taskEvent = ev
+				case types.EventTypeVolumeRemoved:
+					volEvent = ev
+				}
+			case <-timeout:
+				t.Fatal("timed out waiting for events")
+			}
+		}
+
+		assert.Equal(t, vfs.Name, volEvent.Service)
+		assert.Equal(t, "vfs-002", volEvent.VolumeID)
+		assert.Nil(t, volEvent.Volume)
+		assert.NotNil(t, taskEvent.Task)
+		assert.Equal(t, vfs.Name, taskEvent.Service)
+	}
+	apitests.Run(t, vfs.Name, newTestConfig(t), tf)
+}
+
 func TestVolumeCreate(t *testing.T) {
 	tf := func(config gofig.Config, client types.Client, t *testing.T) {
 		volumeName := "Volume 003"
@@ -509,6 +757,429 @@ func TestVolumeCreate(t *testing.T) {
 	apitests.Run(t, vfs.Name, newTestConfig(t), tf)
 }
 
+const policyConfigYAML = `
+libstorage:
+  policy:
+    maxVolumes: 4
+    maxCapacity: 40960
+    types: myType
+    maxIOPS: 2000
+    requiredFields: owner
+`
+
+func newTestConfigWithPolicy(t *testing.T) []byte {
+	return append(newTestConfig(t), []byte(policyConfigYAML)...)
+}
+
+func TestVolumeCreateAsync(t *testing.T) {
+	tf := func(config gofig.Config, client types.Client, t *testing.T) {
+		size := int64(10240)
+		request := &types.VolumeCreateRequest{
+			Name: "Volume 004",
+			Size: &size,
+		}
+
+		ctx, task := apiclient.WithAsync(context.Background())
+		reply, err := client.API().VolumeCreate(ctx, vfs.Name, request)
+		assert.NoError(t, err)
+		if err != nil {
+			t.FailNow()
+		}
+		assert.Empty(t, reply.ID)
+		assert.Equal(t, vfs.Name, task.Service)
+		assert.NotEmpty(t, task.State)
+
+		timeout := time.After(10 * time.Second)
+		for task.State != types.TaskStateSuccess {
+			assert.NotEqual(t, types.TaskStateError, string(task.State))
+			select {
+			case <-timeout:
+				t.Fatal("timed out waiting for task")
+			case <-time.After(100 * time.Millisecond):
+			}
+			if task, err = client.API().TaskInspect(nil, task.ID); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		vol, ok := task.Result.(map[string]interface{})
+		assert.True(t, ok)
+		assert.Equal(t, "Volume 004", vol["name"])
+
+		// the client driver's hooks do not run for an asynchronous request,
+		// so the volume is inspected rather than its directory
+		_, err = client.API().VolumeInspect(
+			nil, vfs.Name, vol["id"].(string), types.VolAttNone)
+		assert.NoError(t, err)
+	}
+	apitests.Run(t, vfs.Name, newTestConfig(t), tf)
+}
+
+func TestVolumeCreatePolicy(t *testing.T) {
+	newRequest := func() *types.VolumeCreateRequest {
+		iops := int64(1000)
+		size := int64(10240)
+		volType := "myType"
+		return &types.VolumeCreateRequest{
+			Name:   "Volume 003",
+			IOPS:   &iops,
+			Size:   &size,
+			Type:   &volType,
+			Fields: map[string]string{"owner": "root@example.com"},
+		}
+	}
+
+	assertPolicyViolation := func(t *testing.T, err error, msg string) {
+		if !assert.Error(t, err) {
+			t.FailNow()
+		}
+		httpErr := err.(goof.HTTPError)
+		assert.Equal(t, 403, httpErr.Status())
+		assert.Equal(t, "policy violation: "+msg, httpErr.Error())
+	}
+
+	tf := func(config gofig.Config, client types.Client, t *testing.T) {
+		request := newRequest()
+		volType := "otherType"
+		request.Type = &volType
+		_, err := client.API().VolumeCreate(nil, vfs.Name, request)
+		assertPolicyViolation(t, err, "volume type not allowed")
+
+		request = newRequest()
+		iops := int64(3000)
+		request.IOPS = &iops
+		_, err = client.API().VolumeCreate(nil, vfs.Name, request)
+		assertPolicyViolation(t, err, "maximum iops exceeded")
+
+		request = newRequest()
+		request.Fields = nil
+		_, err = client.API().VolumeCreate(nil, vfs.Name, request)
+		assertPolicyViolation(t, err, "missing required field")
+
+		// a required field in the opts is not set on the volume
+		request = newRequest()
+		request.Fields = nil
+		request.Opts = map[string]interface{}{"owner": "root@example.com"}
+		_, err = client.API().VolumeCreate(nil, vfs.Name, request)
+		assertPolicyViolation(t, err, "missing required field")
+
+		request = newRequest()
+		size := int64(20480)
+		request.Size = &size
+		_, err = client.API().VolumeCreate(nil, vfs.Name, request)
+		assertPolicyViolation(t, err, "maximum capacity exceeded")
+
+		vol, err := client.API().VolumeCreate(nil, vfs.Name, newRequest())
+		assert.NoError(t, err)
+		if assert.NotNil(t, vol) {
+			assert.Equal(t, "root@example.com", vol.Fields["owner"])
+		}
+
+		_, err = client.API().VolumeCreate(nil, vfs.Name, newRequest())
+		assertPolicyViolation(t, err, "maximum volume count exceeded")
+
+		_, err = client.API().VolumeUpdate(
+			nil, vfs.Name, "vfs-000", &types.VolumeUpdateRequest{
+				RemoveFields: []string{"owner"},
+			})
+		assertPolicyViolation(t, err, "cannot remove required field")
+
+		_, err = client.API().VolumeUpdate(
+			nil, vfs.Name, "vfs-000", &types.VolumeUpdateRequest{
+				Fields: map[string]string{"owner": ""},
+			})
+		assertPolicyViolation(t, err, "cannot remove required field")
+	}
+
+	apitests.Run(t, vfs.Name, newTestConfigWithPolicy(t), tf)
+}
+
+const authConfigYAML = `
+libstorage:
+  client:
+    auth:
+      token: %s
+  server:
+    tasks:
+      logTimeout: 1m
+    auth:
+      enabled: true
+      tokens:
+        reader:
+          token: readtoken
+          grants:
+            "*": read
+        attacher:
+          token: attachtoken
+          grants:
+            "*": attach
+        admin:
+          token: admintoken
+          grants:
+            vfs: admin
+      jwt:
+        key: jwtsecret
+`
+
+func newTestConfigWithAuth(t *testing.T, token string) []byte {
+	return append(
+		newTestConfig(t), []byte(fmt.Sprintf(authConfigYAML, token))...)
+}
+
+func TestAuthStaticToken(t *testing.T) {
+	tf := func(config gofig.Config, client types.Client, t *testing.T) {
+		_, err := client.API().Volumes(nil, types.VolAttNone)
+		assert.NoError(t, err)
+
+		size := int64(1)
+		_, err = client.API().VolumeCreate(
+			nil, vfs.Name, &types.VolumeCreateRequest{
+				Name: "Volume 003",
+				Size: &size,
+			})
+		if assert.Error(t, err) {
+			httpErr := err.(goof.HTTPError)
+			assert.Equal(t, 403, httpErr.Status())
+		}
+
+		client.API().SetAuthToken("invalid")
+		_, err = client.API().Volumes(nil, types.VolAttNone)
+		if assert.Error(t, err) {
+			httpErr := err.(goof.HTTPError)
+			assert.Equal(t, 401, httpErr.Status())
+		}
+	}
+
+	apitests.Run(t, vfs.Name, newTestConfigWithAuth(t, "readtoken"), tf)
+}
+
+func TestAuthJWT(t *testing.T) {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":    "akutz",
+		"grants": map[string]string{vfs.Name: "admin"},
+	}).SignedString([]byte("jwtsecret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tf := func(config gofig.Config, client types.Client, t *testing.T) {
+		size := int64(1)
+		_, err := client.API().VolumeCreate(
+			nil, vfs.Name, &types.VolumeCreateRequest{
+				Name: "Volume 003",
+				Size: &size,
+			})
+		assert.NoError(t, err)
+
+		tasks, err := client.API().Tasks(nil, &types.TasksOpts{User: "akutz"})
+		assert.NoError(t, err)
+		assert.NotEmpty(t, tasks)
+	}
+
+	apitests.Run(t, vfs.Name, newTestConfigWithAuth(t, token), tf)
+}
+
+func TestAuthServiceGrant(t *testing.T) {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":    "akutz",
+		"grants": map[string]string{vfs.Name: "admin"},
+	}).SignedString([]byte("jwtsecret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertForbidden := func(t *testing.T, err error) {
+		if assert.Error(t, err) {
+			httpErr := err.(goof.HTTPError)
+			assert.Equal(t, 403, httpErr.Status())
+		}
+	}
+
+	tf := func(config gofig.Config, client types.Client, t *testing.T) {
+		// the listings of all services include the granted service
+		reply, err := client.API().Volumes(nil, types.VolAttNone)
+		assert.NoError(t, err)
+		assert.Contains(t, reply, vfs.Name)
+
+		// the operations on all services require a grant for all services
+		_, err = client.API().VolumeDetachAll(
+			nil, &types.VolumeDetachRequest{})
+		assertForbidden(t, err)
+
+		_, err = client.API().ServicesReload(nil)
+		assertForbidden(t, err)
+
+		_, err = client.API().VolumeDetachAllForService(
+			nil, vfs.Name, &types.VolumeDetachRequest{})
+		assert.NoError(t, err)
+	}
+
+	apitests.Run(t, vfs.Name, newTestConfigWithAuth(t, token), tf)
+}
+
+func TestAuthAttachRole(t *testing.T) {
+	tc, _, _, _ := newTestConfigAll(t)
+	tc = append(tc, []byte(fmt.Sprintf(authConfigYAML, "attachtoken"))...)
+
+	tf := func(config gofig.Config, client types.Client, t *testing.T) {
+		reply, err := client.API().VolumeDetachAll(
+			nil, &types.VolumeDetachRequest{})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, len(reply[vfs.Name]))
+
+		_, err = client.API().VolumeDetachAllForService(
+			nil, vfs.Name, &types.VolumeDetachRequest{})
+		assert.NoError(t, err)
+	}
+
+	apitests.Run(t, vfs.Name, tc, tf)
+}
+
+// auditServerConfigYAML is indented to extend the server section that ends
+// authConfigYAML, as a second libstorage key would replace the first.
+const auditServerConfigYAML = `    audit:
+      enabled: true
+      file:
+        path: %s
+`
+
+const auditConfigYAML = `
+libstorage:
+  server:
+` + auditServerConfigYAML
+
+func TestAudit(t *testing.T) {
+	d, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(d)
+	auditPath := path.Join(d, "audit.log")
+
+	tc := append(
+		newTestConfig(t),
+		[]byte(fmt.Sprintf(auditConfigYAML, auditPath))...)
+
+	tf := func(config gofig.Config, client types.Client, t *testing.T) {
+		size := int64(1)
+		_, err := client.API().VolumeCreate(
+			nil, vfs.Name, &types.VolumeCreateRequest{
+				Name: "Volume 003",
+				Size: &size,
+			})
+		assert.NoError(t, err)
+
+		err = client.API().VolumeRemove(nil, vfs.Name, "vfs-999")
+		assert.Error(t, err)
+
+		records, err := client.API().Audit(
+			nil, &types.AuditOpts{Service: vfs.Name})
+		assert.NoError(t, err)
+		if !assert.Len(t, records, 2) {
+			t.FailNow()
+		}
+
+		assert.Equal(t, "volumeCreate", records[0].Operation)
+		assert.Equal(t, "Volume 003", records[0].Name)
+		assert.Equal(t, 201, records[0].Status)
+		assert.NotEmpty(t, records[0].TransactionID)
+
+		assert.Equal(t, "volumeRemove", records[1].Operation)
+		assert.Equal(t, "vfs-999", records[1].VolumeID)
+		assert.Equal(t, 404, records[1].Status)
+		assert.Equal(t, "resource not found", records[1].Error)
+
+		records, err = client.API().Audit(
+			nil, &types.AuditOpts{Service: vfs.Name, Limit: 1})
+		assert.NoError(t, err)
+		assert.Len(t, records, 1)
+
+		// the record of an asynchronous request is written once its task is
+		// complete
+		ctx, task := apiclient.WithAsync(context.Background())
+		_, err = client.API().VolumeCreate(
+			ctx, vfs.Name, &types.VolumeCreateRequest{
+				Name: "Volume 004",
+				Size: &size,
+			})
+		assert.NoError(t, err)
+		timeout := time.After(10 * time.Second)
+		for len(records) != 3 {
+			select {
+			case <-timeout:
+				t.Fatal("timed out waiting for audit record")
+			case <-time.After(100 * time.Millisecond):
+			}
+			if records, err = client.API().Audit(
+				nil, &types.AuditOpts{Service: vfs.Name}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		assert.Equal(t, "volumeCreate", records[2].Operation)
+		assert.Equal(t, "Volume 004", records[2].Name)
+		assert.Equal(t, 201, records[2].Status)
+		assert.Equal(t, fmt.Sprintf("%d", task.ID), records[2].TaskID)
+
+		// the file is shared by the servers of each of the test configs, so
+		// only the most recent record is verified
+		buf, err := ioutil.ReadFile(auditPath)
+		assert.NoError(t, err)
+		lines := strings.Split(strings.TrimSpace(string(buf)), "\n")
+		rec := &types.AuditRecord{}
+		assert.NoError(t, json.Unmarshal([]byte(lines[len(lines)-1]), rec))
+		assert.Equal(t, "volumeCreate", rec.Operation)
+		assert.Equal(t, "Volume 004", rec.Name)
+	}
+
+	apitests.Run(t, vfs.Name, tc, tf)
+}
+
+func TestAuditRejected(t *testing.T) {
+	d, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(d)
+	auditPath := path.Join(d, "audit.log")
+
+	tc := append(
+		newTestConfigWithAuth(t, "readtoken"),
+		[]byte(fmt.Sprintf(auditServerConfigYAML, auditPath))...)
+
+	tf := func(config gofig.Config, client types.Client, t *testing.T) {
+		lastRecord := func() *types.AuditRecord {
+			buf, err := ioutil.ReadFile(auditPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			lines := strings.Split(strings.TrimSpace(string(buf)), "\n")
+			rec := &types.AuditRecord{}
+			err = json.Unmarshal([]byte(lines[len(lines)-1]), rec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return rec
+		}
+
+		err := client.API().VolumeRemove(nil, vfs.Name, "vfs-000")
+		assert.Error(t, err)
+		rec := lastRecord()
+		assert.Equal(t, "volumeRemove", rec.Operation)
+		assert.Equal(t, "reader", rec.User)
+		assert.Equal(t, 403, rec.Status)
+
+		client.API().SetAuthToken("invalid")
+		err = client.API().VolumeRemove(nil, vfs.Name, "vfs-000")
+		assert.Error(t, err)
+		rec = lastRecord()
+		assert.Equal(t, "volumeRemove", rec.Operation)
+		assert.Empty(t, rec.User)
+		assert.Equal(t, 401, rec.Status)
+	}
+
+	apitests.Run(t, vfs.Name, tc, tf)
+}
+
 func TestVolumeCopy(t *testing.T) {
 	tf := func(config gofig.Config, client types.Client, t *testing.T) {
 		request := &types.VolumeCopyRequest{
@@ -537,6 +1208,89 @@ func TestVolumeCopy(t *testing.T) {
 	apitests.Run(t, vfs.Name, newTestConfig(t), tf)
 }
 
+func TestVolumeResize(t *testing.T) {
+	tf := func(config gofig.Config, client types.Client, t *testing.T) {
+		request := &types.VolumeResizeRequest{Size: 20480}
+
+		reply, err := client.API().VolumeResize(
+			nil, vfs.Name, "vfs-002", request)
+		assert.NoError(t, err)
+		if err != nil {
+			t.FailNow()
+		}
+
+		assert.NotNil(t, reply)
+		assert.Equal(t, "vfs-002", reply.ID)
+		assert.Equal(t, request.Size, reply.Size)
+
+		vol, err := client.API().VolumeInspect(nil, vfs.Name, "vfs-002", 0)
+		assert.NoError(t, err)
+		assert.Equal(t, request.Size, vol.Size)
+	}
+
+	apitests.Run(t, vfs.Name, newTestConfig(t), tf)
+}
+
+func TestVolumeResizeShrink(t *testing.T) {
+	tf := func(config gofig.Config, client types.Client, t *testing.T) {
+		request := &types.VolumeResizeRequest{Size: 1024}
+
+		_, err := client.API().VolumeResize(
+			nil, vfs.Name, "vfs-002", request)
+		assert.Error(t, err)
+		assert.Equal(t, "cannot shrink volume", err.Error())
+	}
+
+	apitests.Run(t, vfs.Name, newTestConfig(t), tf)
+}
+
+func TestVolumeUpdate(t *testing.T) {
+	tf := func(config gofig.Config, client types.Client, t *testing.T) {
+		name := "newName"
+		request := &types.VolumeUpdateRequest{
+			Name:         &name,
+			Fields:       map[string]string{"owner": "root@example.com"},
+			RemoveFields: []string{"priority"},
+		}
+
+		reply, err := client.API().VolumeUpdate(
+			nil, vfs.Name, "vfs-002", request)
+		assert.NoError(t, err)
+		if err != nil {
+			t.FailNow()
+		}
+
+		assert.NotNil(t, reply)
+		assert.Equal(t, "vfs-002", reply.ID)
+		assert.Equal(t, name, reply.Name)
+		assert.Equal(t, "root@example.com", reply.Fields["owner"])
+		_, ok := reply.Fields["priority"]
+		assert.False(t, ok)
+
+		vol, err := client.API().VolumeInspect(nil, vfs.Name, "vfs-002", 0)
+		assert.NoError(t, err)
+		assert.Equal(t, name, vol.Name)
+		assert.Equal(t, "root@example.com", vol.Fields["owner"])
+	}
+
+	apitests.Run(t, vfs.Name, newTestConfig(t), tf)
+}
+
+func TestVolumeUpdateNotFound(t *testing.T) {
+	tf := func(config gofig.Config, client types.Client, t *testing.T) {
+		name := "newName"
+		request := &types.VolumeUpdateRequest{Name: &name}
+
+		_, err := client.API().VolumeUpdate(
+			nil, vfs.Name, "vfs-999", request)
+		assert.Error(t, err)
+		httpErr := err.(goof.HTTPError)
+		assert.Equal(t, 404, httpErr.Status())
+	}
+
+	apitests.Run(t, vfs.Name, newTestConfig(t), tf)
+}
+
 func TestVolumeRemove(t *testing.T) {
 
 	tf1 := func(config gofig.Config, client types.Client, t *testing.T) {
@@ -696,6 +1450,71 @@ func TestVolumeAttachWithControllerClient(t *testing.T) {
 		t, types.ControllerClient, vfs.Name, newTestConfig(t), tf)
 }
 
+func TestVolumeAttachTargetInstance(t *testing.T) {
+	tf := func(config gofig.Config, client types.Client, t *testing.T) {
+		target := &types.InstanceID{ID: "remote-host", Driver: vfs.Name}
+
+		reply, _, err := client.API().VolumeAttach(
+			nil, vfs.Name, "vfs-002",
+			&types.VolumeAttachRequest{InstanceID: target})
+		assert.NoError(t, err)
+		if reply == nil {
+			t.FailNow()
+		}
+		assert.Equal(t, "vfs-002", reply.ID)
+		assert.Equal(t, "remote-host", reply.Attachments[0].InstanceID.ID)
+
+		reply, err = client.API().VolumeDetach(
+			nil, vfs.Name, "vfs-002",
+			&types.VolumeDetachRequest{InstanceID: target})
+		assert.NoError(t, err)
+		if reply == nil {
+			t.FailNow()
+		}
+		assert.Equal(t, 0, len(reply.Attachments))
+
+		_, _, err = client.API().VolumeAttach(
+			nil, vfs.Name, "vfs-002",
+			&types.VolumeAttachRequest{InstanceID: &types.InstanceID{
+				ID:     "remote-host",
+				Driver: "ebs",
+			}})
+		if assert.Error(t, err) {
+			httpErr := err.(goof.HTTPError)
+			assert.Equal(t, 400, httpErr.Status())
+		}
+	}
+	apitests.Run(t, vfs.Name, newTestConfigWithAuth(t, "admintoken"), tf)
+	apitests.RunWithClientType(
+		t, types.ControllerClient, vfs.Name,
+		newTestConfigWithAuth(t, "admintoken"), tf)
+}
+
+func TestVolumeAttachTargetInstanceForbidden(t *testing.T) {
+	tf := func(config gofig.Config, client types.Client, t *testing.T) {
+		_, _, err := client.API().VolumeAttach(
+			nil, vfs.Name, "vfs-002",
+			&types.VolumeAttachRequest{InstanceID: &types.InstanceID{
+				ID:     "remote-host",
+				Driver: vfs.Name,
+			}})
+		if assert.Error(t, err) {
+			httpErr := err.(goof.HTTPError)
+			assert.Equal(t, 403, httpErr.Status())
+		}
+
+		reply, _, err := client.API().VolumeAttach(
+			nil, vfs.Name, "vfs-002", &types.VolumeAttachRequest{})
+		assert.NoError(t, err)
+		if reply == nil {
+			t.FailNow()
+		}
+		assert.Equal(t, "vfs-002", reply.ID)
+	}
+	apitests.Run(t, vfs.Name, newTestConfigWithAuth(t, "attachtoken"), tf)
+	apitests.Run(t, vfs.Name, newTestConfig(t), tf)
+}
+
 func TestVolumeDetach(t *testing.T) {
 	tf := func(config gofig.Config, client types.Client, t *testing.T) {
 		request := &types.VolumeDetachRequest{}
@@ -911,6 +1730,422 @@ func TestLocalDevices(t *testing.T) {
 		}).Test)
 }
 
+func TestDockerPlugin(t *testing.T) {
+	tf := func(config gofig.Config, client types.Client, t *testing.T) {
+		p, err := plugin.New(
+			context.Background().WithValue(context.ServiceKey, vfs.Name),
+			client, config)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		s := httptest.NewServer(p)
+		defer s.Close()
+
+		post := func(
+			route string, req interface{}) (int, map[string]interface{}) {
+
+			buf, err := json.Marshal(req)
+			assert.NoError(t, err)
+			res, err := http.Post(
+				s.URL+route, plugin.ContentType, bytes.NewReader(buf))
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			defer res.Body.Close()
+			body := map[string]interface{}{}
+			assert.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+			return res.StatusCode, body
+		}
+
+		status, body := post("/Plugin.Activate", nil)
+		assert.Equal(t, 200, status)
+		assert.Equal(t, []interface{}{"VolumeDriver"}, body["Implements"])
+
+		status, body = post("/VolumeDriver.Capabilities", nil)
+		assert.Equal(t, 200, status)
+		assert.Equal(t,
+			map[string]interface{}{"Scope": "global"}, body["Capabilities"])
+
+		status, body = post("/VolumeDriver.Create", map[string]interface{}{
+			"Name": "Volume 010",
+			"Opts": map[string]string{"size": "1"},
+		})
+		assert.Equal(t, 200, status)
+		assert.Nil(t, body["Err"])
+
+		status, body = post("/VolumeDriver.List", nil)
+		assert.Equal(t, 200, status)
+		var names []string
+		vols, _ := body["Volumes"].([]interface{})
+		for _, v := range vols {
+			vm := v.(map[string]interface{})
+			names = append(names, vm["Name"].(string))
+		}
+		assert.Contains(t, names, "Volume 010")
+
+		status, body = post(
+			"/VolumeDriver.Get", map[string]string{"Name": "Volume 010"})
+		assert.Equal(t, 200, status)
+		vol, _ := body["Volume"].(map[string]interface{})
+		assert.Equal(t, "Volume 010", vol["Name"])
+
+		status, body = post(
+			"/VolumeDriver.Path", map[string]string{"Name": "Volume 010"})
+		assert.Equal(t, 200, status)
+		assert.Nil(t, body["Err"])
+
+		status, body = post("/VolumeDriver.Unmount", map[string]string{
+			"Name": "Volume 010",
+			"ID":   "container-000",
+		})
+		assert.Equal(t, 200, status)
+		assert.Nil(t, body["Err"])
+
+		status, body = post(
+			"/VolumeDriver.Remove", map[string]string{"Name": "Volume 010"})
+		assert.Equal(t, 200, status)
+		assert.Nil(t, body["Err"])
+
+		status, body = post(
+			"/VolumeDriver.Get", map[string]string{"Name": "Volume 010"})
+		assert.Equal(t, 500, status)
+		assert.NotEmpty(t, body["Err"])
+
+		res, err := http.Post(s.URL+"/VolumeDriver.Unknown", "", nil)
+		if assert.NoError(t, err) {
+			res.Body.Close()
+			assert.Equal(t, 404, res.StatusCode)
+		}
+	}
+	apitests.Run(t, vfs.Name, newTestConfigWithMountState(t), tf)
+}
+
+const csiConfigFormat = `
+libstorage:
+  csi:
+    service: vfs
+    controller:
+      endpoint: unix://%s
+    node:
+      endpoint: unix://%s
+`
+
+func TestCSI(t *testing.T) {
+	var (
+		controllerSock = utils.GetTempSockFile()
+		nodeSock       = utils.GetTempSockFile()
+		tc             = append(newTestConfig(t), []byte(fmt.Sprintf(
+			csiConfigFormat, controllerSock, nodeSock))...)
+	)
+
+	dial := func(sock string) *grpc.ClientConn {
+		conn, err := grpc.Dial(
+			sock,
+			grpc.WithInsecure(),
+			grpc.WithDialer(
+				func(addr string, timeout time.Duration) (net.Conn, error) {
+					return net.DialTimeout("unix", addr, timeout)
+				}))
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		return conn
+	}
+
+	assertCode := func(code codes.Code, err error) {
+		if assert.Error(t, err) {
+			assert.Equal(t, code, status.Code(err))
+		}
+	}
+
+	mountCap := &csi.VolumeCapability{
+		AccessType: &csi.VolumeCapability_Mount{
+			Mount: &csi.VolumeCapability_MountVolume{},
+		},
+		AccessMode: &csi.VolumeCapability_AccessMode{
+			Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER,
+		},
+	}
+	blockCap := &csi.VolumeCapability{
+		AccessType: &csi.VolumeCapability_Block{
+			Block: &csi.VolumeCapability_BlockVolume{},
+		},
+		AccessMode: mountCap.AccessMode,
+	}
+
+	tf := func(config gofig.Config, client types.Client, t *testing.T) {
+		ctx := gocontext.Background()
+
+		ns, _, err := apicsi.ServeNode(context.Background(), client, config)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		defer ns.Close()
+
+		cconn := dial(controllerSock)
+		defer cconn.Close()
+		nconn := dial(nodeSock)
+		defer nconn.Close()
+
+		identity := csi.NewIdentityClient(cconn)
+		controller := csi.NewControllerClient(cconn)
+		node := csi.NewNodeClient(nconn)
+
+		info, err := identity.GetPluginInfo(ctx, &csi.GetPluginInfoRequest{})
+		assert.NoError(t, err)
+		assert.Equal(t, "libstorage.codedellemc.com", info.GetName())
+
+		probe, err := identity.Probe(ctx, &csi.ProbeRequest{})
+		assert.NoError(t, err)
+		assert.True(t, probe.GetReady().GetValue())
+
+		// the node id is the node's instance id
+		nodeInfo, err := node.NodeGetInfo(ctx, &csi.NodeGetInfoRequest{})
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		iid := &types.InstanceID{}
+		assert.NoError(t, iid.UnmarshalText([]byte(nodeInfo.NodeId)))
+		expectedIID, err := instanceID()
+		assert.NoError(t, err)
+		assert.Equal(t, expectedIID.ID, iid.ID)
+
+		createReq := &csi.CreateVolumeRequest{
+			Name:               "Volume 020",
+			CapacityRange:      &csi.CapacityRange{RequiredBytes: 1},
+			VolumeCapabilities: []*csi.VolumeCapability{mountCap},
+		}
+		created, err := controller.CreateVolume(ctx, createReq)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		vol := created.GetVolume()
+		assert.Equal(t, int64(1024*1024*1024), vol.CapacityBytes)
+
+		// the CSI controller creates the volume on the server, so the client
+		// driver's hook that creates the volume's directory does not run
+		_, err = client.API().VolumeInspect(
+			nil, vfs.Name, vol.VolumeId, types.VolAttNone)
+		assert.NoError(t, err)
+
+		// creating the volume again returns the same volume
+		created, err = controller.CreateVolume(ctx, createReq)
+		assert.NoError(t, err)
+		assert.Equal(t, vol.VolumeId, created.GetVolume().GetVolumeId())
+
+		_, err = controller.CreateVolume(ctx, &csi.CreateVolumeRequest{
+			Name:               "Volume 021",
+			VolumeCapabilities: []*csi.VolumeCapability{blockCap},
+		})
+		assertCode(codes.InvalidArgument, err)
+
+		vols, err := controller.ListVolumes(
+			ctx, &csi.ListVolumesRequest{MaxEntries: 2})
+		assert.NoError(t, err)
+		assert.Len(t, vols.Entries, 2)
+		assert.Equal(t, "2", vols.NextToken)
+		vols, err = controller.ListVolumes(
+			ctx, &csi.ListVolumesRequest{StartingToken: vols.NextToken})
+		assert.NoError(t, err)
+		assert.Len(t, vols.Entries, 2)
+		assert.Empty(t, vols.NextToken)
+		_, err = controller.ListVolumes(
+			ctx, &csi.ListVolumesRequest{StartingToken: "invalid"})
+		assertCode(codes.Aborted, err)
+
+		publishReq := &csi.ControllerPublishVolumeRequest{
+			VolumeId:         vol.VolumeId,
+			NodeId:           nodeInfo.NodeId,
+			VolumeCapability: mountCap,
+		}
+		_, err = controller.ControllerPublishVolume(ctx, publishReq)
+		assert.NoError(t, err)
+		_, err = controller.ControllerPublishVolume(ctx, publishReq)
+		assert.NoError(t, err)
+
+		v, err := client.API().VolumeInspect(
+			nil, vfs.Name, vol.VolumeId, types.VolAttReq)
+		assert.NoError(t, err)
+		assert.Len(t, v.Attachments, 1)
+
+		unpublishReq := &csi.ControllerUnpublishVolumeRequest{
+			VolumeId: vol.VolumeId,
+			NodeId:   nodeInfo.NodeId,
+		}
+		_, err = controller.ControllerUnpublishVolume(ctx, unpublishReq)
+		assert.NoError(t, err)
+		_, err = controller.ControllerUnpublishVolume(ctx, unpublishReq)
+		assert.NoError(t, err)
+
+		v, err = client.API().VolumeInspect(
+			nil, vfs.Name, vol.VolumeId, types.VolAttReq)
+		assert.NoError(t, err)
+		assert.Empty(t, v.Attachments)
+
+		snap, err := controller.CreateSnapshot(ctx, &csi.CreateSnapshotRequest{
+			Name:           "Snapshot 020",
+			SourceVolumeId: vol.VolumeId,
+		})
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		assert.Equal(t, vol.VolumeId, snap.GetSnapshot().GetSourceVolumeId())
+
+		snaps, err := controller.ListSnapshots(ctx, &csi.ListSnapshotsRequest{
+			SourceVolumeId: vol.VolumeId,
+		})
+		assert.NoError(t, err)
+		assert.Len(t, snaps.Entries, 1)
+
+		_, err = controller.DeleteSnapshot(ctx, &csi.DeleteSnapshotRequest{
+			SnapshotId: snap.GetSnapshot().GetSnapshotId(),
+		})
+		assert.NoError(t, err)
+		snaps, err = controller.ListSnapshots(ctx, &csi.ListSnapshotsRequest{
+			SnapshotId: snap.GetSnapshot().GetSnapshotId(),
+		})
+		assert.NoError(t, err)
+		assert.Empty(t, snaps.Entries)
+
+		deleteReq := &csi.DeleteVolumeRequest{VolumeId: vol.VolumeId}
+		_, err = controller.DeleteVolume(ctx, deleteReq)
+		assert.NoError(t, err)
+		_, err = client.API().VolumeInspect(
+			nil, vfs.Name, vol.VolumeId, types.VolAttNone)
+		assert.Error(t, err)
+		_, err = controller.DeleteVolume(ctx, deleteReq)
+		assert.NoError(t, err)
+
+		caps, err := node.NodeGetCapabilities(
+			ctx, &csi.NodeGetCapabilitiesRequest{})
+		assert.NoError(t, err)
+		assert.Len(t, caps.Capabilities, 1)
+
+		_, err = node.NodeStageVolume(ctx, &csi.NodeStageVolumeRequest{
+			VolumeId:          "vfs-000",
+			StagingTargetPath: "/tmp/stage",
+			VolumeCapability:  blockCap,
+		})
+		assertCode(codes.InvalidArgument, err)
+		_, err = node.NodeStageVolume(ctx, &csi.NodeStageVolumeRequest{
+			VolumeId:          "vfs-000",
+			StagingTargetPath: "/tmp/stage",
+		})
+		assertCode(codes.InvalidArgument, err)
+
+		// unpublishing a volume that is not published succeeds
+		_, err = node.NodeUnpublishVolume(ctx, &csi.NodeUnpublishVolumeRequest{
+			VolumeId:   "vfs-000",
+			TargetPath: path.Join(os.TempDir(), "csi-unpublished"),
+		})
+		assert.NoError(t, err)
+	}
+	apitests.Run(t, vfs.Name, tc, tf)
+}
+
+const csiTCPConfigYAML = `
+libstorage:
+  csi:
+    service: vfs
+    node:
+      endpoint: tcp://127.0.0.1:0
+`
+
+// csiPolicyConfigFormat extends the libstorage section of csiConfigFormat,
+// as a second libstorage key would replace the first.
+const csiPolicyConfigFormat = csiConfigFormat + `  policy:
+    types: myType
+`
+
+func TestCSICreateVolumePolicy(t *testing.T) {
+	controllerSock := utils.GetTempSockFile()
+	tc := append(newTestConfig(t), []byte(fmt.Sprintf(
+		csiPolicyConfigFormat, controllerSock, utils.GetTempSockFile()))...)
+
+	tf := func(config gofig.Config, client types.Client, t *testing.T) {
+		conn, err := grpc.Dial(
+			controllerSock,
+			grpc.WithInsecure(),
+			grpc.WithDialer(
+				func(addr string, timeout time.Duration) (net.Conn, error) {
+					return net.DialTimeout("unix", addr, timeout)
+				}))
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		defer conn.Close()
+
+		mountCap := &csi.VolumeCapability{
+			AccessType: &csi.VolumeCapability_Mount{
+				Mount: &csi.VolumeCapability_MountVolume{},
+			},
+			AccessMode: &csi.VolumeCapability_AccessMode{
+				Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER,
+			},
+		}
+
+		// the volume's type is not one of the policy's allowed types
+		_, err = csi.NewControllerClient(conn).CreateVolume(
+			gocontext.Background(), &csi.CreateVolumeRequest{
+				Name:               "Volume 022",
+				Parameters:         map[string]string{"type": "otherType"},
+				VolumeCapabilities: []*csi.VolumeCapability{mountCap},
+			})
+		if assert.Error(t, err) {
+			assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+		}
+
+		vols, err := client.API().VolumesByService(
+			nil, vfs.Name, types.VolAttNone)
+		assert.NoError(t, err)
+		for _, v := range vols {
+			assert.NotEqual(t, "Volume 022", v.Name)
+		}
+	}
+	apitests.Run(t, vfs.Name, tc, tf)
+}
+
+func TestCSITCPEndpoint(t *testing.T) {
+	tf := func(config gofig.Config, client types.Client, t *testing.T) {
+		tcpConfig := registry.NewConfig()
+		err := tcpConfig.ReadConfig(
+			bytes.NewReader([]byte(csiTCPConfigYAML)))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// the plug-in is only served on unix sockets since its services are
+		// not authenticated
+		_, _, err = apicsi.ServeNode(context.Background(), client, tcpConfig)
+		assert.Error(t, err)
+	}
+	apitests.Run(t, vfs.Name, newTestConfig(t), tf)
+}
+
+const mountStateConfigFormat = `
+libstorage:
+  integration:
+    volume:
+      operations:
+        mount:
+          stateFile: %s
+`
+
+func newTestConfigWithMountState(t *testing.T) []byte {
+	tc := newTestConfig(t)
+	d, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	func() {
+		testDirsLock.Lock()
+		defer testDirsLock.Unlock()
+		testDirs = append(testDirs, d)
+	}()
+	return append(tc, []byte(fmt.Sprintf(
+		mountStateConfigFormat, path.Join(d, "mounts.json")))...)
+}
+
 func removeTestDirs() {
 	testDirsLock.RLock()
 	defer testDirsLock.RUnlock()
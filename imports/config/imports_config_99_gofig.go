@@ -9,6 +9,8 @@ import (
 	log "github.com/Sirupsen/logrus"
 	gofigCore "github.com/akutz/gofig"
 	gofig "github.com/akutz/gofig/types"
+
+	"github.com/codedellemc/libstorage/api/registry"
 	"github.com/codedellemc/libstorage/api/types"
 )
 
@@ -69,18 +71,62 @@ func init() {
 	rk(gofig.Int, 300, "", types.ConfigHTTPReadTimeout)
 	rk(gofig.String, types.LSX.String(), "", types.ConfigExecutorPath)
 	rk(gofig.Bool, false, "", types.ConfigExecutorNoDownload)
+	rk(gofig.String, "5m", "", types.ConfigExecutorLockTimeout)
 	rk(gofig.Bool, false, "", types.ConfigIgVolOpsMountPreempt)
+	rk(gofig.String, "", "", types.ConfigIgVolOpsMountStateFile)
 	rk(gofig.Bool, false, "", types.ConfigIgVolOpsCreateDisable)
 	rk(gofig.Bool, false, "", types.ConfigIgVolOpsRemoveDisable)
 	rk(gofig.Bool, false, "", types.ConfigIgVolOpsUnmountIgnoreUsed)
+	rk(gofig.String, "libstorage", "", types.ConfigIgPluginName)
+	rk(gofig.String, "/run/docker/plugins", "", types.ConfigIgPluginPath)
+	rk(gofig.String, "global", "", types.ConfigIgPluginScope)
 	rk(gofig.Bool, true, "", types.ConfigIgVolOpsPathCacheEnabled)
 	rk(gofig.Bool, true, "", types.ConfigIgVolOpsPathCacheAsync)
 	rk(gofig.String, "30m", "", types.ConfigClientCacheInstanceID)
+	rk(gofig.Bool, false, "", types.ConfigClientGrowFS)
 	rk(gofig.String, "30s", "", types.ConfigDeviceAttachTimeout)
 	rk(gofig.Int, 0, "", types.ConfigDeviceScanType)
 	rk(gofig.Bool, false, "", types.ConfigEmbedded)
 	rk(gofig.String, "1m", "", types.ConfigServerTasksExeTimeout)
 	rk(gofig.String, "0s", "", types.ConfigServerTasksLogTimeout)
+	rk(gofig.String, "memory", "", types.ConfigServerTasksStoreType)
+	rk(gofig.String, "", "", types.ConfigServerTasksStorePath)
+	rk(gofig.Bool, false, "", types.ConfigServerAuthEnabled)
+	rk(gofig.String, "", "", types.ConfigServerAuthJWTKey)
+	rk(gofig.String, "", "", types.ConfigServerAuthJWTKeyFile)
+	rk(gofig.String, "", "", types.ConfigClientAuthToken)
+	rk(gofig.String, "", "", types.ConfigServerAdminTokens)
+	rk(gofig.String, "", "", types.ConfigServerAdminTokenFile)
+	rk(gofig.Bool, false, "", types.ConfigServerAuditEnabled)
+	rk(gofig.String, "file", "", types.ConfigServerAuditSink)
+	rk(gofig.Int, 1000, "", types.ConfigServerAuditBufferSize)
+	rk(gofig.String, "", "", types.ConfigServerAuditFilePath)
+	rk(gofig.Int, 100, "", types.ConfigServerAuditFileMaxSize)
+	rk(gofig.Int, 5, "", types.ConfigServerAuditFileMaxBackups)
+	rk(gofig.String, "", "", types.ConfigServerAuditSyslogNetwork)
+	rk(gofig.String, "", "", types.ConfigServerAuditSyslogAddress)
+	rk(gofig.String, "libstorage", "", types.ConfigServerAuditSyslogTag)
+	rk(gofig.Int, 0, "", types.ConfigPolicyMaxVolumes)
+	rk(gofig.Int, 0, "", types.ConfigPolicyMaxCapacity)
+	rk(gofig.String, "", "", types.ConfigPolicyTypes)
+	rk(gofig.Int, 0, "", types.ConfigPolicyMaxIOPS)
+	rk(gofig.String, "", "", types.ConfigPolicyRequiredFields)
+	rk(gofig.String, "30s", "", types.ConfigListTimeout)
+	rk(gofig.String, "libstorage.codedellemc.com", "", types.ConfigCSIName)
+	rk(gofig.String, "", "", types.ConfigCSIService)
+	rk(gofig.String, "", "", types.ConfigCSIControllerEndpoint)
+	rk(gofig.String, "", "", types.ConfigCSINodeEndpoint)
 
 	gofigCore.Register(r)
+
+	registry.RegisterSecretConfigKeys(
+		types.ConfigServerAuthTokens,
+		types.ConfigServerAuthJWTKey,
+		types.ConfigServerAdminTokens,
+		types.ConfigClientAuthToken)
+
+	// the linux OS driver reads a LUKS key from the environment variable
+	// named by this key, and the driver is not imported by the server
+	registry.RegisterSecretEnvVarConfigKey(
+		"linux.luks.keyenv", "LIBSTORAGE_LUKS_KEY")
 }
@@ -4,6 +4,7 @@ package executors
 
 import (
 	// load the storage executors
+	_ "github.com/codedellemc/libstorage/drivers/storage/cinder/executor"
 	_ "github.com/codedellemc/libstorage/drivers/storage/ebs/executor"
 	_ "github.com/codedellemc/libstorage/drivers/storage/efs/executor"
 	_ "github.com/codedellemc/libstorage/drivers/storage/isilon/executor"
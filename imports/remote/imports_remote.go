@@ -4,6 +4,7 @@ package remote
 
 import (
 	// import to load
+	_ "github.com/codedellemc/libstorage/drivers/storage/cinder/storage"
 	_ "github.com/codedellemc/libstorage/drivers/storage/ebs/storage"
 	_ "github.com/codedellemc/libstorage/drivers/storage/efs/storage"
 	_ "github.com/codedellemc/libstorage/drivers/storage/isilon/storage"